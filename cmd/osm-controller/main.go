@@ -37,13 +37,17 @@ import (
 	"k8c.io/operating-system-manager/pkg/containerruntime"
 	"k8c.io/operating-system-manager/pkg/controllers/osc"
 	"k8c.io/operating-system-manager/pkg/controllers/osp"
+	"k8c.io/operating-system-manager/pkg/crd/migration"
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	osmv1beta1 "k8c.io/operating-system-manager/pkg/crd/osm/v1beta1"
+	"k8c.io/operating-system-manager/pkg/credentialprovider"
 	"k8c.io/operating-system-manager/pkg/generator"
 	osmlog "k8c.io/operating-system-manager/pkg/log"
 	providerconfig "k8c.io/operating-system-manager/pkg/providerconfig/config"
 	"k8c.io/operating-system-manager/pkg/resources/reconciling"
 	"k8c.io/operating-system-manager/pkg/util/certificate"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -82,9 +86,17 @@ type options struct {
 	nodeInsecureRegistries             string
 	nodeRegistryMirrors                string
 	nodeRegistryCredentialsSecret      string
+	nodeRegistryCertificatesSecret     string
 	nodeContainerdRegistryMirrors      containerruntime.RegistryMirrorsFlags
+	nodeContainerdRuntimeHandlers      string
 	deviceOwnershipFromSecurityContext bool
 
+	// Flags for image credential providers of the kubelet
+	nodeImageCredentialProvider          bool
+	nodeImageCredentialProviderBinaryURL string
+	nodeImageCredentialProviderCustom    string
+	nodeRegistryCredentialsMode          string
+
 	// Flags for proxy
 	nodeHTTPProxy string
 	nodeNoProxy   string
@@ -100,7 +112,9 @@ var scheme = runtime.NewScheme()
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(osmv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(osmv1beta1.AddToScheme(scheme))
 	utilruntime.Must(clusterv1alpha1.AddToScheme(scheme))
 }
 
@@ -118,7 +132,7 @@ func main() {
 	flag.StringVar(&opt.workerClusterKubeconfig, "worker-cluster-kubeconfig", "", "Path to kubeconfig of cluster where provisioning secrets are created")
 	flag.IntVar(&opt.workerCount, "worker-count", 10, "Number of workers which process reconciliation in parallel.")
 	flag.StringVar(&opt.namespace, "namespace", "", "The namespace where the OSC controller will run.")
-	flag.StringVar(&opt.containerRuntime, "container-runtime", "containerd", "container runtime to deploy.")
+	flag.StringVar(&opt.containerRuntime, "container-runtime", "containerd", "container runtime to deploy, either containerd or crio. Machine deployments can override it with the k8c.io/container-runtime annotation.")
 	flag.BoolVar(&opt.externalCloudProvider, "external-cloud-provider", false, "cloud-provider Kubelet flag set to external.")
 	flag.StringVar(&opt.clusterDNSIPs, "cluster-dns", "10.10.10.10", "Comma-separated list of DNS server IP address.")
 	flag.StringVar(&opt.pauseImage, "pause-image", "", "pause image to use in Kubelet.")
@@ -132,7 +146,14 @@ func main() {
 	flag.StringVar(&opt.nodeRegistryMirrors, "node-registry-mirrors", "", "Comma separated list of Docker image mirrors")
 	flag.BoolVar(&opt.deviceOwnershipFromSecurityContext, "device-ownership-from-security-context", false, "Enable non-root device usage")
 	flag.Var(&opt.nodeContainerdRegistryMirrors, "node-containerd-registry-mirrors", "Configure registry mirrors endpoints. Can be used multiple times to specify multiple mirrors. Example: `-node-containerd-registry-mirrors myregistry.tld=https://another.host.tld/v2/project?kubermatic=override_path%3Dtrue`")
-	flag.StringVar(&opt.nodeRegistryCredentialsSecret, "node-registry-credentials-secret", "", "A Secret object reference, that contains auth info for image registry in namespace/secret-name form, example: kube-system/registry-credentials. See doc at https://github.com/kubermaric/machine-controller/blob/main/docs/registry-authentication.md")
+	flag.StringVar(&opt.nodeContainerdRuntimeHandlers, "node-containerd-runtime-handlers", "", "Comma separated list of additional containerd runtime handlers, either runsc, kata, wasmtime or name=runtime_type, followed by optional semicolon separated option.<key>=<value>, pod-annotation=<pattern> and privileged-without-host-devices=<bool> attributes. A RuntimeClass is created for every handler, which schedules pods onto the nodes that the kubelet registers with the label runtime-handler.k8c.io/<handler>=true, OSPs pass the labels to the kubelet with --node-labels={{ .NodeLabels }}. Machine deployments can override them with the k8c.io/container-runtime-handlers annotation. Example: `-node-containerd-runtime-handlers runsc,spin=io.containerd.spin.v2;pod-annotation=spin.io/*`")
+	flag.StringVar(&opt.nodeRegistryCredentialsSecret, "node-registry-credentials-secret", "", "A Secret object reference, that contains auth info for image registry in namespace/secret-name form, example: kube-system/registry-credentials. The secret has to be in the namespace of OSM. See doc at https://github.com/kubermaric/machine-controller/blob/main/docs/registry-authentication.md")
+	flag.StringVar(&opt.nodeRegistryCertificatesSecret, "node-registry-certificates-secret", "", "A Secret object reference, that contains the CA and client certificates of image registries in namespace/secret-name form, example: kube-system/registry-certificates. The secret has to be in the namespace of OSM. The keys are the registry host followed by .ca.crt, .client.crt or .client.key, with an underscore in place of the colon of a port, e.g. registry.example.com_5000.ca.crt")
+
+	flag.BoolVar(&opt.nodeImageCredentialProvider, "node-image-credential-provider", false, "Configure the kubelet image credential provider of the cloud provider, i.e. ecr-credential-provider on AWS, acr-credential-provider on Azure and auth-provider-gcp on GCE, so that images of the registries of the cloud provider are pulled with short-lived credentials of the node.")
+	flag.StringVar(&opt.nodeImageCredentialProviderBinaryURL, "node-image-credential-provider-binary-url", "", "Download URL of the binary of the cloud provider image credential provider, ${arch} is replaced with the architecture of the node. Required on GCE since auth-provider-gcp isn't published as a release binary.")
+	flag.StringVar(&opt.nodeImageCredentialProviderCustom, "node-image-credential-provider-custom", "", "A kubelet image credential provider that is configured for all cloud providers, as comma separated key=value pairs with the keys name, url, match-images, args and cache-duration, lists are separated by semicolons. Example: `-node-image-credential-provider-custom name=my-provider,url=https://example.com/my-provider-${arch},match-images=registry.example.com;*.registry.example.com,args=get-credentials,cache-duration=10m`")
+	flag.StringVar(&opt.nodeRegistryCredentialsMode, "node-registry-credentials-mode", credentialprovider.RegistryCredentialsModeContainerRuntime, "How the credentials of the -node-registry-credentials-secret and of machine deployments are delivered to the nodes. With container-runtime they're written into the config of the container runtime, with credential-provider they're kept out of the OSCs and provisioning secrets, the nodes fetch them into a root-only file while provisioning and the kubelet reads them through an image credential provider. The credentials of the registry of the -pause-image are still written into the config of the container runtime, since the container runtime pulls it itself.")
 
 	flag.StringVar(&opt.healthProbeAddress, "health-probe-address", "127.0.0.1:8085", "The address on which the liveness check on /healthz and readiness check on /readyz will be available")
 	flag.StringVar(&opt.metricsAddress, "metrics-address", "127.0.0.1:8080", "The address on which Prometheus metrics will be available under /metrics")
@@ -165,8 +186,8 @@ func main() {
 		log.Fatal("-namespace is required")
 	}
 
-	if opt.containerRuntime != "containerd" {
-		log.Fatalf("%s not supported; containerd is the only supported container runtimes", opt.containerRuntime)
+	if !containerruntime.IsSupported(opt.containerRuntime) {
+		log.Fatalf("%s not supported; containerd and crio are the only supported container runtimes", opt.containerRuntime)
 	}
 
 	var (
@@ -219,13 +240,26 @@ func main() {
 		PauseImage:                         opt.pauseImage,
 		RegistryMirrors:                    opt.nodeRegistryMirrors,
 		RegistryCredentialsSecret:          opt.nodeRegistryCredentialsSecret,
+		RegistryCertificatesSecret:         opt.nodeRegistryCertificatesSecret,
+		Namespace:                          opt.namespace,
 		DeviceOwnershipFromSecurityContext: opt.deviceOwnershipFromSecurityContext,
+		RuntimeHandlers:                    opt.nodeContainerdRuntimeHandlers,
 	}
 	containerRuntimeConfig, err := containerruntime.BuildConfig(containerRuntimeOpts)
 	if err != nil {
 		log.Fatalf("failed to generate container runtime config: %v", err)
 	}
 
+	credentialProviderConfig, err := credentialprovider.BuildConfig(credentialprovider.Opts{
+		CloudProvider:           opt.nodeImageCredentialProvider,
+		CloudProviderBinaryURL:  opt.nodeImageCredentialProviderBinaryURL,
+		Custom:                  opt.nodeImageCredentialProviderCustom,
+		RegistryCredentialsMode: opt.nodeRegistryCredentialsMode,
+	})
+	if err != nil {
+		log.Fatalf("failed to generate image credential provider config: %v", err)
+	}
+
 	// Create manager with client against in-cluster config
 	mgr, err := createManager(opt)
 	if err != nil {
@@ -290,17 +324,37 @@ func main() {
 	// Instantiate ConfigVarResolver
 	providerconfig.SetConfigVarResolver(context.Background(), workerMgr.GetClient(), opt.namespace)
 
+	// Migrate existing resources to the storage version
+	if err := migration.Add(mgr, log); err != nil {
+		log.Fatal(err)
+	}
+
 	// Setup OSP controller
 	if err := osp.Add(mgr, log, opt.namespace, opt.workerCount, opt.disableDefaultOSPs); err != nil {
 		log.Fatal(err)
 	}
 
+	// The OSC controller watches secrets and OSM resources in the namespace of OSM. The cache is scoped to the namespace,
+	// since OSM is only granted access to secrets and config maps in its own namespace.
+	namespaceCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:            scheme,
+		Mapper:            mgr.GetRESTMapper(),
+		DefaultNamespaces: map[string]cache.Config{opt.namespace: {}},
+	})
+	if err != nil {
+		log.Fatalf("failed to create namespace cache: %v", err)
+	}
+	if err := mgr.Add(namespaceCache); err != nil {
+		log.Fatal("failed to add namespace cache to main mgr", zap.Error(err))
+	}
+
 	// Setup OSC controller
 	if err := osc.Add(
 		workerMgr,
 		log,
 		workerClient,
 		mgr.GetClient(),
+		namespaceCache,
 		bootstrappingManager,
 		caCert,
 		hostCACert,
@@ -315,6 +369,8 @@ func main() {
 		opt.nodeNoProxy,
 		containerRuntimeConfig,
 		opt.nodeRegistryCredentialsSecret,
+		opt.nodeRegistryCertificatesSecret,
+		credentialProviderConfig,
 		parsedKubeletFeatureGates,
 	); err != nil {
 		log.Fatal(err)
@@ -27,24 +27,31 @@ import (
 	mdmutation "k8c.io/operating-system-manager/pkg/admission/machinedeployment/mutation"
 	oscvalidation "k8c.io/operating-system-manager/pkg/admission/operatingsystemconfig/validation"
 	ospvalidation "k8c.io/operating-system-manager/pkg/admission/operatingsystemprofile/validation"
+	"k8c.io/operating-system-manager/pkg/containerruntime"
 	"k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	"k8c.io/operating-system-manager/pkg/crd/osm/v1beta1"
 	osmlog "k8c.io/operating-system-manager/pkg/log"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	ctrlruntimelog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 )
 
 type options struct {
-	namespace string
+	namespace               string
+	workerClusterKubeconfig string
+	containerRuntime        string
 
 	metricsAddr          string
 	enableLeaderElection bool
@@ -60,6 +67,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
 	utilruntime.Must(clusterv1alpha1.AddToScheme(scheme))
 }
 
@@ -74,6 +82,8 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&opt.namespace, "namespace", "", "The namespace where the OSC webhook will run.")
+	flag.StringVar(&opt.workerClusterKubeconfig, "worker-cluster-kubeconfig", "", "Path to kubeconfig of cluster where the machine deployments are created")
+	flag.StringVar(&opt.containerRuntime, "container-runtime", "containerd", "The container runtime that OSM deploys by default, either containerd or crio. It has to match the -container-runtime flag of the OSM controller.")
 	flag.StringVar(&opt.certDir, "cert-dir", "/tmp/k8s-webhook-server/serving-certs",
 		"Directory that contains the server key(tls.key) and certificate(tls.crt).")
 	flag.Parse()
@@ -90,6 +100,9 @@ func main() {
 	if len(opt.namespace) == 0 {
 		log.Fatal("-namespace is required")
 	}
+	if !containerruntime.IsSupported(opt.containerRuntime) {
+		log.Fatalf("%s not supported; containerd and crio are the only supported container runtimes", opt.containerRuntime)
+	}
 	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{
 		WebhookServer: webhook.NewServer(webhook.Options{
 			CertDir: opt.certDir,
@@ -106,10 +119,32 @@ func main() {
 		log.Fatal("failed to create the manager", zap.Error(err))
 	}
 
+	// The parameters config maps of the machine deployments are read from the worker cluster, like the OSC controller
+	// does.
+	var workerClient ctrlruntimeclient.Reader = mgr.GetAPIReader()
+	if opt.workerClusterKubeconfig != "" {
+		workerClusterConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: opt.workerClusterKubeconfig},
+			&clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		workerClient, err = ctrlruntimeclient.New(workerClusterConfig, ctrlruntimeclient.Options{
+			Scheme: scheme,
+		})
+		if err != nil {
+			log.Fatalf("failed to build worker client: %v", err)
+		}
+	}
+
 	// Register webhooks
 	oscvalidation.NewAdmissionHandler(log, scheme).SetupWebhookWithManager(mgr)
 	ospvalidation.NewAdmissionHandler(log, scheme).SetupWebhookWithManager(mgr)
-	mdmutation.NewAdmissionHandler(log, scheme).SetupWebhookWithManager(mgr)
+	mdmutation.NewAdmissionHandler(log, scheme, mgr.GetAPIReader(), workerClient, opt.namespace, opt.containerRuntime).SetupWebhookWithManager(mgr)
+
+	// Register the conversion webhook for the OSM CRDs
+	mgr.GetWebhookServer().Register("/convert", conversion.NewWebhookHandler(scheme, mgr.GetConverterRegistry()))
 
 	// Add health endpoints
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -34,4 +34,31 @@ go run sigs.k8s.io/controller-tools/cmd/controller-gen \
 
 echodate "Generating CustomOperatingSystemProfile CRD"
 CUSTOM_OSP=./hack/kkp/operatingsystemmanager.k8c.io_customoperatingsystemprofiles.yaml
-yq -e -i '.spec.versions = load("./deploy/crd/operatingsystemmanager.k8c.io_operatingsystemprofiles.yaml").spec.versions' $CUSTOM_OSP
+# CustomOperatingSystemProfiles are not served by the conversion webhook, they only support v1alpha1.
+yq -e -i '.spec.versions = [load("./deploy/crd/operatingsystemmanager.k8c.io_operatingsystemprofiles.yaml").spec.versions[] | select(.name == "v1alpha1") | .storage = true]' $CUSTOM_OSP
+
+echodate "Configuring conversion webhook for CRDs"
+CONVERSION='  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        service:
+          name: operating-system-manager-webhook
+          namespace: kube-system
+          path: /convert
+          port: 443
+      conversionReviewVersions:
+      - v1'
+for crd in \
+  ./deploy/crd/operatingsystemmanager.k8c.io_operatingsystemconfigs.yaml \
+  ./deploy/crd/operatingsystemmanager.k8c.io_operatingsystemprofiles.yaml \
+  ./deploy/crd/operatingsystemmanager.k8c.io_operatingsystemprofilefragments.yaml \
+  ./deploy/crd/operatingsystemmanager.k8c.io_operatingsystemconfigoverrides.yaml \
+  ./deploy/crd/operatingsystemmanager.k8c.io_templatelibraries.yaml; do
+  awk -v conversion="$CONVERSION" '
+    { print }
+    /^  annotations:$/ { print "    cert-manager.io/inject-ca-from: kube-system/operating-system-manager-serving-cert" }
+    /^spec:$/ { print conversion }
+  ' "$crd" > "$crd.tmp"
+  mv "$crd.tmp" "$crd"
+done
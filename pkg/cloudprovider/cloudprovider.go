@@ -89,3 +89,18 @@ func KubeletCloudProviderConfig(cloudProvider providerconfig.CloudProvider, exte
 		return false, external, nil
 	}
 }
+
+// GetImageCredentialProviderCloudConfig returns the cloud-config that the image credential provider of the cloud provider
+// reads. Unlike the cloud-config of the kubelet, it's required with external cloud providers as well.
+func GetImageCredentialProviderCloudConfig(pconfig providerconfig.Config) (string, error) {
+	if pconfig.OverwriteCloudConfig != nil {
+		return *pconfig.OverwriteCloudConfig, nil
+	}
+
+	switch osmv1alpha1.CloudProvider(pconfig.CloudProvider) {
+	case osmv1alpha1.CloudProviderAzure:
+		return azure.GetCloudConfig(pconfig)
+	default:
+		return "", nil
+	}
+}
@@ -35,9 +35,14 @@ type Opts struct {
 	InsecureRegistries                 string
 	RegistryMirrors                    string
 	RegistryCredentialsSecret          string
+	RegistryCertificatesSecret         string
 	PauseImage                         string
 	ContainerdRegistryMirrors          RegistryMirrorsFlags
 	DeviceOwnershipFromSecurityContext bool
+	RuntimeHandlers                    string
+	// Namespace is the namespace of OSM. The registry secrets have to be in it, since OSM only watches secrets in its
+	// own namespace.
+	Namespace string
 }
 
 type DockerCfgJSON struct {
@@ -45,22 +50,88 @@ type DockerCfgJSON struct {
 }
 
 func BuildConfig(opts Opts) (Config, error) {
+	insecureRegistries := ParseInsecureRegistries(opts.InsecureRegistries)
+
+	if opts.ContainerdRegistryMirrors == nil {
+		opts.ContainerdRegistryMirrors = make(RegistryMirrorsFlags)
+	}
+
+	registryMirrors, err := ParseRegistryMirrors(opts.RegistryMirrors)
+	if err != nil {
+		return Config{}, err
+	}
+	for registry, mirrors := range registryMirrors {
+		opts.ContainerdRegistryMirrors[registry] = append(opts.ContainerdRegistryMirrors[registry], mirrors...)
+	}
+
+	// Only validate registry credential here
+	if err := validateSecretRef("-node-registry-credentials-secret", opts.RegistryCredentialsSecret, opts.Namespace); err != nil {
+		return Config{}, err
+	}
+
+	if err := validateSecretRef("-node-registry-certificates-secret", opts.RegistryCertificatesSecret, opts.Namespace); err != nil {
+		return Config{}, err
+	}
+
+	runtimeHandlers, err := ParseRuntimeHandlers(opts.RuntimeHandlers)
+	if err != nil {
+		return Config{}, fmt.Errorf("incorrect runtime handlers provided: %w", err)
+	}
+	if len(runtimeHandlers) > 0 && opts.ContainerRuntime != containerdName {
+		return Config{}, fmt.Errorf("runtime handlers are only supported by %s", containerdName)
+	}
+
+	return get(
+		opts.ContainerRuntime,
+		withInsecureRegistries(insecureRegistries),
+		withRegistryMirrors(opts.ContainerdRegistryMirrors),
+		withSandboxImage(opts.PauseImage),
+		withContainerdVersion(opts.ContainerdVersion),
+		withDeviceOwnershipFromSecurityContext(opts.DeviceOwnershipFromSecurityContext),
+		withRuntimeHandlers(runtimeHandlers),
+	), nil
+}
+
+// validateSecretRef ensures that the secret reference of the flag is in 'namespace/secretname' form and that the secret
+// is in the namespace.
+func validateSecretRef(flag, ref, namespace string) error {
+	if ref == "" {
+		return nil
+	}
+
+	secRef := strings.Split(ref, "/")
+	if len(secRef) != 2 {
+		return fmt.Errorf("%s is in incorrect format %q, should be in 'namespace/secretname'", flag, ref)
+	}
+	if secRef[0] != namespace {
+		return fmt.Errorf("%s %q has to be in namespace %q", flag, ref, namespace)
+	}
+
+	return nil
+}
+
+// ParseInsecureRegistries parses a comma separated list of insecure registries.
+func ParseInsecureRegistries(value string) []string {
 	var insecureRegistries []string
-	for registry := range strings.SplitSeq(opts.InsecureRegistries, ",") {
+	for registry := range strings.SplitSeq(value, ",") {
 		if trimmedRegistry := strings.TrimSpace(registry); trimmedRegistry != "" {
 			insecureRegistries = append(insecureRegistries, trimmedRegistry)
 		}
 	}
 
+	return insecureRegistries
+}
+
+// ParseRegistryMirrors parses a comma separated list of registry mirrors. Mirrors are either registry=mirror, or only
+// the mirror for mirrors of docker.io. Mirrors without a scheme default to https.
+func ParseRegistryMirrors(value string) (map[string][]string, error) {
+	registryMirrors := map[string][]string{}
+
 	// we want to match e.g. docker.io=registry.docker-cn.com, having docker.io as the first
 	// match group and registry.docker-cn.com as the second one.
 	registryMirrorRegexp := regexp.MustCompile(`^([a-zA-Z0-9\.-]+)=(.*)`)
 
-	if opts.ContainerdRegistryMirrors == nil {
-		opts.ContainerdRegistryMirrors = make(RegistryMirrorsFlags)
-	}
-
-	for mirror := range strings.SplitSeq(opts.RegistryMirrors, ",") {
+	for mirror := range strings.SplitSeq(value, ",") {
 		if trimmedMirror := strings.TrimSpace(mirror); trimmedMirror != "" {
 			registry := "docker.io"
 
@@ -75,32 +146,14 @@ func BuildConfig(opts Opts) (Config, error) {
 
 			_, err := url.Parse(trimmedMirror)
 			if err != nil {
-				return Config{}, fmt.Errorf("incorrect mirror provided: %w", err)
+				return nil, fmt.Errorf("incorrect mirror provided: %w", err)
 			}
 
-			if opts.ContainerdRegistryMirrors[registry] == nil {
-				opts.ContainerdRegistryMirrors[registry] = make([]string, 0, 1)
-			}
-
-			opts.ContainerdRegistryMirrors[registry] = append(opts.ContainerdRegistryMirrors[registry], trimmedMirror)
-		}
-	}
-
-	// Only validate registry credential here
-	if opts.RegistryCredentialsSecret != "" {
-		if secRef := strings.Split(opts.RegistryCredentialsSecret, "/"); len(secRef) != 2 {
-			return Config{}, fmt.Errorf("-node-registry-credentials-secret is in incorrect format %q, should be in 'namespace/secretname'", opts.RegistryCredentialsSecret)
+			registryMirrors[registry] = append(registryMirrors[registry], trimmedMirror)
 		}
 	}
 
-	return get(
-		opts.ContainerRuntime,
-		withInsecureRegistries(insecureRegistries),
-		withRegistryMirrors(opts.ContainerdRegistryMirrors),
-		withSandboxImage(opts.PauseImage),
-		withContainerdVersion(opts.ContainerdVersion),
-		withDeviceOwnershipFromSecurityContext(opts.DeviceOwnershipFromSecurityContext),
-	), nil
+	return registryMirrors, nil
 }
 
 func GetContainerdAuthConfig(ctx context.Context, client ctrlruntimeclient.Client, registryCredentialsSecret string) (map[string]AuthConfig, error) {
@@ -132,3 +185,69 @@ func GetContainerdAuthConfig(ctx context.Context, client ctrlruntimeclient.Clien
 	}
 	return registryCredentials, nil
 }
+
+const (
+	registryCACertSuffix     = ".ca.crt"
+	registryClientCertSuffix = ".client.crt"
+	registryClientKeySuffix  = ".client.key"
+)
+
+// RegistryCertificates are the PEM encoded certificates that are used to connect to a registry.
+type RegistryCertificates struct {
+	// CA is the CA bundle that is used to verify the certificate of the registry.
+	CA string `json:",omitempty"`
+	// ClientCert and ClientKey are the client certificate and key that are used to authenticate against the registry.
+	ClientCert string `json:",omitempty"`
+	ClientKey  string `json:",omitempty"`
+}
+
+// GetRegistryCertificates returns the certificates of the registries from the secret, which is referenced in
+// namespace/name form. The keys of the secret are the host of the registry followed by ".ca.crt", ".client.crt" or
+// ".client.key". Since keys can't contain colons, the port of the registry is separated by an underscore, e.g.
+// "registry.example.com_5000.ca.crt".
+func GetRegistryCertificates(ctx context.Context, client ctrlruntimeclient.Client, registryCertificatesSecret string) (map[string]RegistryCertificates, error) {
+	registryCertificates := map[string]RegistryCertificates{}
+
+	secRef := strings.SplitN(registryCertificatesSecret, "/", 2)
+	if len(secRef) != 2 {
+		return registryCertificates, nil
+	}
+
+	var certsSecret corev1.Secret
+	if err := client.Get(ctx, types.NamespacedName{Namespace: secRef[0], Name: secRef[1]}, &certsSecret); err != nil {
+		return nil, fmt.Errorf("failed to retrieve registry certificates secret object: %w", err)
+	}
+
+	for key, data := range certsSecret.Data {
+		var suffix string
+		for _, s := range []string{registryCACertSuffix, registryClientCertSuffix, registryClientKeySuffix} {
+			if strings.HasSuffix(key, s) {
+				suffix = s
+				break
+			}
+		}
+		if suffix == "" {
+			return nil, fmt.Errorf("invalid key %q in registry certificates secret, expected <registry>%s, <registry>%s or <registry>%s", key, registryCACertSuffix, registryClientCertSuffix, registryClientKeySuffix)
+		}
+
+		registry := strings.Replace(strings.TrimSuffix(key, suffix), "_", ":", 1)
+		certs := registryCertificates[registry]
+		switch suffix {
+		case registryCACertSuffix:
+			certs.CA = string(data)
+		case registryClientCertSuffix:
+			certs.ClientCert = string(data)
+		case registryClientKeySuffix:
+			certs.ClientKey = string(data)
+		}
+		registryCertificates[registry] = certs
+	}
+
+	for registry, certs := range registryCertificates {
+		if (certs.ClientCert == "") != (certs.ClientKey == "") {
+			return nil, fmt.Errorf("registry %s requires both a client certificate and a client key", registry)
+		}
+	}
+
+	return registryCertificates, nil
+}
@@ -17,9 +17,17 @@ limitations under the License.
 package containerruntime
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestContainerdRegistryMirror(t *testing.T) {
@@ -132,3 +140,161 @@ func TestContainerdRegistryMirror(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildConfigRegistrySecrets(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		opts          Opts
+		expectedError string
+	}{
+		{
+			desc: "secrets in the namespace of OSM",
+			opts: Opts{
+				RegistryCredentialsSecret:  "kube-system/registry-credentials",
+				RegistryCertificatesSecret: "kube-system/registry-certificates",
+			},
+		},
+		{
+			desc: "credentials secret in another namespace",
+			opts: Opts{
+				RegistryCredentialsSecret: "registries/registry-credentials",
+			},
+			expectedError: `-node-registry-credentials-secret "registries/registry-credentials" has to be in namespace "kube-system"`,
+		},
+		{
+			desc: "certificates secret in another namespace",
+			opts: Opts{
+				RegistryCertificatesSecret: "registries/registry-certificates",
+			},
+			expectedError: `-node-registry-certificates-secret "registries/registry-certificates" has to be in namespace "kube-system"`,
+		},
+		{
+			desc: "secret without namespace",
+			opts: Opts{
+				RegistryCredentialsSecret: "registry-credentials",
+			},
+			expectedError: `-node-registry-credentials-secret is in incorrect format "registry-credentials", should be in 'namespace/secretname'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.opts.ContainerRuntime = containerdName
+			tc.opts.Namespace = "kube-system"
+
+			_, err := BuildConfig(tc.opts)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("expected success but got error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Fatalf("expected error %q but got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestGetRegistryCertificates(t *testing.T) {
+	testCases := []struct {
+		desc                 string
+		data                 map[string][]byte
+		expectedCertificates map[string]RegistryCertificates
+		expectedError        string
+	}{
+		{
+			desc: "valid certificates",
+			data: map[string][]byte{
+				"registry.example.com.ca.crt":        []byte("ca"),
+				"mirror.example.com_5000.client.crt": []byte("cert"),
+				"mirror.example.com_5000.client.key": []byte("key"),
+			},
+			expectedCertificates: map[string]RegistryCertificates{
+				"registry.example.com":    {CA: "ca"},
+				"mirror.example.com:5000": {ClientCert: "cert", ClientKey: "key"},
+			},
+		},
+		{
+			desc: "client certificate without key",
+			data: map[string][]byte{
+				"registry.example.com.client.crt": []byte("cert"),
+			},
+			expectedError: "registry registry.example.com requires both a client certificate and a client key",
+		},
+		{
+			desc: "unknown key",
+			data: map[string][]byte{
+				"registry.example.com.crt": []byte("cert"),
+			},
+			expectedError: `invalid key "registry.example.com.crt" in registry certificates secret`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := ctrlruntimefakeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "registry-certificates"},
+					Data:       tc.data,
+				}).
+				Build()
+
+			certificates, err := GetRegistryCertificates(context.Background(), client, "kube-system/registry-certificates")
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected error %q but got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected success but got error: %v", err)
+			}
+
+			if !reflect.DeepEqual(certificates, tc.expectedCertificates) {
+				t.Errorf("expected to get %v instead got: %v", tc.expectedCertificates, certificates)
+			}
+		})
+	}
+}
+
+func TestConfigWithRegistryOverrides(t *testing.T) {
+	cfg := Config{
+		InsecureRegistries: []string{"insecure.example.com"},
+		RegistryMirrors: map[string][]string{
+			"docker.io": {"https://mirror.example.com"},
+			"quay.io":   {"https://quay.mirror.example.com"},
+		},
+		RegistryCredentials: map[string]AuthConfig{
+			"registry.example.com": {Username: "user", Password: "pass"},
+			"gcr.io":               {Auth: "dXNlcjpwYXNz"},
+		},
+	}
+
+	overridden := cfg.WithRegistryOverrides(
+		map[string][]string{"docker.io": {"https://edge-mirror.example.com"}},
+		[]string{"insecure.example.com", "edge.example.com"},
+		map[string]AuthConfig{"registry.example.com": {Username: "edge", Password: "pass"}},
+	)
+
+	expected := Config{
+		InsecureRegistries: []string{"insecure.example.com", "edge.example.com"},
+		RegistryMirrors: map[string][]string{
+			"docker.io": {"https://edge-mirror.example.com"},
+			"quay.io":   {"https://quay.mirror.example.com"},
+		},
+		RegistryCredentials: map[string]AuthConfig{
+			"registry.example.com": {Username: "edge", Password: "pass"},
+			"gcr.io":               {Auth: "dXNlcjpwYXNz"},
+		},
+	}
+	if !reflect.DeepEqual(overridden, expected) {
+		t.Errorf("expected to get %+v instead got: %+v", expected, overridden)
+	}
+
+	if cfg.RegistryMirrors["docker.io"][0] != "https://mirror.example.com" || len(cfg.InsecureRegistries) != 1 || cfg.RegistryCredentials["registry.example.com"].Username != "user" {
+		t.Errorf("expected the original config to be unchanged, got: %+v", cfg)
+	}
+}
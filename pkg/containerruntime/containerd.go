@@ -31,8 +31,10 @@ type Containerd struct {
 	registryMirrors                    map[string][]string
 	sandboxImage                       string
 	registryCredentials                map[string]AuthConfig
+	registryCertificates               map[string]RegistryCertificates
 	version                            string
 	deviceOwnershipFromSecurityContext bool
+	runtimeHandlers                    []RuntimeHandler
 }
 
 func (eng *Containerd) ConfigFileName() string {
@@ -97,8 +99,10 @@ type containerdCRISettings struct {
 }
 
 type containerdCRIRuntime struct {
-	RuntimeType string `toml:"runtime_type"`
-	Options     any    `toml:"options"`
+	RuntimeType                  string   `toml:"runtime_type"`
+	PodAnnotations               []string `toml:"pod_annotations,omitempty"`
+	PrivilegedWithoutHostDevices bool     `toml:"privileged_without_host_devices,omitempty"`
+	Options                      any      `toml:"options,omitempty"`
 }
 
 type containerdCRIRuncOptions struct {
@@ -131,14 +135,18 @@ type registryHostConfig struct {
 // hostsTomlConfig represents the top-level structure of a hosts.toml file.
 type hostsTomlConfig struct {
 	Server string                     `toml:"server,omitempty"`
+	CA     string                     `toml:"ca,omitempty"`
+	Client [][]string                 `toml:"client,omitempty"`
 	Host   map[string]hostEntryConfig `toml:"host,omitempty"`
 }
 
 // hostEntryConfig represents a single host entry in a hosts.toml file.
 type hostEntryConfig struct {
-	Capabilities []string `toml:"capabilities"`
-	SkipVerify   bool     `toml:"skip_verify,omitempty"`
-	OverridePath bool     `toml:"override_path,omitempty"`
+	Capabilities []string   `toml:"capabilities"`
+	SkipVerify   bool       `toml:"skip_verify,omitempty"`
+	OverridePath bool       `toml:"override_path,omitempty"`
+	CA           string     `toml:"ca,omitempty"`
+	Client       [][]string `toml:"client,omitempty"`
 }
 
 func (eng *Containerd) Config() (string, error) {
@@ -176,17 +184,31 @@ func (eng *Containerd) Config() (string, error) {
 		}
 	}
 
+	runtimes := map[string]containerdCRIRuntime{
+		defaultRuntimeHandler: {
+			RuntimeType: "io.containerd.runc.v2",
+			Options: containerdCRIRuncOptions{
+				SystemdCgroup: true,
+			},
+		},
+	}
+
+	for _, handler := range eng.runtimeHandlers {
+		runtime := containerdCRIRuntime{
+			RuntimeType:                  handler.RuntimeType,
+			PodAnnotations:               handler.PodAnnotations,
+			PrivilegedWithoutHostDevices: handler.PrivilegedWithoutHostDevices,
+		}
+		if len(handler.Options) > 0 {
+			runtime.Options = handler.Options
+		}
+		runtimes[handler.Name] = runtime
+	}
+
 	criRuntimePlugin := containerdCRIRuntimePlugin{
 		DeviceOwnershipFromSecurityContext: eng.deviceOwnershipFromSecurityContext,
 		Containerd: &containerdCRISettings{
-			Runtimes: map[string]containerdCRIRuntime{
-				"runc": {
-					RuntimeType: "io.containerd.runc.v2",
-					Options: containerdCRIRuncOptions{
-						SystemdCgroup: true,
-					},
-				},
-			},
+			Runtimes: runtimes,
 		},
 		CNI: &containerdCRICNIConfig{
 			BinDirs: []string{"/opt/cni/bin"},
@@ -263,9 +285,46 @@ func (eng *Containerd) buildRegistryHostConfigs() map[string]*registryHostConfig
 		configs[registryName].insecure = true
 	}
 
+	// Registries with certificates need a hosts.toml that references them, even without mirrors.
+	for registryName := range eng.registryCertificates {
+		if _, ok := configs[registryName]; !ok {
+			configs[registryName] = &registryHostConfig{}
+		}
+	}
+
 	return configs
 }
 
+// certificatePaths returns the paths of the CA and the client certificate and key of the registry that serves the
+// given URL or host, they're empty if no certificates are configured for the registry.
+func (eng *Containerd) certificatePaths(registryURL string) (string, [][]string) {
+	host := registryURL
+	if u, err := url.Parse(registryURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	certs, ok := eng.registryCertificates[host]
+	if !ok {
+		return "", nil
+	}
+
+	var (
+		ca     string
+		client [][]string
+	)
+	if certs.CA != "" {
+		ca = fmt.Sprintf("/etc/containerd/certs.d/%s/ca.crt", host)
+	}
+	if certs.ClientCert != "" {
+		client = [][]string{{
+			fmt.Sprintf("/etc/containerd/certs.d/%s/client.crt", host),
+			fmt.Sprintf("/etc/containerd/certs.d/%s/client.key", host),
+		}}
+	}
+
+	return ca, client
+}
+
 // RegistryHostConfigs returns a map of file path to file content for containerd
 // registry host configuration files. Each key is a path like
 // "/etc/containerd/certs.d/<registry>/hosts.toml" and the value is the TOML content.
@@ -285,9 +344,11 @@ func (eng *Containerd) RegistryHostConfigs() (map[string]string, error) {
 	for _, registryName := range registryNames {
 		regCfg := configs[registryName]
 
-		// Skip registries that have no mirrors and are not insecure —
+		_, hasCertificates := eng.registryCertificates[registryName]
+
+		// Skip registries that have no mirrors, are not insecure and have no certificates —
 		// a hosts.toml with only a server URL adds no value over containerd defaults.
-		if len(regCfg.endpoints) == 0 && !regCfg.insecure {
+		if len(regCfg.endpoints) == 0 && !regCfg.insecure && !hasCertificates {
 			continue
 		}
 
@@ -311,13 +372,19 @@ func (eng *Containerd) RegistryHostConfigs() (map[string]string, error) {
 			Server: serverURL,
 			Host:   make(map[string]hostEntryConfig),
 		}
+		if serverURL != "" {
+			hostsCfg.CA, hostsCfg.Client = eng.certificatePaths(serverURL)
+		}
 
 		// Add per-endpoint mirror host entries.
 		for _, endpoint := range regCfg.endpoints {
+			ca, client := eng.certificatePaths(endpoint.url)
 			hostsCfg.Host[endpoint.url] = hostEntryConfig{
 				Capabilities: []string{"pull", "resolve"},
 				OverridePath: endpoint.overridePath,
 				SkipVerify:   regCfg.insecure,
+				CA:           ca,
+				Client:       client,
 			}
 		}
 
@@ -327,6 +394,8 @@ func (eng *Containerd) RegistryHostConfigs() (map[string]string, error) {
 			hostsCfg.Host[serverURL] = hostEntryConfig{
 				Capabilities: []string{"pull", "resolve"},
 				SkipVerify:   regCfg.insecure,
+				CA:           hostsCfg.CA,
+				Client:       hostsCfg.Client,
 			}
 		}
 
@@ -345,6 +414,17 @@ func (eng *Containerd) RegistryHostConfigs() (map[string]string, error) {
 		result[filePath] = output
 	}
 
+	// The certificates are written next to the hosts.toml of their registry.
+	for host, certs := range eng.registryCertificates {
+		if certs.CA != "" {
+			result[fmt.Sprintf("/etc/containerd/certs.d/%s/ca.crt", host)] = certs.CA
+		}
+		if certs.ClientCert != "" {
+			result[fmt.Sprintf("/etc/containerd/certs.d/%s/client.crt", host)] = certs.ClientCert
+			result[fmt.Sprintf("/etc/containerd/certs.d/%s/client.key", host)] = certs.ClientKey
+		}
+	}
+
 	return result, nil
 }
 
@@ -194,6 +194,35 @@ func TestContainerd_Configs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "runtime handlers",
+			eng: &Containerd{
+				runtimeHandlers: []RuntimeHandler{
+					knownRuntimeHandlers["runsc"],
+					knownRuntimeHandlers["kata"],
+					{Name: "spin", RuntimeType: "io.containerd.spin.v2"},
+				},
+			},
+		},
+		{
+			name: "registry certificates",
+			eng: &Containerd{
+				registryCertificates: map[string]RegistryCertificates{
+					"registry.example.com:5000": {CA: "registry-ca\n", ClientCert: "registry-cert\n", ClientKey: "registry-key\n"},
+				},
+			},
+		},
+		{
+			name: "registry certificates of mirror",
+			eng: &Containerd{
+				registryMirrors: map[string][]string{
+					"docker.io": {"https://mirror.example.com"},
+				},
+				registryCertificates: map[string]RegistryCertificates{
+					"mirror.example.com": {CA: "mirror-ca\n"},
+				},
+			},
+		},
 		{
 			name: "mixed registries",
 			eng: &Containerd{
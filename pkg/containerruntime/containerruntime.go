@@ -16,10 +16,26 @@ limitations under the License.
 
 package containerruntime
 
+import (
+	"maps"
+	"slices"
+)
+
 const (
 	containerdName = "containerd"
+	crioName       = "crio"
 )
 
+// IsSupported checks if the container runtime is supported.
+func IsSupported(containerRuntime string) bool {
+	switch containerRuntime {
+	case containerdName, crioName:
+		return true
+	default:
+		return false
+	}
+}
+
 type Engine interface {
 	KubeletFlags() []string
 	ConfigFileName() string
@@ -28,7 +44,8 @@ type Engine interface {
 	AuthConfig() (string, error)
 	String() string
 	// RegistryHostConfigs returns a map of file path to file content
-	// for containerd registry host configuration files under /etc/containerd/certs.d/.
+	// for the registry configuration files of the container runtime, e.g.
+	// the hosts.toml files of containerd under /etc/containerd/certs.d/.
 	RegistryHostConfigs() (map[string]string, error)
 }
 
@@ -64,9 +81,14 @@ func withDeviceOwnershipFromSecurityContext(deviceOwnershipFromSecurityContext b
 	}
 }
 
-func get(_ string, opts ...Opt) Config {
-	cfg := Config{}
-	cfg.Containerd = &Containerd{}
+func withRuntimeHandlers(handlers []RuntimeHandler) Opt {
+	return func(cfg *Config) {
+		cfg.RuntimeHandlers = handlers
+	}
+}
+
+func get(containerRuntimeName string, opts ...Opt) Config {
+	cfg := Config{}.WithContainerRuntime(containerRuntimeName)
 
 	for _, o := range opts {
 		o(&cfg)
@@ -77,6 +99,7 @@ func get(_ string, opts ...Opt) Config {
 
 type Config struct {
 	Containerd                         *Containerd           `json:",omitempty"`
+	CRIO                               *CRIO                 `json:",omitempty"`
 	InsecureRegistries                 []string              `json:",omitempty"`
 	RegistryMirrors                    map[string][]string   `json:",omitempty"`
 	RegistryCredentials                map[string]AuthConfig `json:",omitempty"`
@@ -85,6 +108,11 @@ type Config struct {
 	ContainerLogMaxSize                string                `json:",omitempty"`
 	ContainerdVersion                  string                `json:",omitempty"`
 	DeviceOwnershipFromSecurityContext bool                  `json:",omitempty"`
+	// RuntimeHandlers are the runtime handlers that are configured in addition to runc, they're only supported by
+	// containerd.
+	RuntimeHandlers []RuntimeHandler `json:",omitempty"`
+	// RegistryCertificates are the CA and client certificates of the registries, keyed by the host of the registry.
+	RegistryCertificates map[string]RegistryCertificates `json:",omitempty"`
 }
 
 // AuthConfig is a COPY of github.com/containerd/containerd/pkg/cri/config.AuthConfig.
@@ -102,18 +130,82 @@ type AuthConfig struct {
 	IdentityToken string `toml:"identitytoken,omitempty" json:"identitytoken,omitempty"`
 }
 
+// WithContainerRuntime returns a copy of the config that uses the given container runtime, the registry settings are
+// kept. Unknown container runtimes fall back to containerd.
+func (cfg Config) WithContainerRuntime(containerRuntimeName string) Config {
+	cfg.Containerd = nil
+	cfg.CRIO = nil
+
+	switch containerRuntimeName {
+	case crioName:
+		cfg.CRIO = &CRIO{}
+	default:
+		cfg.Containerd = &Containerd{}
+	}
+
+	return cfg
+}
+
+// WithRegistryOverrides returns a copy of the config with the registry settings of a single pool of nodes. The mirrors
+// replace the configured mirrors of the same registries, insecure registries are added to the configured ones and
+// credentials take precedence over the configured credentials of the same registries.
+func (cfg Config) WithRegistryOverrides(registryMirrors map[string][]string, insecureRegistries []string, registryCredentials map[string]AuthConfig) Config {
+	if len(registryMirrors) > 0 {
+		mirrors := make(map[string][]string, len(cfg.RegistryMirrors)+len(registryMirrors))
+		maps.Copy(mirrors, cfg.RegistryMirrors)
+		maps.Copy(mirrors, registryMirrors)
+		cfg.RegistryMirrors = mirrors
+	}
+
+	if len(insecureRegistries) > 0 {
+		insecure := slices.Clone(cfg.InsecureRegistries)
+		for _, registry := range insecureRegistries {
+			if !slices.Contains(insecure, registry) {
+				insecure = append(insecure, registry)
+			}
+		}
+		cfg.InsecureRegistries = insecure
+	}
+
+	if len(registryCredentials) > 0 {
+		credentials := make(map[string]AuthConfig, len(cfg.RegistryCredentials)+len(registryCredentials))
+		maps.Copy(credentials, cfg.RegistryCredentials)
+		maps.Copy(credentials, registryCredentials)
+		cfg.RegistryCredentials = credentials
+	}
+
+	return cfg
+}
+
 func (cfg Config) String() string {
+	if cfg.CRIO != nil {
+		return crioName
+	}
+
 	return containerdName
 }
 
 func (cfg Config) Engine() Engine {
+	if cfg.CRIO != nil {
+		return &CRIO{
+			insecureRegistries:                 cfg.InsecureRegistries,
+			registryMirrors:                    cfg.RegistryMirrors,
+			sandboxImage:                       cfg.SandboxImage,
+			registryCredentials:                cfg.RegistryCredentials,
+			registryCertificates:               cfg.RegistryCertificates,
+			deviceOwnershipFromSecurityContext: cfg.DeviceOwnershipFromSecurityContext,
+		}
+	}
+
 	containerd := &Containerd{
 		insecureRegistries:                 cfg.InsecureRegistries,
 		registryMirrors:                    cfg.RegistryMirrors,
 		sandboxImage:                       cfg.SandboxImage,
 		registryCredentials:                cfg.RegistryCredentials,
+		registryCertificates:               cfg.RegistryCertificates,
 		version:                            cfg.ContainerdVersion,
 		deviceOwnershipFromSecurityContext: cfg.DeviceOwnershipFromSecurityContext,
+		runtimeHandlers:                    cfg.RuntimeHandlers,
 	}
 	return containerd
 }
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	crioAuthFileName       = "/etc/crio/auth.json"
+	crioRegistriesFileName = "/etc/containers/registries.conf.d/10-osm.conf"
+)
+
+type CRIO struct {
+	insecureRegistries                 []string
+	registryMirrors                    map[string][]string
+	sandboxImage                       string
+	registryCredentials                map[string]AuthConfig
+	registryCertificates               map[string]RegistryCertificates
+	deviceOwnershipFromSecurityContext bool
+}
+
+// ConfigFileName returns the name of the drop-in, the main crio.conf of the package is left untouched.
+func (eng *CRIO) ConfigFileName() string {
+	return "/etc/crio/crio.conf.d/10-osm.conf"
+}
+
+// AuthConfig returns the registry credentials in the format of the docker config json, which CRI-O reads from its
+// global auth file. It's empty if no credentials are configured.
+func (eng *CRIO) AuthConfig() (string, error) {
+	if len(eng.registryCredentials) == 0 {
+		return "", nil
+	}
+
+	// The auth file is keyed by registry host, docker config json keys can be full URLs, e.g. "https://gcr.io".
+	auths := make(map[string]AuthConfig, len(eng.registryCredentials))
+	for registry, auth := range eng.registryCredentials {
+		host := registry
+		if u, err := url.Parse(registry); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		auths[host] = auth
+	}
+
+	b, err := json.Marshal(DockerCfgJSON{Auths: auths})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode registry credentials: %w", err)
+	}
+
+	return string(b), nil
+}
+
+func (eng *CRIO) AuthConfigFileName() string {
+	return crioAuthFileName
+}
+
+func (eng *CRIO) KubeletFlags() []string {
+	return []string{
+		"--container-runtime-endpoint=unix:///var/run/crio/crio.sock",
+	}
+}
+
+func (eng *CRIO) String() string {
+	return crioName
+}
+
+type crioConfigManifest struct {
+	CRIO crioConfig `toml:"crio"`
+}
+
+type crioConfig struct {
+	Image   crioImageConfig   `toml:"image"`
+	Runtime crioRuntimeConfig `toml:"runtime"`
+	Network crioNetworkConfig `toml:"network"`
+	Metrics crioMetricsConfig `toml:"metrics"`
+}
+
+type crioImageConfig struct {
+	PauseImage     string `toml:"pause_image,omitempty"`
+	GlobalAuthFile string `toml:"global_auth_file,omitempty"`
+}
+
+type crioRuntimeConfig struct {
+	CgroupManager                      string `toml:"cgroup_manager"`
+	ConmonCgroup                       string `toml:"conmon_cgroup"`
+	DeviceOwnershipFromSecurityContext bool   `toml:"device_ownership_from_security_context"`
+}
+
+type crioNetworkConfig struct {
+	NetworkDir string   `toml:"network_dir"`
+	PluginDirs []string `toml:"plugin_dirs"`
+}
+
+type crioMetricsConfig struct {
+	EnableMetrics bool   `toml:"enable_metrics"`
+	MetricsHost   string `toml:"metrics_host"`
+	MetricsPort   int    `toml:"metrics_port"`
+}
+
+// crioRegistriesConfig represents a containers-registries.conf(5) drop-in.
+type crioRegistriesConfig struct {
+	Registries []crioRegistry `toml:"registry"`
+}
+
+type crioRegistry struct {
+	Prefix   string               `toml:"prefix"`
+	Location string               `toml:"location"`
+	Insecure bool                 `toml:"insecure,omitempty"`
+	Mirrors  []crioRegistryMirror `toml:"mirror,omitempty"`
+}
+
+type crioRegistryMirror struct {
+	Location string `toml:"location"`
+	Insecure bool   `toml:"insecure,omitempty"`
+}
+
+func (eng *CRIO) Config() (string, error) {
+	cfg := crioConfigManifest{
+		CRIO: crioConfig{
+			Image: crioImageConfig{
+				PauseImage: eng.sandboxImage,
+			},
+			Runtime: crioRuntimeConfig{
+				CgroupManager:                      "systemd",
+				ConmonCgroup:                       "pod",
+				DeviceOwnershipFromSecurityContext: eng.deviceOwnershipFromSecurityContext,
+			},
+			Network: crioNetworkConfig{
+				NetworkDir: "/etc/cni/net.d",
+				PluginDirs: []string{"/opt/cni/bin"},
+			},
+			Metrics: crioMetricsConfig{
+				// metrics available at http://127.0.0.1:9537/metrics
+				EnableMetrics: true,
+				MetricsHost:   "127.0.0.1",
+				MetricsPort:   9537,
+			},
+		},
+	}
+
+	if len(eng.registryCredentials) > 0 {
+		cfg.CRIO.Image.GlobalAuthFile = crioAuthFileName
+	}
+
+	var buf strings.Builder
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	err := enc.Encode(cfg)
+
+	return buf.String(), err
+}
+
+// RegistryHostConfigs returns the registries.conf drop-in with the registry mirrors and insecure registries, and the
+// certificates of the registries. Unlike containerd, registries.conf has no catch-all registry, so mirrors of "*" and
+// "_default" are not supported and skipped.
+func (eng *CRIO) RegistryHostConfigs() (map[string]string, error) {
+	result := map[string]string{}
+
+	// containers-certs.d(5) picks up the certificates by their directory, they aren't referenced by registries.conf.
+	for host, certs := range eng.registryCertificates {
+		if certs.CA != "" {
+			result[fmt.Sprintf("/etc/containers/certs.d/%s/ca.crt", host)] = certs.CA
+		}
+		if certs.ClientCert != "" {
+			result[fmt.Sprintf("/etc/containers/certs.d/%s/client.cert", host)] = certs.ClientCert
+			result[fmt.Sprintf("/etc/containers/certs.d/%s/client.key", host)] = certs.ClientKey
+		}
+	}
+
+	registries := map[string]*crioRegistry{}
+
+	for registryName, mirrorURLs := range eng.registryMirrors {
+		if registryName == "*" || registryName == "_default" {
+			continue
+		}
+
+		registry := &crioRegistry{Prefix: registryName, Location: registryName}
+		for _, mirrorURL := range mirrorURLs {
+			mirror, err := crioRegistryMirrorFromURL(mirrorURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mirror %q of registry %s: %w", mirrorURL, registryName, err)
+			}
+			registry.Mirrors = append(registry.Mirrors, mirror)
+		}
+		registries[registryName] = registry
+	}
+
+	for _, registryName := range eng.insecureRegistries {
+		registry, ok := registries[registryName]
+		if !ok {
+			registry = &crioRegistry{Prefix: registryName, Location: registryName}
+			registries[registryName] = registry
+		}
+
+		registry.Insecure = true
+		for i := range registry.Mirrors {
+			registry.Mirrors[i].Insecure = true
+		}
+	}
+
+	if len(registries) == 0 {
+		return result, nil
+	}
+
+	// Sort registry names for deterministic output
+	registryNames := make([]string, 0, len(registries))
+	for name := range registries {
+		registryNames = append(registryNames, name)
+	}
+	sort.Strings(registryNames)
+
+	cfg := crioRegistriesConfig{}
+	for _, name := range registryNames {
+		cfg.Registries = append(cfg.Registries, *registries[name])
+	}
+
+	var buf strings.Builder
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if err := enc.Encode(cfg); err != nil {
+		return nil, fmt.Errorf("encoding registries.conf: %w", err)
+	}
+
+	result[crioRegistriesFileName] = buf.String()
+
+	return result, nil
+}
+
+// crioRegistryMirrorFromURL converts a mirror URL to a registries.conf mirror. Mirror locations have no scheme, plain
+// http mirrors are marked as insecure. The location is the host and the repository namespace of the mirror, so the
+// API prefix of mirrors with the kubermatic override_path parameter is dropped.
+func crioRegistryMirrorFromURL(mirrorURL string) (crioRegistryMirror, error) {
+	parsedURL, err := url.Parse(mirrorURL)
+	if err != nil {
+		return crioRegistryMirror{}, err
+	}
+
+	var overridePath bool
+	if query := parsedURL.Query(); query.Has("kubermatic") {
+		if kubermaticValues, err := url.ParseQuery(query.Get("kubermatic")); err == nil {
+			overridePath, _ = strconv.ParseBool(kubermaticValues.Get("override_path"))
+		}
+	}
+
+	// Mirrors without a scheme are parsed as a path.
+	if parsedURL.Host == "" {
+		return crioRegistryMirror{Location: strings.TrimSuffix(parsedURL.Path, "/")}, nil
+	}
+
+	path := parsedURL.Path
+	if overridePath {
+		path = strings.TrimPrefix(path, "/v2")
+	}
+
+	return crioRegistryMirror{
+		Location: parsedURL.Host + strings.TrimSuffix(path, "/"),
+		Insecure: parsedURL.Scheme == "http",
+	}, nil
+}
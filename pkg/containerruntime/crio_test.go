@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	testUtil "k8c.io/operating-system-manager/pkg/test/util"
+)
+
+func TestCRIO_Configs(t *testing.T) {
+	tests := []struct {
+		name string
+		eng  *CRIO
+	}{
+		{
+			name: "simple",
+			eng:  &CRIO{},
+		},
+		{
+			name: "sandbox image and device ownership",
+			eng: &CRIO{
+				sandboxImage:                       "registry.k8s.io/pause:3.10",
+				deviceOwnershipFromSecurityContext: true,
+			},
+		},
+		{
+			name: "registry mirrors",
+			eng: &CRIO{
+				registryMirrors: map[string][]string{
+					"docker.io": {"https://registry-v1.docker.io", "http://registry.docker-cn.com"},
+					"quay.io":   {"https://my-quay-io-mirror.example.com"},
+				},
+			},
+		},
+		{
+			name: "override path",
+			eng: &CRIO{
+				registryMirrors: map[string][]string{
+					"docker.io": {"https://harbor.example.com/v2/proxy-docker-io?kubermatic=override_path%3Dtrue"},
+				},
+			},
+		},
+		{
+			name: "insecure registry with mirror",
+			eng: &CRIO{
+				insecureRegistries: []string{"insecure.example.com", "other.example.com"},
+				registryMirrors: map[string][]string{
+					"insecure.example.com": {"https://mirror.insecure.example.com"},
+				},
+			},
+		},
+		{
+			name: "wildcard mirror",
+			eng: &CRIO{
+				registryMirrors: map[string][]string{
+					"*": {"https://mirror.example.com"},
+				},
+			},
+		},
+		{
+			name: "registry certificates",
+			eng: &CRIO{
+				registryMirrors: map[string][]string{
+					"docker.io": {"https://mirror.example.com"},
+				},
+				registryCertificates: map[string]RegistryCertificates{
+					"mirror.example.com":        {CA: "mirror-ca\n"},
+					"registry.example.com:5000": {ClientCert: "registry-cert\n", ClientKey: "registry-key\n"},
+				},
+			},
+		},
+		{
+			name: "registry credentials",
+			eng: &CRIO{
+				registryCredentials: map[string]AuthConfig{
+					"https://my-registry.example.com": {Username: "user", Password: "pass"},
+					"gcr.io":                          {Auth: "dXNlcjpwYXNz"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+
+			config, err := tt.eng.Config()
+			if err != nil {
+				t.Fatalf("Config() error = %v", err)
+			}
+			fmt.Fprintf(&buf, "# %s\n", tt.eng.ConfigFileName())
+			buf.WriteString(config)
+
+			authConfig, err := tt.eng.AuthConfig()
+			if err != nil {
+				t.Fatalf("AuthConfig() error = %v", err)
+			}
+			if authConfig != "" {
+				buf.WriteString("---\n")
+				fmt.Fprintf(&buf, "# %s\n", tt.eng.AuthConfigFileName())
+				buf.WriteString(authConfig + "\n")
+			}
+
+			hostConfigs, err := tt.eng.RegistryHostConfigs()
+			if err != nil {
+				t.Fatalf("RegistryHostConfigs() error = %v", err)
+			}
+
+			paths := make([]string, 0, len(hostConfigs))
+			for path := range hostConfigs {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			for _, path := range paths {
+				buf.WriteString("---\n")
+				fmt.Fprintf(&buf, "# %s\n", path)
+				buf.WriteString(hostConfigs[path])
+			}
+
+			testUtil.CompareOutput(t, testUtil.FSGoldenName(t), buf.String(), *update)
+		})
+	}
+}
+
+func TestConfigWithContainerRuntime(t *testing.T) {
+	cfg, err := BuildConfig(Opts{
+		ContainerRuntime:   containerdName,
+		InsecureRegistries: "insecure.example.com",
+		PauseImage:         "registry.k8s.io/pause:3.10",
+	})
+	if err != nil {
+		t.Fatalf("expected success but got error: %v", err)
+	}
+
+	if name := cfg.Engine().String(); name != containerdName {
+		t.Fatalf("expected engine %q, got %q", containerdName, name)
+	}
+
+	crio := cfg.WithContainerRuntime(crioName)
+	if crio.String() != crioName || crio.Engine().String() != crioName {
+		t.Fatalf("expected engine %q, got %q", crioName, crio.Engine().String())
+	}
+
+	config, err := crio.Engine().Config()
+	if err != nil {
+		t.Fatalf("Config() error = %v", err)
+	}
+	if !strings.Contains(config, `pause_image = "registry.k8s.io/pause:3.10"`) {
+		t.Errorf("expected the sandbox image to be kept, got:\n%s", config)
+	}
+
+	if cfg.String() != containerdName {
+		t.Errorf("expected the original config to keep using %q, got %q", containerdName, cfg.String())
+	}
+}
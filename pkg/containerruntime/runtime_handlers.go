@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const defaultRuntimeHandler = "runc"
+
+// RuntimeHandler is an additional runtime of containerd, e.g. for sandboxed workloads. Pods select it through a
+// RuntimeClass with the name of the handler.
+type RuntimeHandler struct {
+	Name        string
+	RuntimeType string
+	// Options are the runtime specific options of the shim.
+	Options map[string]string
+	// PodAnnotations are the annotations of pods that are passed to the runtime.
+	PodAnnotations               []string
+	PrivilegedWithoutHostDevices bool
+}
+
+// knownRuntimeHandlers are the runtime handlers that can be configured by their name only. The shims and their
+// runtimes have to be installed on the nodes, e.g. by the OSP.
+var knownRuntimeHandlers = map[string]RuntimeHandler{
+	// gVisor
+	"runsc": {
+		Name:        "runsc",
+		RuntimeType: "io.containerd.runsc.v1",
+		Options: map[string]string{
+			"TypeUrl": "io.containerd.runsc.v1.options",
+		},
+	},
+	// Kata Containers
+	"kata": {
+		Name:                         "kata",
+		RuntimeType:                  "io.containerd.kata.v2",
+		PodAnnotations:               []string{"io.katacontainers.*"},
+		PrivilegedWithoutHostDevices: true,
+	},
+	// WebAssembly through the runwasi wasmtime shim
+	"wasmtime": {
+		Name:        "wasmtime",
+		RuntimeType: "io.containerd.wasmtime.v1",
+	},
+}
+
+// ParseRuntimeHandlers parses a comma separated list of runtime handlers. Handlers are either the name of a known
+// handler, i.e. runsc, kata or wasmtime, or name=runtime_type for any other shim, followed by optional semicolon
+// separated attributes:
+//   - option.<key>=<value> sets a runtime specific option of the shim,
+//   - pod-annotation=<pattern> passes the matching pod annotations to the runtime, it can be repeated,
+//   - privileged-without-host-devices=<bool> doesn't pass the host devices to privileged containers.
+//
+// Attributes of known handlers override their defaults, e.g.
+// "runsc,spin=io.containerd.spin.v2;pod-annotation=spin.io/*;option.ConfigPath=/etc/spin/config.toml".
+func ParseRuntimeHandlers(value string) ([]RuntimeHandler, error) {
+	var (
+		handlers []RuntimeHandler
+		names    = map[string]struct{}{}
+	)
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		spec, attributes, _ := strings.Cut(entry, ";")
+		var handler RuntimeHandler
+		if name, runtimeType, ok := strings.Cut(spec, "="); ok {
+			if runtimeType == "" {
+				return nil, fmt.Errorf("runtime handler %q has no runtime type", name)
+			}
+			handler = RuntimeHandler{Name: name, RuntimeType: runtimeType}
+		} else {
+			known, ok := knownRuntimeHandlers[spec]
+			if !ok {
+				return nil, fmt.Errorf("unknown runtime handler %q, use name=runtime_type for custom runtime handlers", spec)
+			}
+			handler = known
+			handler.Options = maps.Clone(known.Options)
+			handler.PodAnnotations = slices.Clone(known.PodAnnotations)
+		}
+
+		if attributes != "" {
+			if err := parseRuntimeHandlerAttributes(&handler, attributes); err != nil {
+				return nil, fmt.Errorf("invalid runtime handler %q: %w", handler.Name, err)
+			}
+		}
+
+		if handler.Name == defaultRuntimeHandler {
+			return nil, fmt.Errorf("runtime handler %q is always configured", defaultRuntimeHandler)
+		}
+		// The handler is referenced by RuntimeClasses, so it has to be a DNS label.
+		if errs := validation.IsDNS1123Label(handler.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid runtime handler name %q: %s", handler.Name, strings.Join(errs, ", "))
+		}
+		if _, ok := names[handler.Name]; ok {
+			return nil, fmt.Errorf("runtime handler %q is configured more than once", handler.Name)
+		}
+		names[handler.Name] = struct{}{}
+
+		handlers = append(handlers, handler)
+	}
+
+	return handlers, nil
+}
+
+func parseRuntimeHandlerAttributes(handler *RuntimeHandler, attributes string) error {
+	// Pod annotations replace the ones of known handlers instead of being added to them.
+	var podAnnotations []string
+	for attribute := range strings.SplitSeq(attributes, ";") {
+		attribute = strings.TrimSpace(attribute)
+		if attribute == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(attribute, "=")
+		if !ok {
+			return fmt.Errorf("%q is not a key=value pair", attribute)
+		}
+
+		switch {
+		case strings.HasPrefix(key, "option."):
+			option := strings.TrimPrefix(key, "option.")
+			if option == "" {
+				return fmt.Errorf("%q has no option name", attribute)
+			}
+			if handler.Options == nil {
+				handler.Options = map[string]string{}
+			}
+			handler.Options[option] = val
+		case key == "pod-annotation":
+			if val == "" {
+				return fmt.Errorf("%q has no pattern", attribute)
+			}
+			podAnnotations = append(podAnnotations, val)
+		case key == "privileged-without-host-devices":
+			privileged, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("invalid privileged-without-host-devices %q: %w", val, err)
+			}
+			handler.PrivilegedWithoutHostDevices = privileged
+		default:
+			return fmt.Errorf("unknown attribute %q", key)
+		}
+	}
+
+	if len(podAnnotations) > 0 {
+		handler.PodAnnotations = podAnnotations
+	}
+
+	return nil
+}
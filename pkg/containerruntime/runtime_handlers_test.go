@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerruntime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRuntimeHandlers(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		value            string
+		expectedHandlers []RuntimeHandler
+		expectedError    bool
+	}{
+		{
+			desc:  "no runtime handlers",
+			value: "",
+		},
+		{
+			desc:  "known runtime handlers",
+			value: "runsc, kata,wasmtime",
+			expectedHandlers: []RuntimeHandler{
+				knownRuntimeHandlers["runsc"],
+				knownRuntimeHandlers["kata"],
+				knownRuntimeHandlers["wasmtime"],
+			},
+		},
+		{
+			desc:  "custom runtime handler",
+			value: "spin=io.containerd.spin.v2",
+			expectedHandlers: []RuntimeHandler{
+				{Name: "spin", RuntimeType: "io.containerd.spin.v2"},
+			},
+		},
+		{
+			desc:  "custom runtime handler with attributes",
+			value: "spin=io.containerd.spin.v2;option.ConfigPath=/etc/spin/config.toml;pod-annotation=spin.io/*;pod-annotation=io.kubernetes.cri.*;privileged-without-host-devices=true,runsc",
+			expectedHandlers: []RuntimeHandler{
+				{
+					Name:                         "spin",
+					RuntimeType:                  "io.containerd.spin.v2",
+					Options:                      map[string]string{"ConfigPath": "/etc/spin/config.toml"},
+					PodAnnotations:               []string{"spin.io/*", "io.kubernetes.cri.*"},
+					PrivilegedWithoutHostDevices: true,
+				},
+				knownRuntimeHandlers["runsc"],
+			},
+		},
+		{
+			desc:  "known runtime handler with attributes",
+			value: "kata;privileged-without-host-devices=false;pod-annotation=io.katacontainers.config.*;option.ConfigPath=/opt/kata/configuration.toml",
+			expectedHandlers: []RuntimeHandler{
+				{
+					Name:           "kata",
+					RuntimeType:    "io.containerd.kata.v2",
+					Options:        map[string]string{"ConfigPath": "/opt/kata/configuration.toml"},
+					PodAnnotations: []string{"io.katacontainers.config.*"},
+				},
+			},
+		},
+		{
+			desc:          "unknown runtime handler attribute",
+			value:         "spin=io.containerd.spin.v2;privileged=true",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid runtime handler attribute",
+			value:         "spin=io.containerd.spin.v2;privileged-without-host-devices=maybe",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown runtime handler",
+			value:         "firecracker",
+			expectedError: true,
+		},
+		{
+			desc:          "custom runtime handler without runtime type",
+			value:         "spin=",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid runtime handler name",
+			value:         "Spin_Shim=io.containerd.spin.v2",
+			expectedError: true,
+		},
+		{
+			desc:          "runc",
+			value:         "runc=io.containerd.runc.v2",
+			expectedError: true,
+		},
+		{
+			desc:          "duplicate runtime handler",
+			value:         "runsc,runsc=io.containerd.runsc.v1",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			handlers, err := ParseRuntimeHandlers(tc.value)
+			if tc.expectedError {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected success but got error: %v", err)
+			}
+
+			if !reflect.DeepEqual(handlers, tc.expectedHandlers) {
+				t.Errorf("expected runtime handlers %+v, got %+v", tc.expectedHandlers, handlers)
+			}
+		})
+	}
+}
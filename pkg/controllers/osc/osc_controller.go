@@ -18,11 +18,10 @@ package osc
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net"
+	"slices"
 
 	"go.uber.org/zap"
 
@@ -34,22 +33,30 @@ import (
 	"k8c.io/operating-system-manager/pkg/containerruntime"
 	"k8c.io/operating-system-manager/pkg/controllers/osc/resources"
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	"k8c.io/operating-system-manager/pkg/credentialprovider"
 	"k8c.io/operating-system-manager/pkg/generator"
 	kuberneteshelper "k8c.io/operating-system-manager/pkg/kubernetes"
+	"k8c.io/operating-system-manager/pkg/resources/reconciling"
 
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/tools/record"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlruntimecache "sigs.k8s.io/controller-runtime/pkg/cache"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
@@ -59,8 +66,66 @@ const (
 
 	OperatingSystemConfigVersionAnnotation = "k8c.io/osp-version"
 	OperatingSystemConfigMDHash            = "k8c.io/mdannotations-hash"
+	// OperatingSystemConfigFileContentsHash is the hash of the file contents that the OSP references from secrets and config maps.
+	OperatingSystemConfigFileContentsHash = "k8c.io/file-contents-hash"
+
+	// fileContentReferenceIndex indexes OSPs and fragments by the secrets and config maps that their files reference.
+	fileContentReferenceIndex = "fileContentReference"
+	// OperatingSystemConfigBaseVersionsAnnotation contains the versions of the base OSPs that the OSP extends.
+	OperatingSystemConfigBaseVersionsAnnotation = "k8c.io/osp-base-versions"
+	// OperatingSystemConfigFragmentsHash is the hash of the OperatingSystemProfileFragments that were merged into the OSP.
+	OperatingSystemConfigFragmentsHash = "k8c.io/osp-fragments-hash"
+	// OperatingSystemConfigOverridesHash is the hash of the OperatingSystemConfigOverrides that were applied to the OSC.
+	OperatingSystemConfigOverridesHash = "k8c.io/osc-overrides-hash"
+	// OperatingSystemConfigParametersHash is the hash of the OSP parameter values that the machine deployment sets.
+	OperatingSystemConfigParametersHash = "k8c.io/osp-parameters-hash"
+	// OperatingSystemConfigMDLabelsHash is the hash of the labels of the machine deployment.
+	OperatingSystemConfigMDLabelsHash = "k8c.io/mdlabels-hash"
+	// OperatingSystemConfigTemplateLibraryVersionsAnnotation contains the versions of the TemplateLibraries that the OSP imports
+	// and hashes over their templates.
+	OperatingSystemConfigTemplateLibraryVersionsAnnotation = "k8c.io/template-library-versions"
+	// OperatingSystemConfigRegistryOverridesHash is the hash of the registry settings of the machine deployment.
+	OperatingSystemConfigRegistryOverridesHash = "k8c.io/registry-overrides-hash"
+	// OperatingSystemConfigRegistryCredentialsHash is the hash of the registry credentials that are configured for OSM.
+	OperatingSystemConfigRegistryCredentialsHash = "k8c.io/registry-credentials-hash"
+	// OperatingSystemConfigRegistryCertificatesHash is the hash of the registry certificates that are configured for OSM.
+	OperatingSystemConfigRegistryCertificatesHash = "k8c.io/registry-certificates-hash"
+
+	// runtimeClassManagedByLabel marks the RuntimeClasses that OSM created for runtime handlers. Only these are updated
+	// and deleted, RuntimeClasses that existed before are left as they are.
+	runtimeClassManagedByLabel = "app.kubernetes.io/managed-by"
+	runtimeClassManagedBy      = "operating-system-manager"
 )
 
+// ospRevision identifies the state of an OSP, including everything that is merged into it or applied on top of it, that
+// an OSC is rendered from.
+// The OSC and secrets are rotated if it changes.
+type ospRevision struct {
+	// version is the version of the OSP.
+	version string
+	// baseVersions are the versions of the base OSPs that the OSP extends.
+	baseVersions string
+	// templateLibraryVersions are the versions of the template libraries that the OSP imports.
+	templateLibraryVersions string
+	// fragmentsHash is the hash of the fragments merged into the OSP.
+	fragmentsHash string
+	// overridesHash is the hash of the OSC overrides that apply to the machine deployment.
+	overridesHash string
+	// fileContentsHash is the hash of the file contents that the OSP references from secrets and config maps.
+	fileContentsHash string
+	// parametersHash is the hash of the parameter values that the machine deployment sets.
+	parametersHash string
+	// labelsHash is the hash of the labels of the machine deployment, which are available to templates and selectors.
+	labelsHash string
+	// registryOverridesHash is the hash of the registry settings of the machine deployment, including the referenced
+	// config map and secret.
+	registryOverridesHash string
+	// registryCredentialsHash is the hash of the registry credentials of the -node-registry-credentials-secret.
+	registryCredentialsHash string
+	// registryCertificatesHash is the hash of the registry certificates of the -node-registry-certificates-secret.
+	registryCertificatesHash string
+}
+
 type Reconciler struct {
 	ctrlruntimeclient.Client
 	workerClient ctrlruntimeclient.Client
@@ -70,19 +135,21 @@ type Reconciler struct {
 
 	bootstrappingManager bootstrap.Bootstrap
 
-	namespace                     string
-	containerRuntime              string
-	externalCloudProvider         bool
-	initialTaints                 string
-	generator                     generator.CloudConfigGenerator
-	clusterDNSIPs                 []net.IP
-	caCert                        string
-	hostCACert                    string
-	nodeHTTPProxy                 string
-	nodeNoProxy                   string
-	nodeRegistryCredentialsSecret string
-	containerRuntimeConfig        containerruntime.Config
-	kubeletFeatureGates           map[string]bool
+	namespace                      string
+	containerRuntime               string
+	externalCloudProvider          bool
+	initialTaints                  string
+	generator                      generator.CloudConfigGenerator
+	clusterDNSIPs                  []net.IP
+	caCert                         string
+	hostCACert                     string
+	nodeHTTPProxy                  string
+	nodeNoProxy                    string
+	nodeRegistryCredentialsSecret  string
+	nodeRegistryCertificatesSecret string
+	containerRuntimeConfig         containerruntime.Config
+	credentialProviderConfig       credentialprovider.Config
+	kubeletFeatureGates            map[string]bool
 }
 
 func Add(
@@ -90,6 +157,7 @@ func Add(
 	log *zap.SugaredLogger,
 	workerClient ctrlruntimeclient.Client,
 	client ctrlruntimeclient.Client,
+	clientCache ctrlruntimecache.Cache,
 	bootstrappingManager bootstrap.Bootstrap,
 	caCert string,
 	hostCACert string,
@@ -104,40 +172,275 @@ func Add(
 	nodeNoProxy string,
 	containerRuntimeConfig containerruntime.Config,
 	nodeRegistryCredentialsSecret string,
+	nodeRegistryCertificatesSecret string,
+	credentialProviderConfig credentialprovider.Config,
 	kubeletFeatureGates map[string]bool,
 ) error {
 	reconciler := &Reconciler{
-		log:                           log,
-		workerClient:                  workerClient,
-		Client:                        client,
-		recorder:                      mgr.GetEventRecorderFor(ControllerName),
-		bootstrappingManager:          bootstrappingManager,
-		caCert:                        caCert,
-		hostCACert:                    hostCACert,
-		namespace:                     namespace,
-		generator:                     generator,
-		clusterDNSIPs:                 clusterDNSIPs,
-		containerRuntime:              containerRuntime,
-		initialTaints:                 initialTaints,
-		externalCloudProvider:         externalCloudProvider,
-		nodeHTTPProxy:                 nodeHTTPProxy,
-		nodeNoProxy:                   nodeNoProxy,
-		containerRuntimeConfig:        containerRuntimeConfig,
-		nodeRegistryCredentialsSecret: nodeRegistryCredentialsSecret,
-		kubeletFeatureGates:           kubeletFeatureGates,
-	}
-
-	_, err := builder.ControllerManagedBy(mgr).
+		log:                            log,
+		workerClient:                   workerClient,
+		Client:                         client,
+		recorder:                       mgr.GetEventRecorderFor(ControllerName),
+		bootstrappingManager:           bootstrappingManager,
+		caCert:                         caCert,
+		hostCACert:                     hostCACert,
+		namespace:                      namespace,
+		generator:                      generator,
+		clusterDNSIPs:                  clusterDNSIPs,
+		containerRuntime:               containerRuntime,
+		initialTaints:                  initialTaints,
+		externalCloudProvider:          externalCloudProvider,
+		nodeHTTPProxy:                  nodeHTTPProxy,
+		nodeNoProxy:                    nodeNoProxy,
+		containerRuntimeConfig:         containerRuntimeConfig,
+		nodeRegistryCredentialsSecret:  nodeRegistryCredentialsSecret,
+		nodeRegistryCertificatesSecret: nodeRegistryCertificatesSecret,
+		credentialProviderConfig:       credentialProviderConfig,
+		kubeletFeatureGates:            kubeletFeatureGates,
+	}
+
+	// Only the secrets and config maps that an OSP or fragment references are mapped to machine deployments, which are
+	// found through the index instead of resolving the OSPs of all machine deployments.
+	if err := clientCache.IndexField(context.Background(), &osmv1alpha1.OperatingSystemProfile{}, fileContentReferenceIndex, indexOperatingSystemProfileFileContent); err != nil {
+		return fmt.Errorf("failed to index OperatingSystemProfiles: %w", err)
+	}
+	if err := clientCache.IndexField(context.Background(), &osmv1alpha1.OperatingSystemProfileFragment{}, fileContentReferenceIndex, indexOperatingSystemProfileFragmentFileContent); err != nil {
+		return fmt.Errorf("failed to index OperatingSystemProfileFragments: %w", err)
+	}
+
+	bldr := builder.ControllerManagedBy(mgr).
 		Named(ControllerName).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: workerCount,
 		}).
-		For(&clusterv1alpha1.MachineDeployment{}, builder.WithPredicates(filterMachineDeploymentPredicate())).
-		Build(reconciler)
+		For(&clusterv1alpha1.MachineDeployment{}, builder.WithPredicates(filterMachineDeploymentPredicate()))
+
+	// The registry credentials and certificates secrets live in the namespace of OSM in the cluster of the client, which
+	// isn't necessarily the worker cluster. All machine deployments are reconciled when they change, so that their OSCs
+	// are rotated.
+	var registrySecrets []string
+	for _, secret := range []string{nodeRegistryCredentialsSecret, nodeRegistryCertificatesSecret} {
+		if secret != "" {
+			registrySecrets = append(registrySecrets, secret)
+		}
+	}
+	if len(registrySecrets) > 0 {
+		bldr = bldr.WatchesRawSource(source.Kind(
+			clientCache,
+			&corev1.Secret{},
+			handler.TypedEnqueueRequestsFromMapFunc(reconciler.enqueueMachineDeployments),
+			filterSecretPredicate(registrySecrets...),
+		))
+	}
+
+	// Files can source their content from secrets and config maps in the namespace of the OSP. The machine deployments
+	// whose OSP references them are reconciled when they change, so that their OSCs are rotated.
+	bldr = bldr.
+		WatchesRawSource(source.Kind(
+			clientCache,
+			&corev1.Secret{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+				return reconciler.enqueueMachineDeploymentsUsingFileContent(ctx, secret)
+			}),
+			fileContentReferencedPredicate[*corev1.Secret](clientCache, log),
+		)).
+		WatchesRawSource(source.Kind(
+			clientCache,
+			&corev1.ConfigMap{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, configMap *corev1.ConfigMap) []reconcile.Request {
+				return reconciler.enqueueMachineDeploymentsUsingFileContent(ctx, configMap)
+			}),
+			fileContentReferencedPredicate[*corev1.ConfigMap](clientCache, log),
+		))
+
+	// The parameters of the OSP and the registry settings can be set by config maps and secrets in the namespace of the
+	// machine deployment, which lives in the worker cluster. The machine deployments that name them are reconciled when
+	// they change.
+	bldr = bldr.
+		WatchesRawSource(source.Kind(
+			mgr.GetCache(),
+			&corev1.ConfigMap{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, configMap *corev1.ConfigMap) []reconcile.Request {
+				return reconciler.enqueueMachineDeploymentsReferencing(ctx, configMap,
+					resources.MachineDeploymentParametersConfigMapAnnotation,
+					resources.MachineDeploymentRegistryConfigMapAnnotation,
+				)
+			}),
+		)).
+		WatchesRawSource(source.Kind(
+			mgr.GetCache(),
+			&corev1.Secret{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+				return reconciler.enqueueMachineDeploymentsReferencing(ctx, secret, resources.MachineDeploymentRegistryCredentialsSecretAnnotation)
+			}),
+		))
+
+	// Template libraries are imported by the OSPs of the machine deployments.
+	bldr = bldr.WatchesRawSource(source.Kind(
+		clientCache,
+		&osmv1alpha1.TemplateLibrary{},
+		handler.TypedEnqueueRequestsFromMapFunc(reconciler.enqueueMachineDeploymentsUsingTemplateLibrary),
+	))
+
+	// Overrides are added to the OSCs of the machine deployments they list or select.
+	bldr = bldr.WatchesRawSource(source.Kind(
+		clientCache,
+		&osmv1alpha1.OperatingSystemConfigOverride{},
+		handler.TypedEnqueueRequestsFromMapFunc(reconciler.enqueueMachineDeploymentsUsingOverride),
+	))
+
+	// Fragments are merged into the OSPs of the machine deployments they select. Both the old and the new fragment are
+	// mapped on updates, so that machine deployments that aren't selected anymore are reconciled as well.
+	bldr = bldr.WatchesRawSource(source.Kind(
+		clientCache,
+		&osmv1alpha1.OperatingSystemProfileFragment{},
+		handler.TypedEnqueueRequestsFromMapFunc(reconciler.enqueueMachineDeploymentsUsingFragment),
+	))
+
+	_, err := bldr.Build(reconciler)
 
 	return err
 }
 
+// enqueueMachineDeployments returns requests for all machine deployments that reference an OSP.
+func (r *Reconciler) enqueueMachineDeployments(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := r.workerClient.List(ctx, machineDeployments); err != nil {
+		r.log.Errorw("Failed to list machine deployments", "secret", ctrlruntimeclient.ObjectKeyFromObject(secret), zap.Error(err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, md := range machineDeployments.Items {
+		if md.Annotations[resources.MachineDeploymentOSPAnnotation] == "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: ctrlruntimeclient.ObjectKeyFromObject(&md)})
+	}
+
+	return requests
+}
+
+// enqueueMachineDeploymentsReferencing returns requests for the machine deployments in the namespace of the object
+// that name it in one of the annotations.
+func (r *Reconciler) enqueueMachineDeploymentsReferencing(ctx context.Context, obj ctrlruntimeclient.Object, annotations ...string) []reconcile.Request {
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := r.workerClient.List(ctx, machineDeployments, ctrlruntimeclient.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Errorw("Failed to list machine deployments", "object", ctrlruntimeclient.ObjectKeyFromObject(obj), zap.Error(err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, md := range machineDeployments.Items {
+		if md.Annotations[resources.MachineDeploymentOSPAnnotation] == "" {
+			continue
+		}
+		if slices.ContainsFunc(annotations, func(annotation string) bool { return md.Annotations[annotation] == obj.GetName() }) {
+			requests = append(requests, reconcile.Request{NamespacedName: ctrlruntimeclient.ObjectKeyFromObject(&md)})
+		}
+	}
+
+	return requests
+}
+
+// enqueueMachineDeploymentsUsing returns requests for the machine deployments whose OSP uses the object. Machine
+// deployments whose OSP can't be fetched or resolved are skipped, the error is reported when they're reconciled.
+func (r *Reconciler) enqueueMachineDeploymentsUsing(ctx context.Context, obj ctrlruntimeclient.Object, uses func(*clusterv1alpha1.MachineDeployment, *osmv1alpha1.OperatingSystemProfile) (bool, error)) []reconcile.Request {
+	log := r.log.With("object", ctrlruntimeclient.ObjectKeyFromObject(obj))
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := r.workerClient.List(ctx, machineDeployments); err != nil {
+		log.Errorw("Failed to list machine deployments", zap.Error(err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range machineDeployments.Items {
+		md := &machineDeployments.Items[i]
+		if md.Annotations[resources.MachineDeploymentOSPAnnotation] == "" {
+			continue
+		}
+
+		osp, err := r.fetchOSP(ctx, md)
+		if err != nil {
+			log.Debugw("Skipping machine deployment", "machinedeployment", ctrlruntimeclient.ObjectKeyFromObject(md), zap.Error(err))
+			continue
+		}
+
+		used, err := uses(md, osp)
+		if err != nil {
+			log.Debugw("Skipping machine deployment", "machinedeployment", ctrlruntimeclient.ObjectKeyFromObject(md), zap.Error(err))
+			continue
+		}
+		if used {
+			requests = append(requests, reconcile.Request{NamespacedName: ctrlruntimeclient.ObjectKeyFromObject(md)})
+		}
+	}
+
+	return requests
+}
+
+// enqueueMachineDeploymentsUsingFileContent returns requests for the machine deployments whose OSP, including its base
+// OSPs and the fragments that apply to the machine deployment, sources the content of a file from the secret or config
+// map.
+func (r *Reconciler) enqueueMachineDeploymentsUsingFileContent(ctx context.Context, obj ctrlruntimeclient.Object) []reconcile.Request {
+	return r.enqueueMachineDeploymentsUsing(ctx, obj, func(md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) (bool, error) {
+		osp, _, err := resources.ResolveOperatingSystemProfile(ctx, r.Client, osp)
+		if err != nil {
+			return false, err
+		}
+
+		osp, _, err = resources.ApplyOperatingSystemProfileFragments(ctx, r.Client, osp, md)
+		if err != nil {
+			return false, err
+		}
+
+		return resources.ReferencesFileContent(osp, obj), nil
+	})
+}
+
+// enqueueMachineDeploymentsUsingFragment returns requests for the machine deployments whose OSP the fragment is merged
+// into.
+func (r *Reconciler) enqueueMachineDeploymentsUsingFragment(ctx context.Context, fragment *osmv1alpha1.OperatingSystemProfileFragment) []reconcile.Request {
+	return r.enqueueMachineDeploymentsUsing(ctx, fragment, func(md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) (bool, error) {
+		if osp.Namespace != fragment.Namespace {
+			return false, nil
+		}
+
+		osp, _, err := resources.ResolveOperatingSystemProfile(ctx, r.Client, osp)
+		if err != nil {
+			return false, err
+		}
+
+		return resources.FragmentMatches(fragment, osp, md)
+	})
+}
+
+// enqueueMachineDeploymentsUsingTemplateLibrary returns requests for the machine deployments whose OSP, including its
+// base OSPs, imports the template library.
+func (r *Reconciler) enqueueMachineDeploymentsUsingTemplateLibrary(ctx context.Context, library *osmv1alpha1.TemplateLibrary) []reconcile.Request {
+	return r.enqueueMachineDeploymentsUsing(ctx, library, func(_ *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) (bool, error) {
+		osp, _, err := resources.ResolveOperatingSystemProfile(ctx, r.Client, osp)
+		if err != nil {
+			return false, err
+		}
+
+		return resources.ImportsTemplateLibrary(osp, library), nil
+	})
+}
+
+// enqueueMachineDeploymentsUsingOverride returns requests for the machine deployments that the override applies to.
+func (r *Reconciler) enqueueMachineDeploymentsUsingOverride(ctx context.Context, override *osmv1alpha1.OperatingSystemConfigOverride) []reconcile.Request {
+	// Overrides are only read from the namespace of OSM.
+	if override.Namespace != r.namespace {
+		return nil
+	}
+
+	return r.enqueueMachineDeploymentsUsing(ctx, override, func(md *clusterv1alpha1.MachineDeployment, _ *osmv1alpha1.OperatingSystemProfile) (bool, error) {
+		return resources.OverrideMatches(override, md)
+	})
+}
+
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrlruntime.Request) (reconcile.Result, error) {
 	log := r.log.With("request", req)
 	log.Debug("Reconciling OSC resource...")
@@ -182,26 +485,224 @@ func (r *Reconciler) reconcile(ctx context.Context, md *clusterv1alpha1.MachineD
 		return fmt.Errorf("failed to fetch OperatingSystemProfile: %w", err)
 	}
 
+	reconcileErr := r.reconcileOperatingSystemConfigAndSecrets(ctx, md, osp)
+
+	// Keep track of the machine deployments referencing the OSP, including the errors that occurred while rendering.
+	if err := r.updateOperatingSystemProfileStatuses(ctx, md, osp, reconcileErr); err != nil {
+		if reconcileErr == nil {
+			return fmt.Errorf("failed to update OperatingSystemProfile status: %w", err)
+		}
+		r.log.Errorw("Failed to update OperatingSystemProfile status", zap.Error(err))
+	}
+
+	return reconcileErr
+}
+
+func (r *Reconciler) reconcileOperatingSystemConfigAndSecrets(ctx context.Context, md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) error {
+	osp, baseVersions, err := resources.ResolveOperatingSystemProfile(ctx, r.Client, osp)
+	if err != nil {
+		r.recorder.Event(md, corev1.EventTypeWarning, "OperatingSystemProfileError", err.Error())
+		return fmt.Errorf("failed to resolve OperatingSystemProfile: %w", err)
+	}
+
+	osp, fragmentsHash, err := resources.ApplyOperatingSystemProfileFragments(ctx, r.Client, osp, md)
+	if err != nil {
+		r.recorder.Event(md, corev1.EventTypeWarning, "OperatingSystemProfileError", err.Error())
+		return fmt.Errorf("failed to apply OperatingSystemProfileFragments: %w", err)
+	}
+
+	osp, templateLibraryVersions, err := resources.ImportTemplateLibraries(ctx, r.Client, osp)
+	if err != nil {
+		r.recorder.Event(md, corev1.EventTypeWarning, "OperatingSystemProfileError", err.Error())
+		return fmt.Errorf("failed to import TemplateLibraries: %w", err)
+	}
+
 	if err := r.checkOSP(md, osp); err != nil {
 		return fmt.Errorf("failed to validate referenced OSP: %w", err)
 	}
 
-	// Check if OSC and secret need to be rotated
-	if err := r.handleOSCAndSecretsRotation(ctx, md, osp); err != nil {
-		return fmt.Errorf("failed to perform rotation for OSC and secrets: %w", err)
+	fileContents, err := resources.FetchReferencedFileContents(ctx, r.Client, osp)
+	if err != nil {
+		return fmt.Errorf("failed to fetch file contents referenced by OperatingSystemProfile: %w", err)
+	}
+
+	fileContentsHash, err := fileContents.Hash()
+	if err != nil {
+		return err
+	}
+
+	overrides, overridesHash, err := resources.FetchOperatingSystemConfigOverrides(ctx, r.Client, r.namespace, md)
+	if err != nil {
+		return fmt.Errorf("failed to fetch OperatingSystemConfigOverrides: %w", err)
+	}
+
+	parameterValues, parametersHash, err := resources.FetchParameterValues(ctx, r.workerClient, md)
+	if err != nil {
+		return fmt.Errorf("failed to fetch OperatingSystemProfile parameter values: %w", err)
+	}
+
+	registryOverrides, registryOverridesHash, err := resources.FetchRegistryOverrides(ctx, r.workerClient, md)
+	if err != nil {
+		return fmt.Errorf("failed to fetch registry overrides: %w", err)
 	}
 
-	if err := r.reconcileOperatingSystemConfigs(ctx, md, osp); err != nil {
+	registryCredentials, registryCredentialsHash, err := r.fetchRegistryCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	registryCertificates, registryCertificatesHash, err := r.fetchRegistryCertificates(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only OSPs that use the labels of the machine deployment are rotated when the labels change.
+	var labelsHash string
+	if resources.UsesMachineDeploymentLabels(osp) {
+		if labelsHash, err = calculateLabelsHash(md.Labels); err != nil {
+			return err
+		}
+	}
+
+	revision := ospRevision{
+		version:                  osp.Spec.Version,
+		baseVersions:             baseVersions,
+		templateLibraryVersions:  templateLibraryVersions,
+		fragmentsHash:            fragmentsHash,
+		overridesHash:            overridesHash,
+		fileContentsHash:         fileContentsHash,
+		parametersHash:           parametersHash,
+		labelsHash:               labelsHash,
+		registryOverridesHash:    registryOverridesHash,
+		registryCredentialsHash:  registryCredentialsHash,
+		registryCertificatesHash: registryCertificatesHash,
+	}
+
+	if err := r.reconcileOperatingSystemConfigs(ctx, md, osp, revision, fileContents, parameterValues, overrides, registryOverrides, registryCredentials, registryCertificates); err != nil {
+		r.recorder.Event(md, corev1.EventTypeWarning, "OperatingSystemConfigRenderFailed", err.Error())
 		return fmt.Errorf("failed to reconcile operating system config: %w", err)
 	}
 
-	if err := r.reconcileSecrets(ctx, md, osp); err != nil {
+	if err := r.reconcileSecrets(ctx, md, revision); err != nil {
 		return fmt.Errorf("failed to reconcile secrets: %w", err)
 	}
 
+	if err := r.reconcileRuntimeClasses(ctx, md); err != nil {
+		return fmt.Errorf("failed to reconcile runtime classes: %w", err)
+	}
+
+	return nil
+}
+
+// machineDeploymentContainerRuntime returns the container runtime of the machine deployment and its config, including
+// the runtime handlers of the machine deployment.
+func (r *Reconciler) machineDeploymentContainerRuntime(md *clusterv1alpha1.MachineDeployment) (string, containerruntime.Config, error) {
+	containerRuntime, err := resources.MachineDeploymentContainerRuntime(md, r.containerRuntime)
+	if err != nil {
+		return "", containerruntime.Config{}, err
+	}
+
+	config := r.containerRuntimeConfig.WithContainerRuntime(containerRuntime)
+	config.RuntimeHandlers, err = resources.MachineDeploymentRuntimeHandlers(md, containerRuntime, config.RuntimeHandlers)
+	if err != nil {
+		return "", containerruntime.Config{}, fmt.Errorf("invalid runtime handlers: %w", err)
+	}
+
+	return containerRuntime, config, nil
+}
+
+// reconcileRuntimeClasses ensures that a RuntimeClass exists in the worker cluster for every runtime handler of the
+// machine deployment, which schedules pods onto the nodes that the kubelet labels with the handler.
+// RuntimeClasses can be shared by machine deployments, so they're only deleted once no machine deployment uses their
+// handler anymore.
+func (r *Reconciler) reconcileRuntimeClasses(ctx context.Context, md *clusterv1alpha1.MachineDeployment) error {
+	_, config, err := r.machineDeploymentContainerRuntime(md)
+	if err != nil {
+		return err
+	}
+
+	var factories []reconciling.NamedRuntimeClassReconcilerFactory
+	for _, handler := range config.RuntimeHandlers {
+		factories = append(factories, runtimeClassReconciler(handler.Name))
+	}
+
+	if err := reconciling.ReconcileRuntimeClasses(ctx, factories, "", r.workerClient); err != nil {
+		return err
+	}
+
+	return r.deleteUnusedRuntimeClasses(ctx)
+}
+
+// deleteUnusedRuntimeClasses deletes the RuntimeClasses created by OSM whose handler no machine deployment uses. Nothing
+// is deleted if the runtime handlers of a machine deployment can't be determined.
+func (r *Reconciler) deleteUnusedRuntimeClasses(ctx context.Context) error {
+	runtimeClasses := &nodev1.RuntimeClassList{}
+	if err := r.workerClient.List(ctx, runtimeClasses, ctrlruntimeclient.MatchingLabels{runtimeClassManagedByLabel: runtimeClassManagedBy}); err != nil {
+		return fmt.Errorf("failed to list RuntimeClasses: %w", err)
+	}
+	if len(runtimeClasses.Items) == 0 {
+		return nil
+	}
+
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := r.workerClient.List(ctx, machineDeployments); err != nil {
+		return fmt.Errorf("failed to list machine deployments: %w", err)
+	}
+
+	used := map[string]struct{}{}
+	for i := range machineDeployments.Items {
+		md := &machineDeployments.Items[i]
+		if md.Annotations[resources.MachineDeploymentOSPAnnotation] == "" || md.DeletionTimestamp != nil {
+			continue
+		}
+
+		_, config, err := r.machineDeploymentContainerRuntime(md)
+		if err != nil {
+			r.log.Debugw("Skipping deletion of unused RuntimeClasses", "machinedeployment", ctrlruntimeclient.ObjectKeyFromObject(md), zap.Error(err))
+			return nil
+		}
+		for _, handler := range config.RuntimeHandlers {
+			used[handler.Name] = struct{}{}
+		}
+	}
+
+	for i := range runtimeClasses.Items {
+		runtimeClass := &runtimeClasses.Items[i]
+		if _, ok := used[runtimeClass.Name]; ok {
+			continue
+		}
+		if err := r.workerClient.Delete(ctx, runtimeClass); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete RuntimeClass %s: %w", runtimeClass.Name, err)
+		}
+	}
+
 	return nil
 }
 
+func runtimeClassReconciler(handler string) reconciling.NamedRuntimeClassReconcilerFactory {
+	return func() (string, reconciling.RuntimeClassReconciler) {
+		return handler, func(runtimeClass *nodev1.RuntimeClass) (*nodev1.RuntimeClass, error) {
+			// The handler is immutable, existing RuntimeClasses with the same name that weren't created by OSM are left as
+			// they are.
+			if runtimeClass.Handler == "" {
+				runtimeClass.Handler = handler
+				if runtimeClass.Labels == nil {
+					runtimeClass.Labels = map[string]string{}
+				}
+				runtimeClass.Labels[runtimeClassManagedByLabel] = runtimeClassManagedBy
+			}
+			if runtimeClass.Labels[runtimeClassManagedByLabel] != runtimeClassManagedBy {
+				return runtimeClass, nil
+			}
+
+			runtimeClass.Scheduling = &nodev1.Scheduling{
+				NodeSelector: map[string]string{resources.RuntimeHandlerLabelPrefix + handler: "true"},
+			}
+			return runtimeClass, nil
+		}
+	}
+}
+
 func (r *Reconciler) fetchOSP(ctx context.Context, md *clusterv1alpha1.MachineDeployment) (*osmv1alpha1.OperatingSystemProfile, error) {
 	ospName := md.Annotations[resources.MachineDeploymentOSPAnnotation]
 
@@ -223,51 +724,158 @@ func (r *Reconciler) fetchOSP(ctx context.Context, md *clusterv1alpha1.MachineDe
 	return osp, nil
 }
 
-func (r *Reconciler) reconcileOperatingSystemConfigs(ctx context.Context, md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) error {
-	machineDeploymentKey := fmt.Sprintf("%s-%s", md.Namespace, md.Name)
-	// machineDeploymentKey must be no more than 63 characters else it'll fail to create bootstrap token.
-	if len(machineDeploymentKey) >= 63 {
-		// As a fallback, we just use the name of the machine deployment.
-		machineDeploymentKey = md.Name
-	}
-
-	bootstrapKubeconfig, bootstrapKubeconfigName, err := r.bootstrappingManager.CreateBootstrapKubeconfig(ctx, machineDeploymentKey)
+func (r *Reconciler) reconcileOperatingSystemConfigs(ctx context.Context, md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile, revision ospRevision, fileContents resources.ReferencedFileContents, parameterValues map[string]string, overrides []osmv1alpha1.OperatingSystemConfigOverride, registryOverrides resources.RegistryOverrides, registryCredentials map[string]containerruntime.AuthConfig, registryCertificates map[string]containerruntime.RegistryCertificates) error {
+	// The bootstrap kubeconfig is ensured on every reconcile, since that refreshes the bootstrap token before it expires.
+	bootstrapKubeconfig, bootstrapKubeconfigName, err := r.bootstrappingManager.CreateBootstrapKubeconfig(ctx, machineDeploymentKey(md))
 	if err != nil {
 		return fmt.Errorf("failed to create bootstrap kubeconfig: %w", err)
 	}
 
-	// Check if OSC already exists, in that case we don't need to do anything since OSC are immutable
+	// Check if OSC already exists, in that case we don't need to do anything since OSC are immutable unless they need
+	// to be rotated.
 	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
-	osc := &osmv1alpha1.OperatingSystemConfig{}
-	if err := r.Get(ctx, types.NamespacedName{Name: oscName, Namespace: r.namespace}, osc); err == nil {
-		// Early return since the object already exists
-		return nil
+	existingOSC := &osmv1alpha1.OperatingSystemConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: oscName, Namespace: r.namespace}, existingOSC); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get OperatingSystemConfig %q from namespace %q: %w", oscName, r.namespace, err)
+		}
+		existingOSC = nil
+	}
+
+	if existingOSC != nil {
+		rotate, err := r.requiresRotation(md, revision, existingOSC)
+		if err != nil {
+			return err
+		}
+		if !rotate {
+			// The OSC is up-to-date, so a previous render failure doesn't apply anymore.
+			return r.clearRenderFailed(ctx, existingOSC)
+		}
+
+		// The kubelet bootstrap kubeconfig is re-created for the rotated OSC.
+		if err := r.deleteKubeletBootstrapConfigSecret(ctx, md); err != nil {
+			return err
+		}
+		bootstrapKubeconfig, bootstrapKubeconfigName, err = r.bootstrappingManager.CreateBootstrapKubeconfig(ctx, machineDeploymentKey(md))
+		if err != nil {
+			return fmt.Errorf("failed to create bootstrap kubeconfig: %w", err)
+		}
+	}
+
+	osc, err := r.generateOperatingSystemConfig(ctx, md, osp, revision, oscName, bootstrapKubeconfig, bootstrapKubeconfigName, fileContents, parameterValues, overrides, registryOverrides, registryCredentials, registryCertificates)
+	if err != nil {
+		// The existing OSC is kept so that machines can still be provisioned while the error persists. Without an
+		// existing OSC, the error is recorded for the machine deployment in the status of the OSP.
+		if existingOSC != nil {
+			if statusErr := r.setRenderFailed(ctx, existingOSC, err); statusErr != nil {
+				r.log.Errorw("Failed to update OperatingSystemConfig status", "osc", oscName, zap.Error(statusErr))
+			}
+		}
+		return err
+	}
+
+	if existingOSC != nil {
+		// Delete the existing OSC and secrets and let the controller re-create them.
+		if err := r.Delete(ctx, existingOSC); err != nil {
+			return fmt.Errorf("failed to delete OperatingSystemConfig %s against MachineDeployment %s: %w", oscName, md.Name, err)
+		}
+
+		if err := r.deleteCloudConfigSecrets(ctx, md); err != nil {
+			return err
+		}
+	}
+
+	// Create resource in cluster
+	if err := r.Create(ctx, osc); err != nil {
+		return fmt.Errorf("failed to create %s osc: %w", oscName, err)
 	}
+	r.log.Infof("successfully generated provisioning osc: %v", oscName)
+
+	return r.updateOperatingSystemConfigStatus(ctx, osc, func(status *osmv1alpha1.OperatingSystemConfigStatus) {
+		status.ObservedGeneration = osc.Generation
+		status.OperatingSystemProfile = osp.Name
+		status.OperatingSystemProfileVersion = osp.Spec.Version
+		status.MachineDeploymentRevision = osc.Annotations[mcbootstrap.MachineDeploymentRevision]
+		status.MachineDeploymentAnnotationsHash = osc.Annotations[OperatingSystemConfigMDHash]
+
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               osmv1alpha1.OperatingSystemConfigRendered,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: osc.Generation,
+			Reason:             "Rendered",
+			Message:            fmt.Sprintf("Rendered from OperatingSystemProfile %s version %s", osp.Name, osp.Spec.Version),
+		})
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               osmv1alpha1.OperatingSystemConfigRenderFailed,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: osc.Generation,
+			Reason:             "Rendered",
+		})
+	})
+}
 
+// generateOperatingSystemConfig renders the OSC for a machine deployment from its OSP.
+func (r *Reconciler) generateOperatingSystemConfig(
+	ctx context.Context,
+	md *clusterv1alpha1.MachineDeployment,
+	osp *osmv1alpha1.OperatingSystemProfile,
+	revision ospRevision,
+	oscName string,
+	bootstrapKubeconfig *api.Config,
+	bootstrapKubeconfigName string,
+	fileContents resources.ReferencedFileContents,
+	parameterValues map[string]string,
+	overrides []osmv1alpha1.OperatingSystemConfigOverride,
+	registryOverrides resources.RegistryOverrides,
+	registryCredentials map[string]containerruntime.AuthConfig,
+	registryCertificates map[string]containerruntime.RegistryCertificates,
+) (*osmv1alpha1.OperatingSystemConfig, error) {
 	provisioner, err := generator.GetProvisioningUtility(osp.Spec.OSName, *md)
 	if err != nil {
-		return fmt.Errorf("failed to determine provisioning utility: %w", err)
+		return nil, fmt.Errorf("failed to determine provisioning utility: %w", err)
 	}
 
 	if osp.Spec.ProvisioningUtility != "" && provisioner != osp.Spec.ProvisioningUtility {
-		return fmt.Errorf("specified provisioning utility %q is not supported by the OperatingSystemProfile", osp.Spec.ProvisioningUtility)
+		return nil, fmt.Errorf("specified provisioning utility %q is not supported by the OperatingSystemProfile", osp.Spec.ProvisioningUtility)
+	}
+
+	containerRuntime, containerRuntimeConfig, err := r.machineDeploymentContainerRuntime(md)
+	if err != nil {
+		return nil, err
 	}
 
 	if r.nodeRegistryCredentialsSecret != "" {
-		registryCredentials, err := containerruntime.GetContainerdAuthConfig(ctx, r.Client, r.nodeRegistryCredentialsSecret)
+		containerRuntimeConfig.RegistryCredentials = registryCredentials
+	}
+
+	if r.nodeRegistryCertificatesSecret != "" {
+		containerRuntimeConfig.RegistryCertificates = registryCertificates
+	}
+
+	// Registry credentials that are delivered through a credential provider are kept out of the OSC and the container
+	// runtime config, the nodes fetch them while provisioning. Only the credentials of the registry of the sandbox image
+	// are kept, since the container runtime pulls it without asking the kubelet.
+	credentialProviderConfig := r.credentialProviderConfig
+	var credentialsSecretName string
+	if credentialProviderConfig.RegistryCredentials {
+		credentials := registryOverrides.Apply(containerRuntimeConfig).RegistryCredentials
+		containerRuntimeConfig.RegistryCredentials = credentialprovider.SandboxImageCredentials(credentials, containerRuntimeConfig.SandboxImage)
+		registryOverrides.RegistryCredentials = nil
+
+		var registries []string
+		credentialsSecretName, registries, err = r.reconcileRegistryCredentialsSecret(ctx, md, credentials)
 		if err != nil {
-			return fmt.Errorf("failed to get containerd auth config: %w", err)
+			return nil, err
 		}
-		r.containerRuntimeConfig.RegistryCredentials = registryCredentials
+		credentialProviderConfig = credentialProviderConfig.WithRegistries(registries)
 	}
 
 	token, err := bootstrap.ExtractAPIServerToken(ctx, r.workerClient)
 	if err != nil {
-		return fmt.Errorf("failed to fetch api-server token: %w", err)
+		return nil, fmt.Errorf("failed to fetch api-server token: %w", err)
 	}
 
-	// We need to create OSC resource as it doesn't exist
-	osc, err = resources.GenerateOperatingSystemConfig(
+	osc, err := resources.GenerateOperatingSystemConfig(
 		md,
 		osp,
 		bootstrapKubeconfig,
@@ -278,32 +886,38 @@ func (r *Reconciler) reconcileOperatingSystemConfigs(ctx context.Context, md *cl
 		r.caCert,
 		r.hostCACert,
 		r.clusterDNSIPs,
-		r.containerRuntime,
+		containerRuntime,
 		r.externalCloudProvider,
 		r.initialTaints,
 		r.nodeHTTPProxy,
 		r.nodeNoProxy,
-		r.containerRuntimeConfig,
+		containerRuntimeConfig,
+		registryOverrides,
+		credentialProviderConfig,
+		credentialsSecretName,
 		r.kubeletFeatureGates,
+		fileContents,
+		parameterValues,
+		overrides,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to generate %s osc: %w", oscName, err)
+		return nil, fmt.Errorf("failed to generate %s osc: %w", oscName, err)
 	}
 
 	if osc.Spec.CloudProvider.Name == "edge" {
 		if err := r.generateEdgeScript(ctx, md, token, bootstrapKubeconfig); err != nil {
-			return fmt.Errorf("failed to generate edge provider bootstrap script: %w", err)
+			return nil, fmt.Errorf("failed to generate edge provider bootstrap script: %w", err)
 		}
 	}
 
 	// Add machine deployment revision to OSC
-	revision := md.Annotations[mcsdkcommon.RevisionAnnotation]
+	mdRevision := md.Annotations[mcsdkcommon.RevisionAnnotation]
 	mdhash, err := r.calculateAnnotationsHash(md.Annotations)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	osc.Annotations = oscRotationAnnotations(revision, mdhash, osp.Spec.Version, osc.Annotations)
+	osc.Annotations = oscRotationAnnotations(mdRevision, mdhash, revision, osc.Annotations)
 	osc.Spec.ProvisioningUtility = osp.Spec.ProvisioningUtility
 
 	// Defaults to cloud-init although we should never hit this condition i.e ProvisioningUtility in OSP to be empty.
@@ -311,15 +925,10 @@ func (r *Reconciler) reconcileOperatingSystemConfigs(ctx context.Context, md *cl
 		osc.Spec.ProvisioningUtility = osmv1alpha1.ProvisioningUtilityCloudInit
 	}
 
-	// Create resource in cluster
-	if err := r.Create(ctx, osc); err != nil {
-		return fmt.Errorf("failed to create %s osc: %w", oscName, err)
-	}
-	r.log.Infof("successfully generated provisioning osc: %v", oscName)
-	return nil
+	return osc, nil
 }
 
-func (r *Reconciler) reconcileSecrets(ctx context.Context, md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) error {
+func (r *Reconciler) reconcileSecrets(ctx context.Context, md *clusterv1alpha1.MachineDeployment, revision ospRevision) error {
 	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
 	osc := &osmv1alpha1.OperatingSystemConfig{}
 
@@ -327,52 +936,128 @@ func (r *Reconciler) reconcileSecrets(ctx context.Context, md *clusterv1alpha1.M
 		return fmt.Errorf("failed to get OperatingSystemConfigs %q from namespace %q: %w", oscName, r.namespace, err)
 	}
 
-	if err := r.ensureCloudConfigSecret(ctx, osc.Spec.ProvisioningConfig, osc.Spec.ProvisioningUtility, resources.ProvisioningCloudConfig, osc.Spec.OSName, osc.Spec.CloudProvider.Name, md, osp.Spec.Version); err != nil {
+	provisioningSecret, err := r.ensureCloudConfigSecret(ctx, osc.Spec.ProvisioningConfig, osc.Spec.ProvisioningUtility, resources.ProvisioningCloudConfig, osc.Spec.OSName, osc.Spec.CloudProvider.Name, md, revision)
+	if err != nil {
 		return fmt.Errorf("failed to reconcile provisioning config secret: %w", err)
 	}
 
-	if err := r.ensureCloudConfigSecret(ctx, osc.Spec.BootstrapConfig, osc.Spec.ProvisioningUtility, mcbootstrap.BootstrapCloudConfig, osc.Spec.OSName, osc.Spec.CloudProvider.Name, md, osp.Spec.Version); err != nil {
+	bootstrapSecret, err := r.ensureCloudConfigSecret(ctx, osc.Spec.BootstrapConfig, osc.Spec.ProvisioningUtility, mcbootstrap.BootstrapCloudConfig, osc.Spec.OSName, osc.Spec.CloudProvider.Name, md, revision)
+	if err != nil {
 		return fmt.Errorf("failed to reconcile bootstrapping config secret: %w", err)
 	}
 
-	return nil
+	var secretStatuses []osmv1alpha1.CloudConfigSecretStatus
+	for _, secret := range []struct {
+		secretType mcbootstrap.CloudConfigSecret
+		secret     *corev1.Secret
+	}{
+		{secretType: mcbootstrap.BootstrapCloudConfig, secret: bootstrapSecret},
+		{secretType: resources.ProvisioningCloudConfig, secret: provisioningSecret},
+	} {
+		contentHash, err := calculateSecretContentHash(secret.secret)
+		if err != nil {
+			return err
+		}
+
+		secretStatuses = append(secretStatuses, osmv1alpha1.CloudConfigSecretStatus{
+			Name:        secret.secret.Name,
+			Namespace:   secret.secret.Namespace,
+			Type:        string(secret.secretType),
+			ContentHash: contentHash,
+		})
+	}
+
+	return r.updateOperatingSystemConfigStatus(ctx, osc, func(status *osmv1alpha1.OperatingSystemConfigStatus) {
+		status.CloudConfigSecrets = secretStatuses
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               osmv1alpha1.OperatingSystemConfigSecretsSynced,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: osc.Generation,
+			Reason:             "SecretsSynced",
+			Message:            "Cloud-config secrets were generated",
+		})
+	})
 }
 
-func (r *Reconciler) ensureCloudConfigSecret(ctx context.Context, config osmv1alpha1.OSCConfig, provisioningUtility osmv1alpha1.ProvisioningUtility, secretType mcbootstrap.CloudConfigSecret, operatingSystem osmv1alpha1.OperatingSystem, cloudProvider osmv1alpha1.CloudProvider, md *clusterv1alpha1.MachineDeployment, ospVersion string) error {
+func (r *Reconciler) ensureCloudConfigSecret(ctx context.Context, config osmv1alpha1.OSCConfig, provisioningUtility osmv1alpha1.ProvisioningUtility, secretType mcbootstrap.CloudConfigSecret, operatingSystem osmv1alpha1.OperatingSystem, cloudProvider osmv1alpha1.CloudProvider, md *clusterv1alpha1.MachineDeployment, revision ospRevision) (*corev1.Secret, error) {
 	secretName := fmt.Sprintf(mcbootstrap.CloudConfigSecretNamePattern, md.Name, md.Namespace, secretType)
 
 	// Check if secret already exists, in that case we don't need to do anything since secrets are immutable
 	secret := &corev1.Secret{}
 	if err := r.workerClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: mcbootstrap.CloudInitSettingsNamespace}, secret); err == nil {
 		// Early return since the object already exists
-		return nil
+		return secret, nil
 	}
 
 	provisionData, err := r.generator.Generate(&config, provisioningUtility, operatingSystem, cloudProvider, *md, secretType)
 	if err != nil {
-		return fmt.Errorf("failed to generate %s data with error: %w", secretType, err)
+		return nil, fmt.Errorf("failed to generate %s data with error: %w", secretType, err)
 	}
 
 	// Generate secret for cloud-config
 	secret = resources.GenerateCloudConfigSecret(secretName, mcbootstrap.CloudInitSettingsNamespace, provisionData)
 
 	// Add machine deployment revision to secret
-	revision := md.Annotations[mcsdkcommon.RevisionAnnotation]
+	mdRevision := md.Annotations[mcsdkcommon.RevisionAnnotation]
 	mdhash, err := r.calculateAnnotationsHash(md.Annotations)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	secret.Annotations = oscRotationAnnotations(revision, mdhash, ospVersion, secret.Annotations) // we do not check the hash here as OSC also regenerates Secrets
+	secret.Annotations = oscRotationAnnotations(mdRevision, mdhash, revision, secret.Annotations) // we do not check the hash here as OSC also regenerates Secrets
 
 	// Create resource in cluster
 	if err := r.workerClient.Create(ctx, secret); err != nil {
-		return fmt.Errorf("failed to create %s %s secret: %w", secretName, secretType, err)
+		return nil, fmt.Errorf("failed to create %s %s secret: %w", secretName, secretType, err)
 	}
 	r.log.Infof("successfully generated %s secret: %v", secretType, secretName)
+	return secret, nil
+}
+
+// updateOperatingSystemConfigStatus applies the given changes to the status of an OSC and persists them if anything changed.
+func (r *Reconciler) updateOperatingSystemConfigStatus(ctx context.Context, osc *osmv1alpha1.OperatingSystemConfig, mutate func(status *osmv1alpha1.OperatingSystemConfigStatus)) error {
+	oldStatus := osc.Status.DeepCopy()
+	mutate(&osc.Status)
+
+	if apiequality.Semantic.DeepEqual(oldStatus, &osc.Status) {
+		return nil
+	}
+
+	if err := r.Status().Update(ctx, osc); err != nil {
+		return fmt.Errorf("failed to update status of OperatingSystemConfig %s: %w", osc.Name, err)
+	}
 	return nil
 }
 
+// setRenderFailed records on an existing OSC that rendering its replacement failed.
+func (r *Reconciler) setRenderFailed(ctx context.Context, osc *osmv1alpha1.OperatingSystemConfig, renderErr error) error {
+	return r.updateOperatingSystemConfigStatus(ctx, osc, func(status *osmv1alpha1.OperatingSystemConfigStatus) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               osmv1alpha1.OperatingSystemConfigRenderFailed,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: osc.Generation,
+			Reason:             "RenderFailed",
+			Message:            renderErr.Error(),
+		})
+	})
+}
+
+// clearRenderFailed resets the RenderFailed condition of an OSC that is up-to-date.
+func (r *Reconciler) clearRenderFailed(ctx context.Context, osc *osmv1alpha1.OperatingSystemConfig) error {
+	if !meta.IsStatusConditionTrue(osc.Status.Conditions, osmv1alpha1.OperatingSystemConfigRenderFailed) {
+		return nil
+	}
+
+	return r.updateOperatingSystemConfigStatus(ctx, osc, func(status *osmv1alpha1.OperatingSystemConfigStatus) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               osmv1alpha1.OperatingSystemConfigRenderFailed,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: osc.Generation,
+			Reason:             "Rendered",
+		})
+	})
+}
+
 // handleMachineDeploymentCleanup handles the cleanup of resources created against a MachineDeployment
 func (r *Reconciler) handleMachineDeploymentCleanup(ctx context.Context, md *clusterv1alpha1.MachineDeployment) (reconcile.Result, error) {
 	// Delete OperatingSystemConfig
@@ -385,6 +1070,16 @@ func (r *Reconciler) handleMachineDeploymentCleanup(ctx context.Context, md *clu
 		return reconcile.Result{}, err
 	}
 
+	// Remove machine deployment from the OSP statuses
+	if err := r.updateOperatingSystemProfileStatuses(ctx, md, nil, nil); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Delete the RuntimeClasses that only the machine deployment used
+	if err := r.deleteUnusedRuntimeClasses(ctx); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Remove finalizer
 	kuberneteshelper.RemoveFinalizer(md, MachineDeploymentCleanupFinalizer)
 
@@ -408,13 +1103,115 @@ func (r *Reconciler) deleteOperatingSystemConfig(ctx context.Context, md *cluste
 	}
 
 	if err := r.Delete(ctx, osc); err != nil && !kerrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete OperatingSystemConfig %s: %v against MachineDeployment %w", oscName, md.Name, err)
+		return fmt.Errorf("failed to delete OperatingSystemConfig %s against MachineDeployment %s: %w", oscName, md.Name, err)
 	}
 	return nil
 }
 
 // deleteGeneratedSecrets deletes the secrets created against a MachineDeployment
 func (r *Reconciler) deleteGeneratedSecrets(ctx context.Context, md *clusterv1alpha1.MachineDeployment) error {
+	if err := r.deleteCloudConfigSecrets(ctx, md); err != nil {
+		return err
+	}
+
+	if err := r.deleteKubeletBootstrapConfigSecret(ctx, md); err != nil {
+		return err
+	}
+
+	// Delete registry credentials secret
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registryCredentialsSecretName(md),
+			Namespace: mcbootstrap.CloudInitSettingsNamespace,
+		},
+	}
+	if err := r.workerClient.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete registry credentials secret %s against MachineDeployment %s: %w", secret.Name, md.Name, err)
+	}
+
+	return nil
+}
+
+// deleteKubeletBootstrapConfigSecret deletes the kubelet bootstrapping kubeconfig secret created against a MachineDeployment
+func (r *Reconciler) deleteKubeletBootstrapConfigSecret(ctx context.Context, md *clusterv1alpha1.MachineDeployment) error {
+	bootstrapConfigName := fmt.Sprintf("%s-kubelet-bootstrap-config", machineDeploymentKey(md))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapConfigName,
+			Namespace: mcbootstrap.CloudInitSettingsNamespace,
+		},
+	}
+
+	if err := r.workerClient.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete kubelet bootstrap config secret %s against MachineDeployment %s: %w", bootstrapConfigName, md.Name, err)
+	}
+	return nil
+}
+
+// machineDeploymentKey returns the key of the machine deployment that the names of its bootstrap token and secrets are
+// based on.
+func machineDeploymentKey(md *clusterv1alpha1.MachineDeployment) string {
+	key := fmt.Sprintf("%s-%s", md.Namespace, md.Name)
+	// The key must be no more than 63 characters else it'll fail to create bootstrap token.
+	if len(key) >= 63 {
+		// As a fallback, we just use the name of the machine deployment.
+		key = md.Name
+	}
+	return key
+}
+
+func registryCredentialsSecretName(md *clusterv1alpha1.MachineDeployment) string {
+	return fmt.Sprintf("%s-registry-credentials", machineDeploymentKey(md))
+}
+
+// reconcileRegistryCredentialsSecret keeps the registry credentials of the machine deployment in a secret in the
+// cloud-init-settings namespace, which the nodes fetch while provisioning. It returns the name of the secret and the
+// registries of the credentials, the name is empty and the secret is deleted if there are no credentials.
+func (r *Reconciler) reconcileRegistryCredentialsSecret(ctx context.Context, md *clusterv1alpha1.MachineDeployment, credentials map[string]containerruntime.AuthConfig) (string, []string, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registryCredentialsSecretName(md),
+			Namespace: mcbootstrap.CloudInitSettingsNamespace,
+		},
+	}
+
+	if len(credentials) == 0 {
+		if err := r.workerClient.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
+			return "", nil, fmt.Errorf("failed to delete registry credentials secret %s: %w", secret.Name, err)
+		}
+		return "", nil, nil
+	}
+
+	response, registries, err := credentialprovider.RegistryCredentialsResponse(credentials)
+	if err != nil {
+		return "", nil, err
+	}
+	data := map[string][]byte{credentialprovider.RegistryCredentialsSecretKey: []byte(response)}
+
+	if err := r.workerClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(secret), secret); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return "", nil, fmt.Errorf("failed to get registry credentials secret %s: %w", secret.Name, err)
+		}
+
+		secret.Data = data
+		if err := r.workerClient.Create(ctx, secret); err != nil {
+			return "", nil, fmt.Errorf("failed to create registry credentials secret %s: %w", secret.Name, err)
+		}
+		return secret.Name, registries, nil
+	}
+
+	if !apiequality.Semantic.DeepEqual(secret.Data, data) {
+		secret.Data = data
+		if err := r.workerClient.Update(ctx, secret); err != nil {
+			return "", nil, fmt.Errorf("failed to update registry credentials secret %s: %w", secret.Name, err)
+		}
+	}
+
+	return secret.Name, registries, nil
+}
+
+// deleteCloudConfigSecrets deletes the cloud-config secrets generated for a MachineDeployment
+func (r *Reconciler) deleteCloudConfigSecrets(ctx context.Context, md *clusterv1alpha1.MachineDeployment) error {
 	// Delete provisioning secret
 	provisioningSecretName := fmt.Sprintf(mcbootstrap.CloudConfigSecretNamePattern, md.Name, md.Namespace, resources.ProvisioningCloudConfig)
 	secret := &corev1.Secret{
@@ -436,72 +1233,104 @@ func (r *Reconciler) deleteGeneratedSecrets(ctx context.Context, md *clusterv1al
 		return fmt.Errorf("failed to delete bootstrap secret %s against MachineDeployment %s: %w", bootstrapSecretName, md.Name, err)
 	}
 
-	// Delete kubelet bootstrapping kubeconfig secret
-	machineDeploymentKey := fmt.Sprintf("%s-%s", md.Namespace, md.Name)
-	// machineDeploymentKey must be no more than 63 characters else it'll fail to create bootstrap token.
-	if len(machineDeploymentKey) >= 63 {
-		// As a fallback, we just use the name of the machine deployment.
-		machineDeploymentKey = md.Name
+	return nil
+}
+
+// requiresRotation checks if the existing OSC is outdated, i.e. the machine deployment template, labels or annotations
+// or the OSP revision were updated. In that case the OSC and secrets need to be rotated.
+func (r *Reconciler) requiresRotation(md *clusterv1alpha1.MachineDeployment, revision ospRevision, osc *osmv1alpha1.OperatingSystemConfig) (bool, error) {
+	// now also check that the MD annotations have not changed as those can generate some differences in the output OSC
+	mdhash, err := r.calculateAnnotationsHash(md.Annotations)
+	if err != nil {
+		return false, err
 	}
 
-	bootstrapConfigName := fmt.Sprintf("%s-kubelet-bootstrap-config", machineDeploymentKey)
-	secret.Name = bootstrapConfigName
+	currentRevision := md.Annotations[mcsdkcommon.RevisionAnnotation]
+	existingRevision := osc.Annotations[mcbootstrap.MachineDeploymentRevision]
+	existingHash := osc.Annotations[OperatingSystemConfigMDHash]
+
+	return currentRevision != existingRevision || existingHash != mdhash || revision != ospRevisionFromAnnotations(osc.Annotations), nil
+}
 
-	if err := r.workerClient.Delete(ctx, secret); err != nil && !kerrors.IsNotFound(err) {
-		return fmt.Errorf("failed to delete kubelet bootstrap config secret %s against MachineDeployment %s: %w", bootstrapConfigName, md.Name, err)
+func (r *Reconciler) calculateAnnotationsHash(annotations map[string]string) (string, error) {
+	mdhash, err := resources.HashJSON(annotations)
+	if err != nil {
+		return "", fmt.Errorf("failed to json encode machinedeployment annotations: %w", err)
 	}
 
-	return nil
+	return mdhash, nil
 }
 
-func (r *Reconciler) handleOSCAndSecretsRotation(ctx context.Context, md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) error {
-	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
-	osc := &osmv1alpha1.OperatingSystemConfig{}
-	if err := r.Get(ctx, types.NamespacedName{Name: oscName, Namespace: r.namespace}, osc); err != nil {
-		if kerrors.IsNotFound(err) {
-			// OSC doesn't exist and we need to create it.
-			return nil
-		}
-		return err
+// fetchRegistryCredentials returns the registry credentials of the -node-registry-credentials-secret and a hash over
+// them, which is empty if no credentials are configured.
+func (r *Reconciler) fetchRegistryCredentials(ctx context.Context) (map[string]containerruntime.AuthConfig, string, error) {
+	if r.nodeRegistryCredentialsSecret == "" {
+		return nil, "", nil
+	}
+
+	registryCredentials, err := containerruntime.GetContainerdAuthConfig(ctx, r.Client, r.nodeRegistryCredentialsSecret)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get containerd auth config: %w", err)
 	}
 
-	// OSC already exists, we need to check if the template in machine deployment was updated. If it's updated then we need to rotate
-	// the OSC and secrets.
+	if len(registryCredentials) == 0 {
+		return registryCredentials, "", nil
+	}
 
-	// now also check that the MD annotations have not changed as those can generate some differences in the output OSC
-	mdhash, err := r.calculateAnnotationsHash(md.Annotations)
+	hash, err := resources.HashJSON(registryCredentials)
 	if err != nil {
-		return err
+		return nil, "", fmt.Errorf("failed to json encode registry credentials: %w", err)
 	}
 
-	currentRevision := md.Annotations[mcsdkcommon.RevisionAnnotation]
-	existingRevision := osc.Annotations[mcbootstrap.MachineDeploymentRevision]
-	existingHash := osc.Annotations[OperatingSystemConfigMDHash]
+	return registryCredentials, hash, nil
+}
 
-	if currentRevision == existingRevision && osp.Spec.Version == osc.Annotations[OperatingSystemConfigVersionAnnotation] && existingHash == mdhash {
-		// Rotation is not required.
-		return nil
+// fetchRegistryCertificates returns the registry certificates of the -node-registry-certificates-secret and a hash over
+// them, which is empty if no certificates are configured.
+func (r *Reconciler) fetchRegistryCertificates(ctx context.Context) (map[string]containerruntime.RegistryCertificates, string, error) {
+	if r.nodeRegistryCertificatesSecret == "" {
+		return nil, "", nil
+	}
+
+	registryCertificates, err := containerruntime.GetRegistryCertificates(ctx, r.Client, r.nodeRegistryCertificatesSecret)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get registry certificates: %w", err)
 	}
 
-	// Delete the existing OSC and let the controller re-create them.
-	if err := r.Delete(ctx, osc); err != nil {
-		return fmt.Errorf("failed to delete OperatingSystemConfig %s: %v against MachineDeployment %w", oscName, md.Name, err)
+	if len(registryCertificates) == 0 {
+		return registryCertificates, "", nil
 	}
 
-	// Delete the existing secrets and let the controller re-create them.
-	return r.deleteGeneratedSecrets(ctx, md)
+	hash, err := resources.HashJSON(registryCertificates)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to json encode registry certificates: %w", err)
+	}
+
+	return registryCertificates, hash, nil
 }
 
-func (r *Reconciler) calculateAnnotationsHash(annotations map[string]string) (string, error) {
-	mdjson, err := json.Marshal(annotations)
+// calculateLabelsHash returns a hash over the labels of a machine deployment, it's empty if there are no labels.
+func calculateLabelsHash(labels map[string]string) (string, error) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+
+	hash, err := resources.HashJSON(labels)
 	if err != nil {
-		return "", fmt.Errorf("failed to json encode machinedeployment annotations: %w", err)
+		return "", fmt.Errorf("failed to json encode machinedeployment labels: %w", err)
 	}
 
-	mdhashBytes := sha256.Sum256(mdjson)
-	mdhash := hex.EncodeToString(mdhashBytes[:])
+	return hash, nil
+}
 
-	return mdhash, nil
+// calculateSecretContentHash returns a hash over the data of a generated cloud-config secret.
+func calculateSecretContentHash(secret *corev1.Secret) (string, error) {
+	hash, err := resources.HashJSON(secret.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to json encode data of secret %s: %w", secret.Name, err)
+	}
+
+	return hash, nil
 }
 
 func (r *Reconciler) checkOSP(md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) error {
@@ -551,18 +1380,110 @@ func filterMachineDeploymentPredicate() predicate.Predicate {
 	})
 }
 
-func oscRotationAnnotations(revision, mdhash, ospversion string, annotations map[string]string) map[string]string {
+// filterSecretPredicate filters for the secrets that are referenced in namespace/name form.
+func filterSecretPredicate(secretRefs ...string) predicate.TypedPredicate[*corev1.Secret] {
+	return predicate.NewTypedPredicateFuncs(func(secret *corev1.Secret) bool {
+		return slices.Contains(secretRefs, fmt.Sprintf("%s/%s", secret.Namespace, secret.Name))
+	})
+}
+
+// fileContentReferencedPredicate filters for the secrets and config maps that the files of an OSP or a fragment in their
+// namespace source their content from. The reader has to index OSPs and fragments by fileContentReferenceIndex.
+func fileContentReferencedPredicate[T ctrlruntimeclient.Object](reader ctrlruntimeclient.Reader, log *zap.SugaredLogger) predicate.TypedPredicate[T] {
+	return predicate.NewTypedPredicateFuncs(func(obj T) bool {
+		opts := []ctrlruntimeclient.ListOption{
+			ctrlruntimeclient.InNamespace(obj.GetNamespace()),
+			ctrlruntimeclient.MatchingFields{fileContentReferenceIndex: resources.FileContentReferenceOf(obj)},
+		}
+
+		osps := &osmv1alpha1.OperatingSystemProfileList{}
+		if err := reader.List(context.Background(), osps, opts...); err != nil {
+			// Mapping the object is more expensive, but doesn't miss a change.
+			log.Errorw("Failed to list OperatingSystemProfiles referencing file content", "object", ctrlruntimeclient.ObjectKeyFromObject(obj), zap.Error(err))
+			return true
+		}
+		if len(osps.Items) > 0 {
+			return true
+		}
+
+		fragments := &osmv1alpha1.OperatingSystemProfileFragmentList{}
+		if err := reader.List(context.Background(), fragments, opts...); err != nil {
+			log.Errorw("Failed to list OperatingSystemProfileFragments referencing file content", "object", ctrlruntimeclient.ObjectKeyFromObject(obj), zap.Error(err))
+			return true
+		}
+
+		return len(fragments.Items) > 0
+	})
+}
+
+func indexOperatingSystemProfileFileContent(obj ctrlruntimeclient.Object) []string {
+	return resources.FileContentReferences(obj.(*osmv1alpha1.OperatingSystemProfile))
+}
+
+func indexOperatingSystemProfileFragmentFileContent(obj ctrlruntimeclient.Object) []string {
+	return resources.FragmentFileContentReferences(obj.(*osmv1alpha1.OperatingSystemProfileFragment))
+}
+
+func oscRotationAnnotations(mdRevision, mdhash string, revision ospRevision, annotations map[string]string) map[string]string {
 	if annotations == nil {
 		annotations = map[string]string{}
 	}
 
-	annotations[mcbootstrap.MachineDeploymentRevision] = revision
+	annotations[mcbootstrap.MachineDeploymentRevision] = mdRevision
 	annotations[OperatingSystemConfigMDHash] = mdhash
-	annotations[OperatingSystemConfigVersionAnnotation] = ospversion
+	annotations[OperatingSystemConfigVersionAnnotation] = revision.version
+	// The remaining annotations are only set if they're used by the OSP, this keeps existing OSCs from being rotated.
+	if revision.baseVersions != "" {
+		annotations[OperatingSystemConfigBaseVersionsAnnotation] = revision.baseVersions
+	}
+	if revision.templateLibraryVersions != "" {
+		annotations[OperatingSystemConfigTemplateLibraryVersionsAnnotation] = revision.templateLibraryVersions
+	}
+	if revision.fragmentsHash != "" {
+		annotations[OperatingSystemConfigFragmentsHash] = revision.fragmentsHash
+	}
+	if revision.overridesHash != "" {
+		annotations[OperatingSystemConfigOverridesHash] = revision.overridesHash
+	}
+	if revision.fileContentsHash != "" {
+		annotations[OperatingSystemConfigFileContentsHash] = revision.fileContentsHash
+	}
+	if revision.parametersHash != "" {
+		annotations[OperatingSystemConfigParametersHash] = revision.parametersHash
+	}
+	if revision.labelsHash != "" {
+		annotations[OperatingSystemConfigMDLabelsHash] = revision.labelsHash
+	}
+	if revision.registryOverridesHash != "" {
+		annotations[OperatingSystemConfigRegistryOverridesHash] = revision.registryOverridesHash
+	}
+	if revision.registryCredentialsHash != "" {
+		annotations[OperatingSystemConfigRegistryCredentialsHash] = revision.registryCredentialsHash
+	}
+	if revision.registryCertificatesHash != "" {
+		annotations[OperatingSystemConfigRegistryCertificatesHash] = revision.registryCertificatesHash
+	}
 
 	return annotations
 }
 
+// ospRevisionFromAnnotations returns the OSP revision that an OSC was rendered from.
+func ospRevisionFromAnnotations(annotations map[string]string) ospRevision {
+	return ospRevision{
+		version:                  annotations[OperatingSystemConfigVersionAnnotation],
+		baseVersions:             annotations[OperatingSystemConfigBaseVersionsAnnotation],
+		templateLibraryVersions:  annotations[OperatingSystemConfigTemplateLibraryVersionsAnnotation],
+		fragmentsHash:            annotations[OperatingSystemConfigFragmentsHash],
+		overridesHash:            annotations[OperatingSystemConfigOverridesHash],
+		fileContentsHash:         annotations[OperatingSystemConfigFileContentsHash],
+		parametersHash:           annotations[OperatingSystemConfigParametersHash],
+		labelsHash:               annotations[OperatingSystemConfigMDLabelsHash],
+		registryOverridesHash:    annotations[OperatingSystemConfigRegistryOverridesHash],
+		registryCredentialsHash:  annotations[OperatingSystemConfigRegistryCredentialsHash],
+		registryCertificatesHash: annotations[OperatingSystemConfigRegistryCertificatesHash],
+	}
+}
+
 func validateMachineDeployment(md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile) error {
 	// Get providerConfig from machineDeployment
 	providerConfig := providerconfig.Config{}
@@ -590,5 +1511,20 @@ func validateMachineDeployment(md *clusterv1alpha1.MachineDeployment, osp *osmv1
 		return fmt.Errorf("OperatingSystemProfile %q does not support cloud provider %q", osp.Name, providerConfig.CloudProvider)
 	}
 
+	// Ensure that OSP supports the container runtime selected by the machine deployment
+	if containerRuntime := md.Annotations[resources.MachineDeploymentContainerRuntimeAnnotation]; containerRuntime != "" {
+		supportedContainerRuntime := false
+		for _, cr := range osp.Spec.ProvisioningConfig.SupportedContainerRuntimes {
+			if cr.Name == osmv1alpha1.ContainerRuntime(containerRuntime) {
+				supportedContainerRuntime = true
+				break
+			}
+		}
+
+		if !supportedContainerRuntime {
+			return fmt.Errorf("OperatingSystemProfile %q does not support container runtime %q", osp.Name, containerRuntime)
+		}
+	}
+
 	return nil
 }
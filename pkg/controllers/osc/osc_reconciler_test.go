@@ -24,6 +24,9 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -37,17 +40,24 @@ import (
 	"k8c.io/operating-system-manager/pkg/containerruntime"
 	"k8c.io/operating-system-manager/pkg/controllers/osc/resources"
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	"k8c.io/operating-system-manager/pkg/credentialprovider"
 	"k8c.io/operating-system-manager/pkg/generator"
 	testUtil "k8c.io/operating-system-manager/pkg/test/util"
 
 	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlruntimefakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/yaml"
 )
 
@@ -335,6 +345,7 @@ func TestReconciler_Reconcile(t *testing.T) {
 			NewClientBuilder().
 			WithScheme(scheme.Scheme).
 			WithObjects(objects...).
+			WithStatusSubresource(&osmv1alpha1.OperatingSystemConfig{}, &osmv1alpha1.OperatingSystemProfile{}).
 			Build()
 
 		reconciler := buildReconciler(fakeClient, testCase.config)
@@ -381,6 +392,11 @@ func TestReconciler_Reconcile(t *testing.T) {
 				APIVersion: osmv1alpha1.SchemeGroupVersion.String(),
 			}
 
+			// Transition times are not stable across test runs.
+			for i := range osc.Status.Conditions {
+				osc.Status.Conditions[i].LastTransitionTime = metav1.Time{}
+			}
+
 			buff, err := yaml.Marshal(osc)
 			if err != nil {
 				t.Fatal(err)
@@ -519,6 +535,7 @@ func TestOSCAndSecretRotation(t *testing.T) {
 			NewClientBuilder().
 			WithScheme(scheme.Scheme).
 			WithObjects(objects...).
+			WithStatusSubresource(&osmv1alpha1.OperatingSystemConfig{}, &osmv1alpha1.OperatingSystemProfile{}).
 			Build()
 
 		reconciler := buildReconciler(fakeClient, testCase.config)
@@ -743,6 +760,7 @@ func TestOSCAndSecretRotationOnHashOrVersionChange(t *testing.T) {
 				NewClientBuilder().
 				WithScheme(scheme.Scheme).
 				WithObjects(objects...).
+				WithStatusSubresource(&osmv1alpha1.OperatingSystemConfig{}, &osmv1alpha1.OperatingSystemProfile{}).
 				Build()
 
 			reconciler := buildReconciler(fakeClient, testConfig{namespace: "kube-system", containerRuntime: "containerd"})
@@ -773,6 +791,11 @@ func TestOSCAndSecretRotationOnHashOrVersionChange(t *testing.T) {
 			oldHash := osc.Annotations[OperatingSystemConfigMDHash]
 			oldVersion := osc.Annotations[OperatingSystemConfigVersionAnnotation]
 
+			// Reconciling updates the status of the OperatingSystemProfile.
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: osp.Namespace, Name: osp.Name}, osp); err != nil {
+				t.Fatalf("failed to refresh OperatingSystemProfile: %v", err)
+			}
+
 			testCase.mutate(t, ctx, fakeClient, md, osp)
 
 			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: md.Namespace, Name: md.Name}, md); err != nil {
@@ -850,6 +873,707 @@ func TestOSCAndSecretRotationOnHashOrVersionChange(t *testing.T) {
 	}
 }
 
+func TestOSCRenderFailureKeepsExistingOSC(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: osp.Namespace, Name: osp.Name}, osp); err != nil {
+		t.Fatalf("failed to refresh OperatingSystemProfile: %v", err)
+	}
+
+	renderedSpec := osp.Spec.DeepCopy()
+
+	// Bump the OSP version with a template that fails to render.
+	osp.Spec.Version += ".1"
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path: "/etc/broken",
+		Content: osmv1alpha1.FileContent{
+			Inline: &osmv1alpha1.FileContentInline{
+				Data: "{{ .DoesNotExist }}",
+			},
+		},
+	})
+	if err := fakeClient.Update(ctx, osp); err != nil {
+		t.Fatalf("failed to update OperatingSystemProfile: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err == nil {
+		t.Fatal("expected reconciling to fail")
+	}
+
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("expected existing osc to be kept: %v", err)
+	}
+
+	if osc.Status.OperatingSystemProfileVersion == osp.Spec.Version {
+		t.Fatal("expected osc to still be rendered from the previous OperatingSystemProfile version")
+	}
+
+	if !meta.IsStatusConditionTrue(osc.Status.Conditions, osmv1alpha1.OperatingSystemConfigRenderFailed) {
+		t.Fatalf("expected %s condition to be true, got %+v", osmv1alpha1.OperatingSystemConfigRenderFailed, osc.Status.Conditions)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: osp.Namespace, Name: osp.Name}, osp); err != nil {
+		t.Fatalf("failed to get OperatingSystemProfile: %v", err)
+	}
+
+	if len(osp.Status.MachineDeployments) != 1 {
+		t.Fatalf("expected one machine deployment in OperatingSystemProfile status, got %+v", osp.Status.MachineDeployments)
+	}
+
+	if status := osp.Status.MachineDeployments[0]; status.RenderError == "" || status.RenderedVersion != osc.Status.OperatingSystemProfileVersion {
+		t.Fatalf("expected render error and previously rendered version in OperatingSystemProfile status, got %+v", status)
+	}
+
+	provisioningSecretName := fmt.Sprintf(mcbootstrap.CloudConfigSecretNamePattern, md.Name, md.Namespace, resources.ProvisioningCloudConfig)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: mcbootstrap.CloudInitSettingsNamespace, Name: provisioningSecretName}, &corev1.Secret{}); err != nil {
+		t.Fatalf("expected existing provisioning secret to be kept: %v", err)
+	}
+
+	kubeletBootstrapConfigName := fmt.Sprintf("%s-kubelet-bootstrap-config", machineDeploymentKey(md))
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: mcbootstrap.CloudInitSettingsNamespace, Name: kubeletBootstrapConfigName}, &corev1.Secret{}); err != nil {
+		t.Fatalf("expected kubelet bootstrap config secret to be re-created: %v", err)
+	}
+
+	// Revert the OSP to the rendered version, the existing OSC is up-to-date again.
+	osp.Spec = *renderedSpec
+	if err := fakeClient.Update(ctx, osp); err != nil {
+		t.Fatalf("failed to update OperatingSystemProfile: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	if !meta.IsStatusConditionFalse(osc.Status.Conditions, osmv1alpha1.OperatingSystemConfigRenderFailed) {
+		t.Fatalf("expected %s condition to be cleared, got %+v", osmv1alpha1.OperatingSystemConfigRenderFailed, osc.Status.Conditions)
+	}
+}
+
+func TestBootstrapTokenRefreshWithUpToDateOSC(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	// Let the bootstrap token expire while the OSC is up-to-date.
+	token := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "bootstrap-token"}, token); err != nil {
+		t.Fatalf("failed to get bootstrap token: %v", err)
+	}
+	token.Data["expiration"] = []byte(metav1.Now().Add(-1 * time.Hour).Format(time.RFC3339))
+	if err := fakeClient.Update(ctx, token); err != nil {
+		t.Fatalf("failed to update bootstrap token: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	rotated := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, rotated); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+	if rotated.UID != osc.UID {
+		t.Fatal("expected the up-to-date osc to be kept")
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: "bootstrap-token"}, token); err != nil {
+		t.Fatalf("failed to get bootstrap token: %v", err)
+	}
+	expiration, err := time.Parse(time.RFC3339, string(token.Data["expiration"]))
+	if err != nil {
+		t.Fatalf("failed to parse expiration of bootstrap token: %v", err)
+	}
+	if time.Until(expiration) < 30*time.Minute {
+		t.Errorf("expected the bootstrap token to be refreshed, it expires at %s", expiration)
+	}
+}
+
+func TestOSCRenderFailureWithoutExistingOSC(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path: "/etc/broken",
+		Content: osmv1alpha1.FileContent{
+			Inline: &osmv1alpha1.FileContentInline{
+				Data: "{{ .DoesNotExist }}",
+			},
+		},
+	})
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+
+	if err := reconciler.reconcile(ctx, md); err == nil {
+		t.Fatal("expected reconciling to fail")
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: osp.Namespace, Name: osp.Name}, osp); err != nil {
+		t.Fatalf("failed to get OperatingSystemProfile: %v", err)
+	}
+
+	if len(osp.Status.MachineDeployments) != 1 {
+		t.Fatalf("expected one machine deployment in OperatingSystemProfile status, got %+v", osp.Status.MachineDeployments)
+	}
+
+	if status := osp.Status.MachineDeployments[0]; status.RenderError == "" || status.RenderedVersion != "" {
+		t.Fatalf("expected render error without a rendered version in OperatingSystemProfile status, got %+v", status)
+	}
+}
+
+func TestOperatingSystemProfileStatus(t *testing.T) {
+	const customOSPName = "osp-ubuntu-custom"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	customOSP := osp.DeepCopy()
+	customOSP.Name = customOSPName
+	customOSP.Spec.Version = "v2.0.0"
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+	md.Finalizers = []string{MachineDeploymentCleanupFinalizer}
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp, customOSP)
+
+	verifyStatus := func(name string, expected []osmv1alpha1.OperatingSystemProfileMachineDeployment) {
+		current := &osmv1alpha1.OperatingSystemProfile{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: name}, current); err != nil {
+			t.Fatalf("failed to get OperatingSystemProfile %s: %v", name, err)
+		}
+
+		if !reflect.DeepEqual(current.Status.MachineDeployments, expected) {
+			t.Fatalf("expected machine deployments %+v in status of OperatingSystemProfile %s, got %+v", expected, name, current.Status.MachineDeployments)
+		}
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	verifyStatus(ospUbuntu, []osmv1alpha1.OperatingSystemProfileMachineDeployment{
+		{Namespace: md.Namespace, Name: md.Name, RenderedVersion: osp.Spec.Version},
+	})
+	verifyStatus(customOSPName, nil)
+
+	// Switch the machine deployment to the custom OSP.
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: md.Namespace, Name: md.Name}, md); err != nil {
+		t.Fatalf("failed to get machine deployment: %v", err)
+	}
+	md.Annotations[resources.MachineDeploymentOSPAnnotation] = customOSPName
+	if err := fakeClient.Update(ctx, md); err != nil {
+		t.Fatalf("failed to update machine deployment: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile after switching OperatingSystemProfile: %v", err)
+	}
+
+	verifyStatus(ospUbuntu, nil)
+	verifyStatus(customOSPName, []osmv1alpha1.OperatingSystemProfileMachineDeployment{
+		{Namespace: md.Namespace, Name: md.Name, RenderedVersion: customOSP.Spec.Version},
+	})
+
+	if _, err := reconciler.handleMachineDeploymentCleanup(ctx, md); err != nil {
+		t.Fatalf("failed to clean up machine deployment: %v", err)
+	}
+
+	verifyStatus(customOSPName, nil)
+}
+
+func TestOSCAndSecretRotationOnReferencedFileContentChange(t *testing.T) {
+	const filePath = "/etc/referenced/config.toml"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path:        filePath,
+		Permissions: 600,
+		Content: osmv1alpha1.FileContent{
+			SecretRef: &osmv1alpha1.FileContentReference{
+				Name: "referenced-file-contents",
+				Key:  "config.toml",
+			},
+		},
+	})
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	referencedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "referenced-file-contents",
+			Namespace: osp.Namespace,
+		},
+		Data: map[string][]byte{
+			"config.toml": []byte("key = \"{{ .NotATemplate }}\"\n"),
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, referencedSecret, osp)
+
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	provisioningSecretName := fmt.Sprintf(mcbootstrap.CloudConfigSecretNamePattern, md.Name, md.Namespace, resources.ProvisioningCloudConfig)
+
+	// reconcileAndVerify reconciles the machine deployment and returns the file contents hash after verifying that
+	// the referenced content was written verbatim into the OSC.
+	reconcileAndVerify := func(expectedContent string) string {
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+
+		var found bool
+		for _, file := range osc.Spec.ProvisioningConfig.Files {
+			if file.Path != filePath {
+				continue
+			}
+			found = true
+			if file.Content.Inline == nil || file.Content.Inline.Data != expectedContent {
+				t.Fatalf("expected content of %s to be %q, got %+v", filePath, expectedContent, file.Content)
+			}
+			if file.Content.SecretRef != nil {
+				t.Fatalf("expected secret reference of %s to be resolved", filePath)
+			}
+		}
+		if !found {
+			t.Fatalf("expected file %s in provisioning config of osc", filePath)
+		}
+
+		provisioningSecret := &corev1.Secret{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: mcbootstrap.CloudInitSettingsNamespace, Name: provisioningSecretName}, provisioningSecret); err != nil {
+			t.Fatalf("failed to get provisioning secret: %v", err)
+		}
+
+		hash := osc.Annotations[OperatingSystemConfigFileContentsHash]
+		if hash == "" {
+			t.Fatal("expected file contents hash annotation on osc")
+		}
+		if hash != provisioningSecret.Annotations[OperatingSystemConfigFileContentsHash] {
+			t.Fatal("file contents hash for OSC and provisioning secret didn't match")
+		}
+
+		return hash
+	}
+
+	oldHash := reconcileAndVerify("key = \"{{ .NotATemplate }}\"\n")
+
+	referencedSecret.Data["config.toml"] = []byte("key = \"updated\"\n")
+	if err := fakeClient.Update(ctx, referencedSecret); err != nil {
+		t.Fatalf("failed to update referenced secret: %v", err)
+	}
+
+	newHash := reconcileAndVerify("key = \"updated\"\n")
+
+	if oldHash == newHash {
+		t.Fatal("expected file contents hash to change")
+	}
+
+	requests := reconciler.enqueueMachineDeploymentsUsingFileContent(ctx, referencedSecret)
+	if len(requests) != 1 || requests[0].Name != md.Name || requests[0].Namespace != md.Namespace {
+		t.Errorf("expected the machine deployment to be enqueued for the referenced secret, got %v", requests)
+	}
+
+	unreferencedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "referenced-file-contents",
+			Namespace: osp.Namespace,
+		},
+	}
+	if requests := reconciler.enqueueMachineDeploymentsUsingFileContent(ctx, unreferencedConfigMap); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for a config map with the name of the secret, got %v", requests)
+	}
+}
+
+func TestFileContentReferencedPredicate(t *testing.T) {
+	fileReferencing := func(path string, content osmv1alpha1.FileContent) osmv1alpha1.File {
+		return osmv1alpha1.File{Path: path, Content: content}
+	}
+
+	osp := &osmv1alpha1.OperatingSystemProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "osp-custom", Namespace: "kube-system"},
+		Spec: osmv1alpha1.OperatingSystemProfileSpec{
+			ProvisioningConfig: osmv1alpha1.OSPConfig{
+				Files: []osmv1alpha1.File{
+					fileReferencing("/etc/custom/config.toml", osmv1alpha1.FileContent{SecretRef: &osmv1alpha1.FileContentReference{Name: "custom-files", Key: "config.toml"}}),
+				},
+			},
+		},
+	}
+	fragment := &osmv1alpha1.OperatingSystemProfileFragment{
+		ObjectMeta: metav1.ObjectMeta{Name: "fragment", Namespace: "kube-system"},
+		Spec: osmv1alpha1.OperatingSystemProfileFragmentSpec{
+			BootstrapConfig: &osmv1alpha1.OSPFragmentConfig{
+				Files: []osmv1alpha1.File{
+					fileReferencing("/etc/fragment/motd", osmv1alpha1.FileContent{ConfigMapRef: &osmv1alpha1.FileContentReference{Name: "fragment-files", Key: "motd"}}),
+				},
+			},
+		},
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		osp.Name,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+	reconciler, fakeClient := newTestReconciler(t, md, osp, fragment)
+
+	secretPredicate := fileContentReferencedPredicate[*corev1.Secret](fakeClient, reconciler.log)
+	configMapPredicate := fileContentReferencedPredicate[*corev1.ConfigMap](fakeClient, reconciler.log)
+	objectMeta := func(namespace, name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name}
+	}
+
+	secrets := []struct {
+		secret   *corev1.Secret
+		expected bool
+	}{
+		{secret: &corev1.Secret{ObjectMeta: objectMeta("kube-system", "custom-files")}, expected: true},
+		{secret: &corev1.Secret{ObjectMeta: objectMeta("kube-system", "fragment-files")}, expected: false},
+		{secret: &corev1.Secret{ObjectMeta: objectMeta("kube-system", "cloud-config")}, expected: false},
+		{secret: &corev1.Secret{ObjectMeta: objectMeta("default", "custom-files")}, expected: false},
+	}
+	for _, testCase := range secrets {
+		if secretPredicate.Update(event.TypedUpdateEvent[*corev1.Secret]{ObjectOld: testCase.secret, ObjectNew: testCase.secret}) != testCase.expected {
+			t.Errorf("expected secret %s to be referenced: %t", ctrlruntimeclient.ObjectKeyFromObject(testCase.secret), testCase.expected)
+		}
+	}
+
+	configMaps := []struct {
+		configMap *corev1.ConfigMap
+		expected  bool
+	}{
+		{configMap: &corev1.ConfigMap{ObjectMeta: objectMeta("kube-system", "fragment-files")}, expected: true},
+		{configMap: &corev1.ConfigMap{ObjectMeta: objectMeta("kube-system", "custom-files")}, expected: false},
+		{configMap: &corev1.ConfigMap{ObjectMeta: objectMeta("kube-system", "operating-system-manager-leader-lock")}, expected: false},
+	}
+	for _, testCase := range configMaps {
+		if configMapPredicate.Update(event.TypedUpdateEvent[*corev1.ConfigMap]{ObjectOld: testCase.configMap, ObjectNew: testCase.configMap}) != testCase.expected {
+			t.Errorf("expected config map %s to be referenced: %t", ctrlruntimeclient.ObjectKeyFromObject(testCase.configMap), testCase.expected)
+		}
+	}
+}
+
+func TestOperatingSystemProfileExtends(t *testing.T) {
+	const (
+		ospCustom      = "osp-ubuntu-custom"
+		addedFile      = "/etc/custom/motd"
+		removedFile    = "/etc/systemd/journald.conf.d/max_disk_use.conf"
+		replacedFile   = "/opt/disable-swap.sh"
+		replacedScript = "#!/bin/bash\necho custom\n"
+	)
+
+	ctx := context.Background()
+	base := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(base, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	child := &osmv1alpha1.OperatingSystemProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ospCustom,
+			Namespace: base.Namespace,
+		},
+		Spec: osmv1alpha1.OperatingSystemProfileSpec{
+			OSName:              osmv1alpha1.OperatingSystemUbuntu,
+			Version:             "v1.0.0",
+			Extends:             ospUbuntu,
+			ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+			ProvisioningConfig: osmv1alpha1.OSPConfig{
+				Files: []osmv1alpha1.File{
+					{
+						Path:        addedFile,
+						Permissions: 644,
+						Content:     osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: "welcome"}},
+					},
+					{
+						Path:        replacedFile,
+						Permissions: 755,
+						Content:     osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: replacedScript}},
+					},
+				},
+				Remove: &osmv1alpha1.OSPConfigRemovals{
+					Files: []string{removedFile},
+				},
+			},
+		},
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospCustom,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, base, child)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		files[file.Path] = file.Content.Inline.Data
+	}
+	if _, ok := files[addedFile]; !ok {
+		t.Errorf("expected file %s of the extending OSP in the osc", addedFile)
+	}
+	if _, ok := files[removedFile]; ok {
+		t.Errorf("expected file %s to be removed from the osc", removedFile)
+	}
+	if files[replacedFile] != replacedScript {
+		t.Errorf("expected file %s to be replaced, got %q", replacedFile, files[replacedFile])
+	}
+	if osc.Spec.OSVersion != base.Spec.OSVersion {
+		t.Errorf("expected os version %q to be inherited from the base OSP, got %q", base.Spec.OSVersion, osc.Spec.OSVersion)
+	}
+	if len(osc.Spec.BootstrapConfig.Files) == 0 {
+		t.Error("expected bootstrap files to be inherited from the base OSP")
+	}
+
+	expectedBaseVersions := fmt.Sprintf("%s=%s", ospUbuntu, base.Spec.Version)
+	if osc.Annotations[OperatingSystemConfigBaseVersionsAnnotation] != expectedBaseVersions {
+		t.Fatalf("expected base versions %q, got %q", expectedBaseVersions, osc.Annotations[OperatingSystemConfigBaseVersionsAnnotation])
+	}
+
+	// Updating the base OSP rotates the OSC of the extending OSP.
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(base), base); err != nil {
+		t.Fatalf("failed to get base osp: %v", err)
+	}
+	base.Spec.Version = "v9.9.9"
+	if err := fakeClient.Update(ctx, base); err != nil {
+		t.Fatalf("failed to update base osp: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+	if osc.Annotations[OperatingSystemConfigBaseVersionsAnnotation] != ospUbuntu+"=v9.9.9" {
+		t.Fatalf("expected osc to be rotated after the base OSP was updated, got base versions %q", osc.Annotations[OperatingSystemConfigBaseVersionsAnnotation])
+	}
+
+	// A cycle in the chain of base OSPs is rejected.
+	base.Spec.Extends = ospCustom
+	if err := fakeClient.Update(ctx, base); err != nil {
+		t.Fatalf("failed to update base osp: %v", err)
+	}
+
+	err := reconciler.reconcile(ctx, md)
+	if err == nil || !strings.Contains(err.Error(), "cyclic") {
+		t.Fatalf("expected cyclic base chain error, got %v", err)
+	}
+}
+
+func TestOperatingSystemProfileExtendsIgnition(t *testing.T) {
+	const (
+		ospFlatcar = "osp-flatcar"
+		ospCustom  = "osp-flatcar-custom"
+		addedFile  = "/etc/custom/motd"
+	)
+
+	ctx := context.Background()
+	base := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(base, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospFlatcar)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	child := &osmv1alpha1.OperatingSystemProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ospCustom,
+			Namespace: base.Namespace,
+		},
+		Spec: osmv1alpha1.OperatingSystemProfileSpec{
+			OSName:              osmv1alpha1.OperatingSystemFlatcar,
+			Version:             "v1.0.0",
+			Extends:             ospFlatcar,
+			ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+			ProvisioningConfig: osmv1alpha1.OSPConfig{
+				Files: []osmv1alpha1.File{
+					{
+						Path:        addedFile,
+						Permissions: 644,
+						Content:     osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: "welcome"}},
+					},
+				},
+			},
+		},
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"flatcar-aws",
+		"kube-system",
+		ospCustom,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemFlatcar,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, base, child)
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	if osc.Spec.ProvisioningUtility != osmv1alpha1.ProvisioningUtilityIgnition {
+		t.Errorf("expected osc to be provisioned with %s, got %s", osmv1alpha1.ProvisioningUtilityIgnition, osc.Spec.ProvisioningUtility)
+	}
+	if !slices.ContainsFunc(osc.Spec.ProvisioningConfig.Files, func(file osmv1alpha1.File) bool { return file.Path == addedFile }) {
+		t.Errorf("expected file %s of the extending OSP in the osc", addedFile)
+	}
+
+	// A child that is defaulted to cloud-init can't extend an ignition OSP.
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(child), child); err != nil {
+		t.Fatalf("failed to get osp: %v", err)
+	}
+	child.Spec.ProvisioningUtility = osmv1alpha1.ProvisioningUtilityCloudInit
+	child.Spec.Version = "v1.0.1"
+	if err := fakeClient.Update(ctx, child); err != nil {
+		t.Fatalf("failed to update osp: %v", err)
+	}
+
+	err := reconciler.reconcile(ctx, md)
+	if err == nil || !strings.Contains(err.Error(), "cannot extend") {
+		t.Fatalf("expected provisioning utility mismatch error, got %v", err)
+	}
+}
+
 func TestMachineDeploymentDeletion(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -928,6 +1652,7 @@ func TestMachineDeploymentDeletion(t *testing.T) {
 			NewClientBuilder().
 			WithScheme(scheme.Scheme).
 			WithObjects(objects...).
+			WithStatusSubresource(&osmv1alpha1.OperatingSystemConfig{}, &osmv1alpha1.OperatingSystemProfile{}).
 			Build()
 
 		reconciler := buildReconciler(fakeClient, testCase.config)
@@ -1082,6 +1807,7 @@ func buildReconciler(fakeClient ctrlruntimeclient.Client, config testConfig) Rec
 		workerClient: fakeClient,
 
 		log:                   testUtil.DefaultLogger,
+		recorder:              &record.FakeRecorder{},
 		generator:             generator.NewDefaultCloudConfigGenerator(""),
 		namespace:             config.namespace,
 		caCert:                dummyCACert,
@@ -1094,3 +1820,2147 @@ func buildReconciler(fakeClient ctrlruntimeclient.Client, config testConfig) Rec
 		nodeNoProxy:           "http://test-no-proxy.com",
 	}
 }
+
+// newTestReconciler returns a reconciler for the machine deployment with a fake client that holds the objects, the
+// machine deployment, the cluster-info config map and the tokens that are required to reconcile it.
+func newTestReconciler(t *testing.T, md *v1alpha1.MachineDeployment, objects ...ctrlruntimeclient.Object) (Reconciler, ctrlruntimeclient.Client) {
+	t.Helper()
+
+	objects = append(objects,
+		md,
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-info",
+				Namespace: "kube-public",
+			},
+			Data: map[string]string{"kubeconfig": clusterInfoKubeconfig},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cloud-init-getter-token",
+				Namespace: "cloud-init-settings",
+			},
+			Data: map[string][]byte{
+				"token": []byte("top-secret"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bootstrap-token",
+				Namespace: "kube-system",
+				Labels:    map[string]string{"machinedeployment.k8s.io/name": fmt.Sprintf("%s-%s", md.Namespace, md.Name)},
+			},
+			Data: map[string][]byte{
+				"token-id":     []byte("test"),
+				"token-secret": []byte("test"),
+				"expiration":   []byte(metav1.Now().Add(10 * time.Hour).Format(time.RFC3339)),
+			},
+		},
+	)
+
+	fakeClient := ctrlruntimefakeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&osmv1alpha1.OperatingSystemConfig{}, &osmv1alpha1.OperatingSystemProfile{}).
+		WithIndex(&osmv1alpha1.OperatingSystemProfile{}, fileContentReferenceIndex, indexOperatingSystemProfileFileContent).
+		WithIndex(&osmv1alpha1.OperatingSystemProfileFragment{}, fileContentReferenceIndex, indexOperatingSystemProfileFragmentFileContent).
+		Build()
+
+	return buildReconciler(fakeClient, testConfig{namespace: "kube-system", containerRuntime: "containerd"}), fakeClient
+}
+
+func TestOperatingSystemProfileFragments(t *testing.T) {
+	const (
+		caFile   = "/etc/pki/custom-ca.crt"
+		caRunCMD = "update-ca-certificates"
+	)
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+	osp.Labels = map[string]string{"ca": "custom"}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+	md.Labels = map[string]string{"pool": "gpu"}
+
+	caFragment := &osmv1alpha1.OperatingSystemProfileFragment{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-ca", Namespace: osp.Namespace},
+		Spec: osmv1alpha1.OperatingSystemProfileFragmentSpec{
+			OperatingSystemProfileSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"ca": "custom"}},
+			ProvisioningConfig: &osmv1alpha1.OSPFragmentConfig{
+				Files: []osmv1alpha1.File{
+					{
+						Path:        caFile,
+						Permissions: 644,
+						Content:     osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: "-----BEGIN CERTIFICATE-----"}},
+					},
+				},
+				CloudInitModules: &osmv1alpha1.CloudInitModule{RunCMD: []string{caRunCMD}},
+			},
+		},
+	}
+	ignoredFragment := &osmv1alpha1.OperatingSystemProfileFragment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ignored", Namespace: osp.Namespace},
+		Spec: osmv1alpha1.OperatingSystemProfileFragmentSpec{
+			OperatingSystemProfileSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"ca": "custom"}},
+			MachineDeploymentSelector:      &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "cpu"}},
+			ProvisioningConfig: &osmv1alpha1.OSPFragmentConfig{
+				Files: []osmv1alpha1.File{
+					{
+						Path:        "/etc/ignored",
+						Permissions: 644,
+						Content:     osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: "ignored"}},
+					},
+				},
+			},
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp, caFragment, ignoredFragment)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		files[file.Path] = file.Content.Inline.Data
+	}
+	if _, ok := files[caFile]; !ok {
+		t.Errorf("expected file %s of the fragment in the osc", caFile)
+	}
+	if _, ok := files["/etc/ignored"]; ok {
+		t.Error("expected fragment with a non-matching machine deployment selector to be ignored")
+	}
+	if osc.Spec.ProvisioningConfig.CloudInitModules == nil || !slices.Contains(osc.Spec.ProvisioningConfig.CloudInitModules.RunCMD, caRunCMD) {
+		t.Errorf("expected runcmd %q of the fragment in the osc", caRunCMD)
+	}
+
+	fragmentsHash := osc.Annotations[OperatingSystemConfigFragmentsHash]
+	if fragmentsHash == "" {
+		t.Fatalf("expected %s annotation on the osc", OperatingSystemConfigFragmentsHash)
+	}
+
+	if requests := reconciler.enqueueMachineDeploymentsUsingFragment(ctx, caFragment); len(requests) != 1 || requests[0].Name != md.Name {
+		t.Errorf("expected the machine deployment to be enqueued for the matching fragment, got %v", requests)
+	}
+	if requests := reconciler.enqueueMachineDeploymentsUsingFragment(ctx, ignoredFragment); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for the non-matching fragment, got %v", requests)
+	}
+
+	// Updating a matching fragment rotates the OSC.
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(ignoredFragment), ignoredFragment); err != nil {
+		t.Fatalf("failed to get fragment: %v", err)
+	}
+	ignoredFragment.Spec.MachineDeploymentSelector.MatchLabels["pool"] = "gpu"
+	if err := fakeClient.Update(ctx, ignoredFragment); err != nil {
+		t.Fatalf("failed to update fragment: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+	if osc.Annotations[OperatingSystemConfigFragmentsHash] == fragmentsHash {
+		t.Fatal("expected osc to be rotated after a matching fragment was updated")
+	}
+
+	found := false
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		found = found || file.Path == "/etc/ignored"
+	}
+	if !found {
+		t.Error("expected file of the newly matching fragment in the osc")
+	}
+
+	// Units are only supported by Flatcar.
+	unitsFragment := &osmv1alpha1.OperatingSystemProfileFragment{
+		ObjectMeta: metav1.ObjectMeta{Name: "units", Namespace: osp.Namespace},
+		Spec: osmv1alpha1.OperatingSystemProfileFragmentSpec{
+			MachineDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+			ProvisioningConfig: &osmv1alpha1.OSPFragmentConfig{
+				Units: []osmv1alpha1.Unit{{Name: "custom.service"}},
+			},
+		},
+	}
+	if err := fakeClient.Create(ctx, unitsFragment); err != nil {
+		t.Fatalf("failed to create fragment: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err == nil {
+		t.Fatal("expected reconciling to fail for a fragment with units for an Ubuntu OSP")
+	}
+}
+
+func TestOperatingSystemConfigOverrides(t *testing.T) {
+	const (
+		modprobeFile = "/etc/modprobe.d/nvidia.conf"
+		sshKey       = "ssh-ed25519 AAAAoverride"
+	)
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	gpuOverride := &osmv1alpha1.OperatingSystemConfigOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "kube-system"},
+		Spec: osmv1alpha1.OperatingSystemConfigOverrideSpec{
+			MachineDeployments: []string{fmt.Sprintf("%s/%s", md.Namespace, md.Name)},
+			ProvisioningConfig: &osmv1alpha1.OSCOverrideConfig{
+				Files: []osmv1alpha1.File{
+					{
+						Path:        modprobeFile,
+						Permissions: 644,
+						Content:     osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: "options nvidia NVreg_EnableGpuFirmware=0"}},
+					},
+				},
+				UserSSHKeys: []string{sshKey},
+			},
+		},
+	}
+	otherOverride := &osmv1alpha1.OperatingSystemConfigOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "kube-system"},
+		Spec: osmv1alpha1.OperatingSystemConfigOverrideSpec{
+			// A machine deployment with the same name in another namespace doesn't match.
+			MachineDeployments:        []string{fmt.Sprintf("other/%s", md.Name)},
+			MachineDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "other"}},
+			ProvisioningConfig: &osmv1alpha1.OSCOverrideConfig{
+				UserSSHKeys: []string{"ssh-ed25519 AAAAother"},
+			},
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp, gpuOverride, otherOverride)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	if !slices.ContainsFunc(osc.Spec.ProvisioningConfig.Files, func(file osmv1alpha1.File) bool { return file.Path == modprobeFile }) {
+		t.Errorf("expected file %s of the override in the osc", modprobeFile)
+	}
+	if !slices.Contains(osc.Spec.ProvisioningConfig.UserSSHKeys, sshKey) {
+		t.Errorf("expected ssh key of the override in the osc, got %v", osc.Spec.ProvisioningConfig.UserSSHKeys)
+	}
+	if len(osc.Spec.ProvisioningConfig.UserSSHKeys) != 2 {
+		t.Errorf("expected ssh key of the non-matching override to be ignored, got %v", osc.Spec.ProvisioningConfig.UserSSHKeys)
+	}
+	if slices.ContainsFunc(osc.Spec.BootstrapConfig.Files, func(file osmv1alpha1.File) bool { return file.Path == modprobeFile }) {
+		t.Errorf("expected file %s only in the provisioning config", modprobeFile)
+	}
+
+	overridesHash := osc.Annotations[OperatingSystemConfigOverridesHash]
+	if overridesHash == "" {
+		t.Fatalf("expected %s annotation on the osc", OperatingSystemConfigOverridesHash)
+	}
+
+	if requests := reconciler.enqueueMachineDeploymentsUsingOverride(ctx, gpuOverride); len(requests) != 1 || requests[0].Name != md.Name {
+		t.Errorf("expected the machine deployment to be enqueued for the matching override, got %v", requests)
+	}
+	if requests := reconciler.enqueueMachineDeploymentsUsingOverride(ctx, otherOverride); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for the non-matching override, got %v", requests)
+	}
+
+	// Updating an override that doesn't match the machine deployment keeps the OSC.
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(otherOverride), otherOverride); err != nil {
+		t.Fatalf("failed to get override: %v", err)
+	}
+	otherOverride.Spec.ProvisioningConfig.UserSSHKeys = []string{"ssh-ed25519 AAAAchanged"}
+	if err := fakeClient.Update(ctx, otherOverride); err != nil {
+		t.Fatalf("failed to update override: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	existingOSC := &osmv1alpha1.OperatingSystemConfig{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, existingOSC); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+	if existingOSC.Annotations[OperatingSystemConfigOverridesHash] != overridesHash {
+		t.Fatal("expected osc not to be rotated after a non-matching override was updated")
+	}
+
+	// Updating a matching override rotates the OSC.
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(gpuOverride), gpuOverride); err != nil {
+		t.Fatalf("failed to get override: %v", err)
+	}
+	gpuOverride.Spec.ProvisioningConfig.Files[0].Content.Inline.Data = "options nvidia NVreg_EnableGpuFirmware=1"
+	if err := fakeClient.Update(ctx, gpuOverride); err != nil {
+		t.Fatalf("failed to update override: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+	if osc.Annotations[OperatingSystemConfigOverridesHash] == overridesHash {
+		t.Fatal("expected osc to be rotated after a matching override was updated")
+	}
+}
+
+func TestStaticNetworkConfig(t *testing.T) {
+	testCases := []struct {
+		name             string
+		osp              string
+		operatingSystem  providerconfig.OperatingSystem
+		ipFamily         mcnet.IPFamily
+		networkFields    map[string]interface{}
+		expectedError    string
+		expectedCommands []string
+	}{
+		{
+			name:             "netplan for ubuntu",
+			osp:              ospUbuntu,
+			operatingSystem:  providerconfig.OperatingSystemUbuntu,
+			expectedCommands: []string{"${STATIC_NETWORK_INTERFACE}:", "dhcp4: false", `- "10.10.0.20/24"`, `via: "10.10.0.1"`, "netplan apply"},
+		},
+		{
+			name:             "NetworkManager keyfile for rocky linux",
+			osp:              "osp-rockylinux",
+			operatingSystem:  providerconfig.OperatingSystemRockyLinux,
+			expectedCommands: []string{"interface-name=${STATIC_NETWORK_INTERFACE}", "method=manual", "address1=10.10.0.20/24,10.10.0.1", "dns=10.10.0.2;", "nmcli connection up static"},
+		},
+		{
+			name:            "unsupported operating system",
+			osp:             "osp-amzn2",
+			operatingSystem: providerconfig.OperatingSystemAmazonLinux2,
+			expectedError:   "static IP config is not supported",
+		},
+		{
+			name:            "dual-stack is not supported",
+			osp:             ospUbuntu,
+			operatingSystem: providerconfig.OperatingSystemUbuntu,
+			ipFamily:        mcnet.IPFamilyIPv4IPv6,
+			expectedError:   "static IP config doesn't support the IP family IPv4+IPv6",
+		},
+		{
+			name:            "CIDR of another IP family",
+			osp:             ospUbuntu,
+			operatingSystem: providerconfig.OperatingSystemUbuntu,
+			ipFamily:        mcnet.IPFamilyIPv6,
+			expectedError:   `CIDR "10.10.0.20/24" in static IP config doesn't belong to the IP family IPv6`,
+		},
+		{
+			name:            "netplan with routes",
+			osp:             ospUbuntu,
+			operatingSystem: providerconfig.OperatingSystemUbuntu,
+			networkFields:   map[string]interface{}{"routes": []interface{}{map[string]interface{}{"to": "10.20.0.0/16", "via": "10.10.0.254"}}},
+			expectedError:   `network config doesn't support routes, static IP config can only be rendered from cidr, gateway, dns, ipFamily`,
+		},
+		{
+			name:            "netplan with VLANs",
+			osp:             ospUbuntu,
+			operatingSystem: providerconfig.OperatingSystemUbuntu,
+			networkFields:   map[string]interface{}{"vlans": map[string]interface{}{"vlan100": map[string]interface{}{"id": 100, "link": "eth0"}}},
+			expectedError:   `network config doesn't support vlans, static IP config can only be rendered from cidr, gateway, dns, ipFamily`,
+		},
+		{
+			name:            "netplan with bonds",
+			osp:             ospUbuntu,
+			operatingSystem: providerconfig.OperatingSystemUbuntu,
+			networkFields:   map[string]interface{}{"bonds": map[string]interface{}{"bond0": map[string]interface{}{"interfaces": []string{"eth0", "eth1"}}}},
+			expectedError:   `network config doesn't support bonds, static IP config can only be rendered from cidr, gateway, dns, ipFamily`,
+		},
+		{
+			name:            "NetworkManager keyfile with routes",
+			osp:             "osp-rockylinux",
+			operatingSystem: providerconfig.OperatingSystemRockyLinux,
+			networkFields:   map[string]interface{}{"routes": []interface{}{map[string]interface{}{"to": "10.20.0.0/16", "via": "10.10.0.254"}}},
+			expectedError:   `network config doesn't support routes, static IP config can only be rendered from cidr, gateway, dns, ipFamily`,
+		},
+		{
+			name:            "NetworkManager keyfile with VLANs",
+			osp:             "osp-rockylinux",
+			operatingSystem: providerconfig.OperatingSystemRockyLinux,
+			networkFields:   map[string]interface{}{"vlans": map[string]interface{}{"vlan100": map[string]interface{}{"id": 100, "link": "eth0"}}},
+			expectedError:   `network config doesn't support vlans, static IP config can only be rendered from cidr, gateway, dns, ipFamily`,
+		},
+		{
+			name:            "NetworkManager keyfile with bonds",
+			osp:             "osp-rockylinux",
+			operatingSystem: providerconfig.OperatingSystemRockyLinux,
+			networkFields:   map[string]interface{}{"bonds": map[string]interface{}{"bond0": map[string]interface{}{"interfaces": []string{"eth0", "eth1"}}}},
+			expectedError:   `network config doesn't support bonds, static IP config can only be rendered from cidr, gateway, dns, ipFamily`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			osp := &osmv1alpha1.OperatingSystemProfile{}
+			if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", testCase.osp)); err != nil {
+				t.Fatalf("failed loading osp from testdata: %v", err)
+			}
+
+			md := generateMachineDeployment(
+				t,
+				"static",
+				"kube-system",
+				testCase.osp,
+				defaultKubeletVersion,
+				testCase.operatingSystem,
+				"aws",
+				runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+				nil,
+				mcnet.IPFamilyIPv4,
+			)
+
+			pconfig, err := providerconfig.GetConfig(md.Spec.Template.Spec.ProviderSpec)
+			if err != nil {
+				t.Fatalf("failed to get provider config: %v", err)
+			}
+			pconfig.Network.CIDR = "10.10.0.20/24"
+			pconfig.Network.Gateway = "10.10.0.1"
+			pconfig.Network.DNS.Servers = []string{"10.10.0.2"}
+			if testCase.ipFamily != "" {
+				pconfig.Network.IPFamily = testCase.ipFamily
+			}
+			md.Spec.Template.Spec.ProviderSpec.Value.Raw, err = json.Marshal(pconfig)
+			if err != nil {
+				t.Fatalf("failed to encode provider config: %v", err)
+			}
+			if testCase.networkFields != nil {
+				spec := map[string]interface{}{}
+				if err := json.Unmarshal(md.Spec.Template.Spec.ProviderSpec.Value.Raw, &spec); err != nil {
+					t.Fatalf("failed to decode provider config: %v", err)
+				}
+				network := spec["network"].(map[string]interface{})
+				for field, value := range testCase.networkFields {
+					network[field] = value
+				}
+				md.Spec.Template.Spec.ProviderSpec.Value.Raw, err = json.Marshal(spec)
+				if err != nil {
+					t.Fatalf("failed to encode provider config: %v", err)
+				}
+			}
+
+			reconciler, fakeClient := newTestReconciler(t, md, osp)
+			err = reconciler.reconcile(ctx, md)
+			if testCase.expectedError != "" {
+				if err == nil {
+					t.Fatal("expected reconcile to fail")
+				}
+				if !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			osc := &osmv1alpha1.OperatingSystemConfig{}
+			oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+				t.Fatalf("failed to get osc: %v", err)
+			}
+
+			index := slices.IndexFunc(osc.Spec.BootstrapConfig.Files, func(file osmv1alpha1.File) bool { return file.Path == "/opt/bin/bootstrap" })
+			if index == -1 {
+				t.Fatal("expected bootstrap script in the osc")
+			}
+
+			script := osc.Spec.BootstrapConfig.Files[index].Content.Inline.Data
+			for _, command := range testCase.expectedCommands {
+				if !strings.Contains(script, command) {
+					t.Errorf("expected %q in bootstrap script:\n%s", command, script)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeTuning(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+	osp.Spec.ProvisioningConfig.NodeTuning = &osmv1alpha1.NodeTuning{
+		Sysctls:       map[string]string{"vm.max_map_count": "262144", "net.core.somaxconn": "1024"},
+		KernelModules: []string{"nvme_tcp"},
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		map[string]string{
+			resources.MachineDeploymentSysctlsAnnotation:              "net.core.somaxconn=4096",
+			resources.MachineDeploymentKernelModulesAnnotation:        "dm_thin_pool",
+			resources.MachineDeploymentHugePagesAnnotation:            "2Mi=512,1Gi=2",
+			resources.MachineDeploymentTransparentHugePagesAnnotation: "madvise",
+		},
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		if file.Content.Inline != nil {
+			files[file.Path] = file.Content.Inline.Data
+		}
+	}
+
+	expected := map[string]string{
+		"/etc/sysctl.d/90-node-tuning.conf":    "net.core.somaxconn = 4096\nvm.max_map_count = 262144\n",
+		"/etc/modules-load.d/node-tuning.conf": "nvme_tcp\ndm_thin_pool\n",
+		"/opt/bin/node-tuning": `#!/usr/bin/env bash
+set -euo pipefail
+
+modprobe -a nvme_tcp dm_thin_pool
+sysctl --load /etc/sysctl.d/90-node-tuning.conf
+echo 2 > /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages
+if [ "$(cat /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages)" -lt 2 ]; then echo "failed to allocate 2 huge pages of size 1Gi" >&2; exit 1; fi
+echo 512 > /sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages
+if [ "$(cat /sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages)" -lt 512 ]; then echo "failed to allocate 512 huge pages of size 2Mi" >&2; exit 1; fi
+echo madvise > /sys/kernel/mm/transparent_hugepage/enabled
+`,
+		"/etc/systemd/system/kubelet.service.d/10-node-tuning.conf": "[Service]\nExecStartPre=/opt/bin/node-tuning\n",
+	}
+	for path, content := range expected {
+		if files[path] != content {
+			t.Errorf("unexpected content of %s, expected:\n%s\ngot:\n%s", path, content, files[path])
+		}
+	}
+}
+
+func TestNodeTuningReservedHugePages(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		hugePages              []osmv1alpha1.HugePages
+		annotations            map[string]string
+		expectedSystemReserved map[string]string
+		expectedError          bool
+	}{
+		{
+			name:      "reserved huge pages",
+			hugePages: []osmv1alpha1.HugePages{{Size: "2Mi", Count: 512, Reserved: 64}, {Size: "1048576Ki", Count: 2, Reserved: 1}},
+			expectedSystemReserved: map[string]string{
+				"cpu":               "200m",
+				"ephemeral-storage": "1Gi",
+				"memory":            "200Mi",
+				"hugepages-2Mi":     "128Mi",
+				"hugepages-1Gi":     "1Gi",
+			},
+		},
+		{
+			name:        "reserved huge pages with system reserved resources of the machine deployment",
+			hugePages:   []osmv1alpha1.HugePages{{Size: "2Mi", Count: 512, Reserved: 64}},
+			annotations: map[string]string{mcsdkcommon.KubeletConfigAnnotationPrefixV1 + "/" + mcsdkcommon.SystemReservedKubeletConfig: "cpu=500m,memory=1Gi"},
+			expectedSystemReserved: map[string]string{
+				"cpu":           "500m",
+				"memory":        "1Gi",
+				"hugepages-2Mi": "128Mi",
+			},
+		},
+		{
+			name:      "no reserved huge pages",
+			hugePages: []osmv1alpha1.HugePages{{Size: "2Mi", Count: 512}},
+			expectedSystemReserved: map[string]string{
+				"cpu":               "200m",
+				"ephemeral-storage": "1Gi",
+				"memory":            "200Mi",
+			},
+		},
+		{
+			name:          "more reserved than allocated huge pages",
+			hugePages:     []osmv1alpha1.HugePages{{Size: "2Mi", Count: 64, Reserved: 128}},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			osp := &osmv1alpha1.OperatingSystemProfile{}
+			if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+				t.Fatalf("failed loading osp from testdata: %v", err)
+			}
+			osp.Spec.ProvisioningConfig.NodeTuning = &osmv1alpha1.NodeTuning{HugePages: testCase.hugePages}
+
+			md := generateMachineDeployment(
+				t,
+				"ubuntu-aws",
+				"kube-system",
+				ospUbuntu,
+				defaultKubeletVersion,
+				providerconfig.OperatingSystemUbuntu,
+				"aws",
+				runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+				testCase.annotations,
+				mcnet.IPFamilyIPv4,
+			)
+
+			reconciler, fakeClient := newTestReconciler(t, md, osp)
+			err := reconciler.reconcile(ctx, md)
+			if testCase.expectedError {
+				if err == nil {
+					t.Fatal("expected reconciling to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			osc := &osmv1alpha1.OperatingSystemConfig{}
+			oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+				t.Fatalf("failed to get osc: %v", err)
+			}
+
+			var kubeletConfig struct {
+				SystemReserved map[string]string `json:"systemReserved"`
+			}
+			for _, file := range osc.Spec.ProvisioningConfig.Files {
+				if file.Path == "/etc/kubernetes/kubelet.conf" {
+					if err := yaml.Unmarshal([]byte(file.Content.Inline.Data), &kubeletConfig); err != nil {
+						t.Fatalf("failed to decode kubelet configuration: %v", err)
+					}
+				}
+			}
+			if !reflect.DeepEqual(kubeletConfig.SystemReserved, testCase.expectedSystemReserved) {
+				t.Errorf("expected system reserved resources %v, got %v", testCase.expectedSystemReserved, kubeletConfig.SystemReserved)
+			}
+		})
+	}
+}
+
+func TestNodeTuningOfBootstrapOnlyOSP(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+"osp-flatcar-cloud-init.yaml"); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"flatcar-anexia",
+		"kube-system",
+		osp.Name,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemFlatcar,
+		"anexia",
+		runtime.RawExtension{Raw: []byte(`{}`)},
+		map[string]string{
+			resources.MachineDeploymentSysctlsAnnotation:       "vm.max_map_count=262144",
+			resources.MachineDeploymentKernelModulesAnnotation: "dm_thin_pool",
+		},
+		mcnet.IPFamilyIPv4,
+	)
+
+	// The OSP is provisioned with cloud-init, which the machine deployment has to request for Flatcar.
+	providerConfig := providerconfig.Config{}
+	if err := json.Unmarshal(md.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerConfig); err != nil {
+		t.Fatalf("failed to decode provider config: %v", err)
+	}
+	providerConfig.OperatingSystemSpec = runtime.RawExtension{Raw: []byte(`{"provisioningUtility": "cloud-init"}`)}
+	raw, err := json.Marshal(providerConfig)
+	if err != nil {
+		t.Fatalf("failed to encode provider config: %v", err)
+	}
+	md.Spec.Template.Spec.ProviderSpec.Value.Raw = raw
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	// The OSP keeps its node tuning in the bootstrap config, the annotations have to be applied there as well.
+	files := map[string]string{}
+	for _, file := range osc.Spec.BootstrapConfig.Files {
+		if file.Content.Inline != nil {
+			files[file.Path] = file.Content.Inline.Data
+		}
+	}
+	if !strings.Contains(files["/etc/sysctl.d/90-node-tuning.conf"], "vm.max_map_count = 262144\n") {
+		t.Errorf("expected the sysctl annotation in the bootstrap config, got:\n%s", files["/etc/sysctl.d/90-node-tuning.conf"])
+	}
+	if !strings.HasSuffix(files["/etc/modules-load.d/node-tuning.conf"], "br_netfilter\ndm_thin_pool\n") {
+		t.Errorf("expected the kernel module annotation in the bootstrap config, got:\n%s", files["/etc/modules-load.d/node-tuning.conf"])
+	}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		if file.Path == "/etc/sysctl.d/90-node-tuning.conf" {
+			t.Errorf("expected no node tuning in the provisioning config")
+		}
+	}
+}
+
+func TestNodeTuningRejectsInvalidAnnotations(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+	}{
+		{
+			name:        "kernel module with shell command",
+			annotations: map[string]string{resources.MachineDeploymentKernelModulesAnnotation: "nvme_tcp;$(reboot)"},
+		},
+		{
+			name:        "sysctl key with line break",
+			annotations: map[string]string{resources.MachineDeploymentSysctlsAnnotation: "vm.max_map_count\nkernel.modules_disabled=1"},
+		},
+		{
+			name:        "sysctl value with line break",
+			annotations: map[string]string{resources.MachineDeploymentSysctlsAnnotation: "vm.max_map_count=1\nkernel.modules_disabled = 1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			osp := &osmv1alpha1.OperatingSystemProfile{}
+			if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+				t.Fatalf("failed loading osp from testdata: %v", err)
+			}
+
+			md := generateMachineDeployment(
+				t,
+				"ubuntu-aws",
+				"kube-system",
+				ospUbuntu,
+				defaultKubeletVersion,
+				providerconfig.OperatingSystemUbuntu,
+				"aws",
+				runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+				testCase.annotations,
+				mcnet.IPFamilyIPv4,
+			)
+
+			reconciler, _ := newTestReconciler(t, md, osp)
+			if err := reconciler.reconcile(context.Background(), md); err == nil {
+				t.Fatal("expected reconciling to fail")
+			}
+		})
+	}
+}
+
+func TestFileAndUnitSelectors(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	inline := func(path, data string, when *osmv1alpha1.Selector) osmv1alpha1.File {
+		return osmv1alpha1.File{
+			Path:    path,
+			When:    when,
+			Content: osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: data}},
+		}
+	}
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files,
+		inline("/etc/aws", "aws", &osmv1alpha1.Selector{CloudProviders: []osmv1alpha1.CloudProvider{osmv1alpha1.CloudProviderAWS}}),
+		// The template of a file that isn't selected is never rendered.
+		inline("/etc/azure", "{{ .Undefined.Field }}", &osmv1alpha1.Selector{CloudProviders: []osmv1alpha1.CloudProvider{osmv1alpha1.CloudProviderAzure}}),
+		inline("/etc/noble", "noble", &osmv1alpha1.Selector{OSVersions: ">= 24.04"}),
+		inline("/etc/jammy", "jammy", &osmv1alpha1.Selector{OSVersions: "< 24.04"}),
+		inline("/etc/kubernetes-1.31", "1.31", &osmv1alpha1.Selector{KubernetesVersions: "~1.31"}),
+		inline("/etc/kubernetes-1.32", "1.32", &osmv1alpha1.Selector{KubernetesVersions: ">= 1.32"}),
+		inline("/etc/amd64", "amd64", &osmv1alpha1.Selector{Architectures: []string{"amd64"}}),
+		inline("/etc/arm64", "arm64", &osmv1alpha1.Selector{Architectures: []string{"arm64"}}),
+		inline("/etc/gpu", "gpu", &osmv1alpha1.Selector{MachineDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}}}),
+		inline("/etc/storage", "storage", &osmv1alpha1.Selector{MachineDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"storage": "true"}}}),
+	)
+	osp.Spec.ProvisioningConfig.Units = []osmv1alpha1.Unit{
+		{Name: "aws.service", When: &osmv1alpha1.Selector{CloudProviders: []osmv1alpha1.CloudProvider{osmv1alpha1.CloudProviderAWS}}},
+		{Name: "arm64.service", When: &osmv1alpha1.Selector{Architectures: []string{"arm64"}}},
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+	md.Labels = map[string]string{"gpu": "true"}
+	md.Spec.Template.Labels = map[string]string{corev1.LabelArchStable: "arm64"}
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	files := map[string]osmv1alpha1.File{}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		files[file.Path] = file
+	}
+
+	for path, selected := range map[string]bool{
+		"/etc/aws":             true,
+		"/etc/azure":           false,
+		"/etc/noble":           true,
+		"/etc/jammy":           false,
+		"/etc/kubernetes-1.31": true,
+		"/etc/kubernetes-1.32": false,
+		"/etc/amd64":           false,
+		"/etc/arm64":           true,
+		"/etc/gpu":             true,
+		"/etc/storage":         false,
+	} {
+		file, ok := files[path]
+		if ok != selected {
+			t.Errorf("expected file %s to be selected: %t", path, selected)
+		}
+		if ok && file.When != nil {
+			t.Errorf("expected the selector of file %s to be removed", path)
+		}
+	}
+
+	units := osc.Spec.ProvisioningConfig.Units
+	if len(units) != 2 || units[0].Name != "aws.service" || units[1].Name != "arm64.service" || units[0].When != nil || units[1].When != nil {
+		t.Errorf("expected the aws.service and arm64.service units without selector to be selected, got: %+v", units)
+	}
+}
+
+func TestArchitectureSelectorRequiresArchitectureLabel(t *testing.T) {
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path:    "/etc/amd64",
+		When:    &osmv1alpha1.Selector{Architectures: []string{"amd64"}},
+		Content: osmv1alpha1.FileContent{Inline: &osmv1alpha1.FileContentInline{Data: "amd64"}},
+	})
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, _ := newTestReconciler(t, md, osp)
+	if err := reconciler.reconcile(context.Background(), md); err == nil {
+		t.Fatal("expected reconciling to fail for an architecture selector without the architecture label")
+	}
+}
+
+func TestOperatingSystemProfileParameters(t *testing.T) {
+	const filePath = "/etc/proxy.conf"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	osp.Spec.Parameters = []osmv1alpha1.Parameter{
+		{Name: "proxyHost", Type: osmv1alpha1.ParameterTypeString, Required: true},
+		{Name: "proxyPort", Type: osmv1alpha1.ParameterTypeInteger, Default: &apiextensionsv1.JSON{Raw: []byte(`3128`)}},
+		{Name: "debug", Type: osmv1alpha1.ParameterTypeBoolean, Default: &apiextensionsv1.JSON{Raw: []byte(`false`)}},
+	}
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path: filePath,
+		Content: osmv1alpha1.FileContent{
+			Inline: &osmv1alpha1.FileContentInline{
+				Data: `proxy={{ .Params.proxyHost }}:{{ add .Params.proxyPort 1 }}{{ if .Params.debug }} debug{{ end }}`,
+			},
+		},
+	})
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		map[string]string{
+			resources.MachineDeploymentParametersAnnotation:          "debug=true",
+			resources.MachineDeploymentParametersConfigMapAnnotation: "osp-parameters",
+		},
+		mcnet.IPFamilyIPv4,
+	)
+
+	parameters := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osp-parameters",
+			Namespace: md.Namespace,
+		},
+		Data: map[string]string{
+			"proxyHost": "proxy.example.com",
+			"debug":     "false",
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, parameters, osp)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	// reconcileAndVerify reconciles the machine deployment and returns the parameters hash after verifying that the
+	// parameter values were rendered into the OSC.
+	reconcileAndVerify := func(expectedContent string) string {
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+
+		var content string
+		for _, file := range osc.Spec.ProvisioningConfig.Files {
+			if file.Path == filePath {
+				content = file.Content.Inline.Data
+			}
+		}
+		if content != expectedContent {
+			t.Fatalf("expected content of %s to be %q, got %q", filePath, expectedContent, content)
+		}
+
+		hash := osc.Annotations[OperatingSystemConfigParametersHash]
+		if hash == "" {
+			t.Fatal("expected parameters hash annotation on osc")
+		}
+		return hash
+	}
+
+	// The annotation takes precedence over the config map, and the default is used for the port.
+	oldHash := reconcileAndVerify("proxy=proxy.example.com:3129 debug")
+
+	parameters.Data["proxyPort"] = "8080"
+	if err := fakeClient.Update(ctx, parameters); err != nil {
+		t.Fatalf("failed to update parameters config map: %v", err)
+	}
+
+	newHash := reconcileAndVerify("proxy=proxy.example.com:8081 debug")
+	if oldHash == newHash {
+		t.Fatal("expected parameters hash to change")
+	}
+
+	// The machine deployment is reconciled when the config map that it names changes, but not for other config maps.
+	requests := reconciler.enqueueMachineDeploymentsReferencing(ctx, parameters, resources.MachineDeploymentParametersConfigMapAnnotation)
+	if len(requests) != 1 || requests[0].NamespacedName != ctrlruntimeclient.ObjectKeyFromObject(md) {
+		t.Errorf("expected the machine deployment to be enqueued for the parameters config map, got %v", requests)
+	}
+	otherConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-parameters", Namespace: md.Namespace}}
+	if requests := reconciler.enqueueMachineDeploymentsReferencing(ctx, otherConfigMap, resources.MachineDeploymentParametersConfigMapAnnotation); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for a config map that it doesn't name, got %v", requests)
+	}
+	foreignConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: parameters.Name, Namespace: "default"}}
+	if requests := reconciler.enqueueMachineDeploymentsReferencing(ctx, foreignConfigMap, resources.MachineDeploymentParametersConfigMapAnnotation); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for a config map in another namespace, got %v", requests)
+	}
+
+	parameters.Data["proxyPort"] = "not-a-number"
+	if err := fakeClient.Update(ctx, parameters); err != nil {
+		t.Fatalf("failed to update parameters config map: %v", err)
+	}
+
+	if err := reconciler.reconcile(ctx, md); err == nil || !strings.Contains(err.Error(), `invalid value of parameter "proxyPort"`) {
+		t.Fatalf("expected invalid parameter value to fail rendering, got: %v", err)
+	}
+}
+
+func TestMachineDeploymentTemplateViews(t *testing.T) {
+	const filePath = "/etc/machine-deployment"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path: filePath,
+		Content: osmv1alpha1.FileContent{
+			Inline: &osmv1alpha1.FileContentInline{
+				Data: `{{ .MachineDeployment.Namespace }}/{{ .MachineDeployment.Name }} replicas={{ .MachineDeployment.Replicas }} ` +
+					`pool={{ .MachineDeployment.Labels.pool }} {{ .ProviderSpec.CloudProvider }}/{{ .ProviderSpec.OperatingSystem }} ` +
+					`zone={{ .ProviderSpec.CloudProviderSpec.availabilityZone }}`,
+			},
+		},
+	})
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+	md.Labels = map[string]string{"pool": "general"}
+	md.Spec.Replicas = ptr.To[int32](3)
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	// reconcileAndVerify reconciles the machine deployment and returns the labels hash after verifying the rendered
+	// content of the file.
+	reconcileAndVerify := func(expectedContent string) string {
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+
+		var content string
+		for _, file := range osc.Spec.ProvisioningConfig.Files {
+			if file.Path == filePath {
+				content = file.Content.Inline.Data
+			}
+		}
+		if content != expectedContent {
+			t.Fatalf("expected content of %s to be %q, got %q", filePath, expectedContent, content)
+		}
+
+		return osc.Annotations[OperatingSystemConfigMDLabelsHash]
+	}
+
+	oldHash := reconcileAndVerify("kube-system/ubuntu-aws replicas=3 pool=general aws/ubuntu zone=eu-central-1b")
+
+	// Changing the labels rotates the OSC.
+	md.Labels["pool"] = "gpu"
+	newHash := reconcileAndVerify("kube-system/ubuntu-aws replicas=3 pool=gpu aws/ubuntu zone=eu-central-1b")
+	if oldHash == newHash {
+		t.Fatal("expected labels hash to change")
+	}
+
+	// OSPs that don't use the labels aren't rotated when the labels change.
+	current := &osmv1alpha1.OperatingSystemProfile{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: osp.Name}, current); err != nil {
+		t.Fatalf("failed to get osp: %v", err)
+	}
+	for i, file := range current.Spec.ProvisioningConfig.Files {
+		if file.Path == filePath {
+			current.Spec.ProvisioningConfig.Files[i].Content.Inline.Data = "{{ .MachineDeployment.Name }}"
+		}
+	}
+	if err := fakeClient.Update(ctx, current); err != nil {
+		t.Fatalf("failed to update osp: %v", err)
+	}
+
+	if hash := reconcileAndVerify("ubuntu-aws"); hash != "" {
+		t.Fatalf("expected no labels hash for an OSP that doesn't use the labels, got %q", hash)
+	}
+	md.Labels["pool"] = "general"
+	if hash := reconcileAndVerify("ubuntu-aws"); hash != "" {
+		t.Fatalf("expected no labels hash for an OSP that doesn't use the labels, got %q", hash)
+	}
+}
+
+func TestTemplateLibraries(t *testing.T) {
+	const filePath = "/etc/greeting"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	osp.Spec.ProvisioningConfig.TemplateLibraries = []string{"common"}
+	osp.Spec.ProvisioningConfig.Templates["farewell"] = "bye from the osp"
+	osp.Spec.ProvisioningConfig.Files = append(osp.Spec.ProvisioningConfig.Files, osmv1alpha1.File{
+		Path: filePath,
+		Content: osmv1alpha1.FileContent{
+			Inline: &osmv1alpha1.FileContentInline{
+				Data: `{{ template "greeting" }}, {{ template "farewell" }}`,
+			},
+		},
+	})
+
+	library := &osmv1alpha1.TemplateLibrary{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "common",
+			Namespace: osp.Namespace,
+		},
+		Spec: osmv1alpha1.TemplateLibrarySpec{
+			Version: "v1.0.0",
+			Templates: map[string]string{
+				"greeting": "hello from the library",
+				"farewell": "bye from the library",
+			},
+		},
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, library, osp)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	// reconcileAndVerify reconciles the machine deployment and returns the template library versions after verifying
+	// the rendered content of the file and the versions without the hashes of the templates.
+	reconcileAndVerify := func(expectedContent, expectedVersions string) string {
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+
+		var content string
+		for _, file := range osc.Spec.ProvisioningConfig.Files {
+			if file.Path == filePath {
+				content = file.Content.Inline.Data
+			}
+		}
+		if content != expectedContent {
+			t.Fatalf("expected content of %s to be %q, got %q", filePath, expectedContent, content)
+		}
+
+		versions := osc.Annotations[OperatingSystemConfigTemplateLibraryVersionsAnnotation]
+		if !strings.HasPrefix(versions, expectedVersions+"@") {
+			t.Fatalf("expected template library versions %q, got %q", expectedVersions, versions)
+		}
+		return versions
+	}
+
+	// Templates of the OSP take precedence over the ones of the library.
+	oldVersions := reconcileAndVerify("\nhello from the library, \nbye from the osp", "common=v1.0.0")
+
+	if requests := reconciler.enqueueMachineDeploymentsUsingTemplateLibrary(ctx, library); len(requests) != 1 || requests[0].Name != md.Name {
+		t.Errorf("expected the machine deployment to be enqueued for the imported template library, got %v", requests)
+	}
+	unusedLibrary := &osmv1alpha1.TemplateLibrary{ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: library.Namespace}}
+	if requests := reconciler.enqueueMachineDeploymentsUsingTemplateLibrary(ctx, unusedLibrary); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for a template library that isn't imported, got %v", requests)
+	}
+
+	// Updating the library rotates the OSC.
+	library.Spec.Version = "v1.1.0"
+	library.Spec.Templates["greeting"] = "hi from the library"
+	if err := fakeClient.Update(ctx, library); err != nil {
+		t.Fatalf("failed to update template library: %v", err)
+	}
+
+	newVersions := reconcileAndVerify("\nhi from the library, \nbye from the osp", "common=v1.1.0")
+	if oldVersions == newVersions {
+		t.Fatal("expected template library versions to change")
+	}
+
+	// Changing the templates without bumping the version rotates the OSC as well.
+	library.Spec.Templates["greeting"] = "hey from the library"
+	if err := fakeClient.Update(ctx, library); err != nil {
+		t.Fatalf("failed to update template library: %v", err)
+	}
+
+	if versions := reconcileAndVerify("\nhey from the library, \nbye from the osp", "common=v1.1.0"); versions == newVersions {
+		t.Fatal("expected template library versions to change without a version bump")
+	}
+
+	if err := fakeClient.Delete(ctx, library); err != nil {
+		t.Fatalf("failed to delete template library: %v", err)
+	}
+	if err := reconciler.reconcile(ctx, md); err == nil || !strings.Contains(err.Error(), `TemplateLibrary "common" imported by OperatingSystemProfile`) {
+		t.Fatalf("expected missing template library to fail the reconciliation, got: %v", err)
+	}
+}
+
+func TestContainerRuntimeAnnotation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		osp              string
+		operatingSystem  providerconfig.OperatingSystem
+		containerRuntime string
+		expectedError    string
+		expectedFiles    map[string]string
+		unexpectedFiles  []string
+	}{
+		{
+			name:            "default container runtime",
+			osp:             "osp-rockylinux",
+			operatingSystem: providerconfig.OperatingSystemRockyLinux,
+			expectedFiles: map[string]string{
+				"/etc/containerd/config.toml":         "io.containerd.cri.v1.images",
+				"/etc/systemd/system/kubelet.service": "--container-runtime-endpoint=unix:///run/containerd/containerd.sock",
+			},
+			unexpectedFiles: []string{"/etc/crio/crio.conf.d/10-osm.conf"},
+		},
+		{
+			name:             "crio selected by the machine deployment",
+			osp:              "osp-rockylinux",
+			operatingSystem:  providerconfig.OperatingSystemRockyLinux,
+			containerRuntime: "crio",
+			expectedFiles: map[string]string{
+				"/etc/crio/crio.conf.d/10-osm.conf":   `cgroup_manager = "systemd"`,
+				"/etc/crictl.yaml":                    "unix:///var/run/crio/crio.sock",
+				"/etc/systemd/system/kubelet.service": "--container-runtime-endpoint=unix:///var/run/crio/crio.sock",
+				"/opt/bin/setup":                      "CRIO_VERSION=v1.31",
+			},
+			unexpectedFiles: []string{"/etc/containerd/config.toml"},
+		},
+		{
+			name:             "crio not supported by the OSP",
+			osp:              ospUbuntu,
+			operatingSystem:  providerconfig.OperatingSystemUbuntu,
+			containerRuntime: "crio",
+			expectedError:    `does not support container runtime "crio"`,
+		},
+		{
+			name:             "unknown container runtime",
+			osp:              "osp-rockylinux",
+			operatingSystem:  providerconfig.OperatingSystemRockyLinux,
+			containerRuntime: "docker",
+			expectedError:    `does not support container runtime "docker"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			osp := &osmv1alpha1.OperatingSystemProfile{}
+			if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", testCase.osp)); err != nil {
+				t.Fatalf("failed loading osp from testdata: %v", err)
+			}
+
+			annotations := map[string]string{}
+			if testCase.containerRuntime != "" {
+				annotations[resources.MachineDeploymentContainerRuntimeAnnotation] = testCase.containerRuntime
+			}
+
+			md := generateMachineDeployment(
+				t,
+				"runtime",
+				"kube-system",
+				testCase.osp,
+				defaultKubeletVersion,
+				testCase.operatingSystem,
+				"aws",
+				runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+				annotations,
+				mcnet.IPFamilyIPv4,
+			)
+
+			reconciler, fakeClient := newTestReconciler(t, md, osp)
+			err := reconciler.reconcile(ctx, md)
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error containing %q, got: %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			osc := &osmv1alpha1.OperatingSystemConfig{}
+			oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+				t.Fatalf("failed to get osc: %v", err)
+			}
+
+			files := map[string]string{}
+			for _, file := range osc.Spec.ProvisioningConfig.Files {
+				files[file.Path] = file.Content.Inline.Data
+			}
+
+			for path, expected := range testCase.expectedFiles {
+				content, ok := files[path]
+				if !ok {
+					t.Fatalf("expected file %s to be rendered", path)
+				}
+				if !strings.Contains(content, expected) {
+					t.Errorf("expected file %s to contain %q, got:\n%s", path, expected, content)
+				}
+			}
+
+			for _, path := range testCase.unexpectedFiles {
+				if _, ok := files[path]; ok {
+					t.Errorf("expected file %s not to be rendered", path)
+				}
+			}
+		})
+	}
+}
+
+func TestRuntimeHandlers(t *testing.T) {
+	const (
+		configPath  = "/etc/containerd/config.toml"
+		kubeletPath = "/etc/systemd/system/kubelet.service"
+	)
+
+	testCases := []struct {
+		name                   string
+		annotations            map[string]string
+		expectedRuntimeClasses []string
+		unexpectedHandlers     []string
+	}{
+		{
+			name:                   "runtime handlers of OSM",
+			expectedRuntimeClasses: []string{"wasmtime"},
+			unexpectedHandlers:     []string{"runsc", "kata"},
+		},
+		{
+			name:                   "runtime handlers of the machine deployment",
+			annotations:            map[string]string{resources.MachineDeploymentRuntimeHandlersAnnotation: "runsc,kata"},
+			expectedRuntimeClasses: []string{"runsc", "kata"},
+			unexpectedHandlers:     []string{"wasmtime"},
+		},
+		{
+			name:               "runtime handlers disabled by the machine deployment",
+			annotations:        map[string]string{resources.MachineDeploymentRuntimeHandlersAnnotation: ""},
+			unexpectedHandlers: []string{"wasmtime", "runsc", "kata"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			osp := &osmv1alpha1.OperatingSystemProfile{}
+			if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+				t.Fatalf("failed loading osp from testdata: %v", err)
+			}
+
+			md := generateMachineDeployment(
+				t,
+				"ubuntu-aws",
+				"kube-system",
+				ospUbuntu,
+				defaultKubeletVersion,
+				providerconfig.OperatingSystemUbuntu,
+				"aws",
+				runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+				testCase.annotations,
+				mcnet.IPFamilyIPv4,
+			)
+
+			reconciler, fakeClient := newTestReconciler(t, md, osp)
+			handlers, err := containerruntime.ParseRuntimeHandlers("wasmtime")
+			if err != nil {
+				t.Fatalf("failed to parse runtime handlers: %v", err)
+			}
+			reconciler.containerRuntimeConfig.RuntimeHandlers = handlers
+
+			if err := reconciler.reconcile(ctx, md); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			osc := &osmv1alpha1.OperatingSystemConfig{}
+			oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+				t.Fatalf("failed to get osc: %v", err)
+			}
+
+			var config, kubelet string
+			for _, file := range osc.Spec.ProvisioningConfig.Files {
+				switch file.Path {
+				case configPath:
+					config = file.Content.Inline.Data
+				case kubeletPath:
+					kubelet = file.Content.Inline.Data
+				}
+			}
+
+			for _, handler := range testCase.expectedRuntimeClasses {
+				if !strings.Contains(config, fmt.Sprintf("containerd.runtimes.%s]", handler)) {
+					t.Errorf("expected runtime handler %q in %s, got:\n%s", handler, configPath, config)
+				}
+
+				runtimeClass := &nodev1.RuntimeClass{}
+				if err := fakeClient.Get(ctx, types.NamespacedName{Name: handler}, runtimeClass); err != nil {
+					t.Fatalf("failed to get RuntimeClass %q: %v", handler, err)
+				}
+				if runtimeClass.Handler != handler {
+					t.Errorf("expected RuntimeClass %q to use handler %q, got %q", handler, handler, runtimeClass.Handler)
+				}
+
+				// The RuntimeClass schedules pods onto the nodes that the kubelet labels with the handler.
+				label := resources.RuntimeHandlerLabelPrefix + handler
+				if runtimeClass.Scheduling == nil || runtimeClass.Scheduling.NodeSelector[label] != "true" {
+					t.Errorf("expected RuntimeClass %q to select nodes by label %q, got %+v", handler, label, runtimeClass.Scheduling)
+				}
+				if !strings.Contains(kubelet, label+"=true") {
+					t.Errorf("expected the kubelet to register the node with label %q, got:\n%s", label, kubelet)
+				}
+			}
+
+			for _, handler := range testCase.unexpectedHandlers {
+				if strings.Contains(config, fmt.Sprintf("containerd.runtimes.%s]", handler)) {
+					t.Errorf("expected no runtime handler %q in %s, got:\n%s", handler, configPath, config)
+				}
+
+				err := fakeClient.Get(ctx, types.NamespacedName{Name: handler}, &nodev1.RuntimeClass{})
+				if !kerrors.IsNotFound(err) {
+					t.Errorf("expected no RuntimeClass %q, got: %v", handler, err)
+				}
+			}
+			if len(testCase.expectedRuntimeClasses) == 0 && strings.Contains(kubelet, "--node-labels") {
+				t.Errorf("expected the kubelet to register the node without labels, got:\n%s", kubelet)
+			}
+
+			// The machines of the machine deployment aren't changed.
+			updated := &v1alpha1.MachineDeployment{}
+			if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(md), updated); err != nil {
+				t.Fatalf("failed to get machine deployment: %v", err)
+			}
+			if !reflect.DeepEqual(updated.Spec.Template, md.Spec.Template) {
+				t.Errorf("expected the machine template to be left as it is, got %+v", updated.Spec.Template)
+			}
+		})
+	}
+}
+
+func TestRuntimeClassGarbageCollection(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	generate := func(name, handlers string) *v1alpha1.MachineDeployment {
+		return generateMachineDeployment(
+			t,
+			name,
+			"kube-system",
+			ospUbuntu,
+			defaultKubeletVersion,
+			providerconfig.OperatingSystemUbuntu,
+			"aws",
+			runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+			map[string]string{resources.MachineDeploymentRuntimeHandlersAnnotation: handlers},
+			mcnet.IPFamilyIPv4,
+		)
+	}
+	sandboxed := generate("sandboxed", "runsc,kata,wasmtime")
+	gvisor := generate("gvisor", "runsc")
+	gvisor.Finalizers = []string{MachineDeploymentCleanupFinalizer}
+
+	// The RuntimeClass existed before, it's neither changed nor deleted by OSM.
+	wasmtime := &nodev1.RuntimeClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "wasmtime"},
+		Handler:    "wasmtime",
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, sandboxed, gvisor, wasmtime, osp)
+
+	for _, md := range []*v1alpha1.MachineDeployment{sandboxed, gvisor} {
+		if err := reconciler.reconcileRuntimeClasses(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile runtime classes of %s: %v", md.Name, err)
+		}
+	}
+
+	verifyRuntimeClasses := func(expected ...string) {
+		t.Helper()
+
+		runtimeClasses := &nodev1.RuntimeClassList{}
+		if err := fakeClient.List(ctx, runtimeClasses); err != nil {
+			t.Fatalf("failed to list RuntimeClasses: %v", err)
+		}
+
+		var names []string
+		for _, runtimeClass := range runtimeClasses.Items {
+			names = append(names, runtimeClass.Name)
+			if runtimeClass.Name == wasmtime.Name && runtimeClass.Scheduling != nil {
+				t.Errorf("expected the existing RuntimeClass %q to be left as it is, got scheduling %+v", runtimeClass.Name, runtimeClass.Scheduling)
+			}
+		}
+		slices.Sort(names)
+		slices.Sort(expected)
+		if !slices.Equal(names, expected) {
+			t.Errorf("expected RuntimeClasses %v, got %v", expected, names)
+		}
+	}
+
+	verifyRuntimeClasses("kata", "runsc", "wasmtime")
+
+	// Removing the handlers from a machine deployment deletes the RuntimeClasses that no other machine deployment uses.
+	sandboxed.Annotations[resources.MachineDeploymentRuntimeHandlersAnnotation] = ""
+	if err := fakeClient.Update(ctx, sandboxed); err != nil {
+		t.Fatalf("failed to update machine deployment: %v", err)
+	}
+	if err := reconciler.reconcileRuntimeClasses(ctx, sandboxed); err != nil {
+		t.Fatalf("failed to reconcile runtime classes: %v", err)
+	}
+	verifyRuntimeClasses("runsc", "wasmtime")
+
+	// Deleting the last machine deployment that uses a handler deletes its RuntimeClass.
+	if err := fakeClient.Delete(ctx, gvisor); err != nil {
+		t.Fatalf("failed to delete machine deployment: %v", err)
+	}
+	if err := fakeClient.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(gvisor), gvisor); err != nil {
+		t.Fatalf("failed to get machine deployment: %v", err)
+	}
+	if _, err := reconciler.handleMachineDeploymentCleanup(ctx, gvisor); err != nil {
+		t.Fatalf("failed to clean up machine deployment: %v", err)
+	}
+	verifyRuntimeClasses("wasmtime")
+}
+
+func TestRegistryOverrides(t *testing.T) {
+	const (
+		configPath      = "/etc/containerd/config.toml"
+		dockerHostsPath = "/etc/containerd/certs.d/docker.io/hosts.toml"
+		quayHostsPath   = "/etc/containerd/certs.d/quay.io/hosts.toml"
+		edgeHostsPath   = "/etc/containerd/certs.d/registry.edge.example.com/hosts.toml"
+	)
+
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedContents map[string][]string
+		absentContents   map[string][]string
+	}{
+		{
+			name: "registry settings of OSM",
+			expectedContents: map[string][]string{
+				dockerHostsPath: {`[host."https://mirror.cloud.example.com"]`},
+				configPath:      {`registry.configs."cloud.example.com".auth]`},
+			},
+			absentContents: map[string][]string{
+				configPath: {"registry.edge.example.com"},
+			},
+		},
+		{
+			name: "registry settings of the machine deployment",
+			annotations: map[string]string{
+				resources.MachineDeploymentRegistryMirrorsAnnotation:           "docker.io=mirror.edge.example.com",
+				resources.MachineDeploymentInsecureRegistriesAnnotation:        "registry.edge.example.com",
+				resources.MachineDeploymentRegistryConfigMapAnnotation:         "edge-registries",
+				resources.MachineDeploymentRegistryCredentialsSecretAnnotation: "edge-registry-credentials",
+			},
+			expectedContents: map[string][]string{
+				dockerHostsPath: {`[host."https://mirror.edge.example.com"]`},
+				quayHostsPath:   {`[host."https://quay.mirror.edge.example.com"]`},
+				edgeHostsPath:   {"skip_verify = true"},
+				configPath: {
+					`registry.configs."cloud.example.com".auth]`,
+					`registry.configs."registry.edge.example.com".auth]`,
+				},
+			},
+			absentContents: map[string][]string{
+				dockerHostsPath: {"mirror.cloud.example.com", "docker.mirror.edge.example.com"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			osp := &osmv1alpha1.OperatingSystemProfile{}
+			if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+				t.Fatalf("failed loading osp from testdata: %v", err)
+			}
+
+			md := generateMachineDeployment(
+				t,
+				"ubuntu-aws",
+				"kube-system",
+				ospUbuntu,
+				defaultKubeletVersion,
+				providerconfig.OperatingSystemUbuntu,
+				"aws",
+				runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+				testCase.annotations,
+				mcnet.IPFamilyIPv4,
+			)
+
+			objects := []ctrlruntimeclient.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "edge-registries",
+						Namespace: "kube-system",
+					},
+					Data: map[string]string{
+						"registryMirrors": "docker.io=docker.mirror.edge.example.com,quay.io=quay.mirror.edge.example.com",
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "edge-registry-credentials",
+						Namespace: "kube-system",
+					},
+					Data: map[string][]byte{
+						"registry.edge.example.com": []byte(`{"username": "edge", "password": "secret"}`),
+					},
+				},
+				osp,
+			}
+
+			reconciler, fakeClient := newTestReconciler(t, md, objects...)
+			containerRuntimeConfig, err := containerruntime.BuildConfig(containerruntime.Opts{
+				ContainerRuntime: "containerd",
+				RegistryMirrors:  "mirror.cloud.example.com",
+			})
+			if err != nil {
+				t.Fatalf("failed to generate container runtime config: %v", err)
+			}
+			containerRuntimeConfig.RegistryCredentials = map[string]containerruntime.AuthConfig{
+				"cloud.example.com": {Username: "cloud", Password: "secret"},
+			}
+			reconciler.containerRuntimeConfig = containerRuntimeConfig
+
+			if err := reconciler.reconcile(ctx, md); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			osc := &osmv1alpha1.OperatingSystemConfig{}
+			oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+				t.Fatalf("failed to get osc: %v", err)
+			}
+
+			files := map[string]string{}
+			for _, file := range osc.Spec.ProvisioningConfig.Files {
+				files[file.Path] = file.Content.Inline.Data
+			}
+
+			for path, contents := range testCase.expectedContents {
+				for _, content := range contents {
+					if !strings.Contains(files[path], content) {
+						t.Errorf("expected %q in %s, got:\n%s", content, path, files[path])
+					}
+				}
+			}
+			for path, contents := range testCase.absentContents {
+				for _, content := range contents {
+					if strings.Contains(files[path], content) {
+						t.Errorf("expected no %q in %s, got:\n%s", content, path, files[path])
+					}
+				}
+			}
+
+			// The registry settings of the machine deployment must not leak into the ones of OSM.
+			if mirrors := reconciler.containerRuntimeConfig.RegistryMirrors["docker.io"]; len(mirrors) != 1 || mirrors[0] != "https://mirror.cloud.example.com" {
+				t.Errorf("expected the registry mirrors of OSM to be unchanged, got %v", reconciler.containerRuntimeConfig.RegistryMirrors)
+			}
+			if len(reconciler.containerRuntimeConfig.RegistryCredentials) != 1 {
+				t.Errorf("expected the registry credentials of OSM to be unchanged, got %v", reconciler.containerRuntimeConfig.RegistryCredentials)
+			}
+
+			_, hasHash := osc.Annotations[OperatingSystemConfigRegistryOverridesHash]
+			if hasHash != (len(testCase.annotations) > 0) {
+				t.Errorf("expected registry overrides hash annotation to be set: %v, got annotations %v", len(testCase.annotations) > 0, osc.Annotations)
+			}
+		})
+	}
+}
+
+func TestRegistryOverridesRotation(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		map[string]string{
+			resources.MachineDeploymentRegistryConfigMapAnnotation:         "edge-registries",
+			resources.MachineDeploymentRegistryCredentialsSecretAnnotation: "edge-registry-credentials",
+		},
+		mcnet.IPFamilyIPv4,
+	)
+
+	registries := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge-registries",
+			Namespace: "kube-system",
+		},
+		Data: map[string]string{
+			"insecureRegistries": "registry.edge.example.com",
+		},
+	}
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edge-registry-credentials",
+			Namespace: "kube-system",
+		},
+		Data: map[string][]byte{
+			"registry.edge.example.com": []byte(`{"username": "edge", "password": "first-password"}`),
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, registries, credentials, osp)
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+
+	// reconcileAndVerify reconciles the machine deployment and returns the registry overrides hash after verifying that
+	// one of the provisioning files contains the content.
+	reconcileAndVerify := func(expectedContent string) string {
+		t.Helper()
+
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+
+		if !slices.ContainsFunc(osc.Spec.ProvisioningConfig.Files, func(file osmv1alpha1.File) bool {
+			return file.Content.Inline != nil && strings.Contains(file.Content.Inline.Data, expectedContent)
+		}) {
+			t.Fatalf("expected %q in the provisioning files", expectedContent)
+		}
+
+		hash := osc.Annotations[OperatingSystemConfigRegistryOverridesHash]
+		if hash == "" {
+			t.Fatal("expected registry overrides hash annotation on osc")
+		}
+		return hash
+	}
+
+	hash := reconcileAndVerify("first-password")
+
+	// Changing the credentials secret must rotate the OSC, although the machine deployment is unchanged.
+	credentials.Data["registry.edge.example.com"] = []byte(`{"username": "edge", "password": "second-password"}`)
+	if err := fakeClient.Update(ctx, credentials); err != nil {
+		t.Fatalf("failed to update registry credentials: %v", err)
+	}
+	if newHash := reconcileAndVerify("second-password"); newHash == hash {
+		t.Errorf("expected the registry overrides hash to change on a credentials change, got %q", newHash)
+	} else {
+		hash = newHash
+	}
+
+	// The same applies to the config map.
+	registries.Data["insecureRegistries"] = "registry.edge.example.com,registry.lab.example.com"
+	if err := fakeClient.Update(ctx, registries); err != nil {
+		t.Fatalf("failed to update registry config map: %v", err)
+	}
+	if newHash := reconcileAndVerify("registry.lab.example.com"); newHash == hash {
+		t.Errorf("expected the registry overrides hash to change on a config map change, got %q", newHash)
+	}
+
+	// Both are mapped back to the machine deployment that names them.
+	requests := reconciler.enqueueMachineDeploymentsReferencing(ctx, registries,
+		resources.MachineDeploymentParametersConfigMapAnnotation,
+		resources.MachineDeploymentRegistryConfigMapAnnotation,
+	)
+	if len(requests) != 1 || requests[0].NamespacedName != ctrlruntimeclient.ObjectKeyFromObject(md) {
+		t.Errorf("expected the machine deployment to be enqueued for the registry config map, got %v", requests)
+	}
+	requests = reconciler.enqueueMachineDeploymentsReferencing(ctx, credentials, resources.MachineDeploymentRegistryCredentialsSecretAnnotation)
+	if len(requests) != 1 || requests[0].NamespacedName != ctrlruntimeclient.ObjectKeyFromObject(md) {
+		t.Errorf("expected the machine deployment to be enqueued for the registry credentials secret, got %v", requests)
+	}
+	otherSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other-credentials", Namespace: "kube-system"}}
+	if requests := reconciler.enqueueMachineDeploymentsReferencing(ctx, otherSecret, resources.MachineDeploymentRegistryCredentialsSecretAnnotation); len(requests) != 0 {
+		t.Errorf("expected no machine deployment to be enqueued for a secret that it doesn't name, got %v", requests)
+	}
+}
+
+func TestRegistryCredentialsRotation(t *testing.T) {
+	const configPath = "/etc/containerd/config.toml"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	credentialsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry-credentials",
+			Namespace: "kube-system",
+		},
+		Data: map[string][]byte{
+			"registry.example.com": []byte(`{"username": "user", "password": "first-password"}`),
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, credentialsSecret, osp)
+	reconciler.nodeRegistryCredentialsSecret = "kube-system/registry-credentials"
+
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	reconcileOSC := func() *osmv1alpha1.OperatingSystemConfig {
+		t.Helper()
+
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+		return osc
+	}
+
+	containerdConfig := func(osc *osmv1alpha1.OperatingSystemConfig) string {
+		for _, file := range osc.Spec.ProvisioningConfig.Files {
+			if file.Path == configPath {
+				return file.Content.Inline.Data
+			}
+		}
+		return ""
+	}
+
+	osc := reconcileOSC()
+	hash := osc.Annotations[OperatingSystemConfigRegistryCredentialsHash]
+	if hash == "" {
+		t.Fatalf("expected OSC to have a registry credentials hash, got annotations %v", osc.Annotations)
+	}
+	if config := containerdConfig(osc); !strings.Contains(config, "first-password") {
+		t.Fatalf("expected the registry credentials in %s, got:\n%s", configPath, config)
+	}
+
+	// Changing the credentials must rotate the OSC, although the machine deployment is unchanged.
+	credentialsSecret.Data["registry.example.com"] = []byte(`{"username": "user", "password": "second-password"}`)
+	if err := fakeClient.Update(ctx, credentialsSecret); err != nil {
+		t.Fatalf("failed to update registry credentials: %v", err)
+	}
+
+	osc = reconcileOSC()
+	if newHash := osc.Annotations[OperatingSystemConfigRegistryCredentialsHash]; newHash == hash {
+		t.Errorf("expected the registry credentials hash to change, got %q", newHash)
+	}
+	if config := containerdConfig(osc); !strings.Contains(config, "second-password") || strings.Contains(config, "first-password") {
+		t.Errorf("expected the rotated registry credentials in %s, got:\n%s", configPath, config)
+	}
+
+	requests := reconciler.enqueueMachineDeployments(ctx, credentialsSecret)
+	if len(requests) != 1 || requests[0].NamespacedName != ctrlruntimeclient.ObjectKeyFromObject(md) {
+		t.Errorf("expected the machine deployment to be enqueued, got %v", requests)
+	}
+}
+
+func TestRegistryCertificatesRotation(t *testing.T) {
+	const caPath = "/etc/containerd/certs.d/registry.example.com/ca.crt"
+
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	certificatesSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry-certificates",
+			Namespace: "kube-system",
+		},
+		Data: map[string][]byte{
+			"registry.example.com.ca.crt": []byte("first-ca"),
+		},
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, certificatesSecret, osp)
+	reconciler.nodeRegistryCertificatesSecret = "kube-system/registry-certificates"
+
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	reconcileOSC := func() *osmv1alpha1.OperatingSystemConfig {
+		t.Helper()
+
+		if err := reconciler.reconcile(ctx, md); err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+			t.Fatalf("failed to get osc: %v", err)
+		}
+		return osc
+	}
+
+	caFile := func(osc *osmv1alpha1.OperatingSystemConfig) string {
+		for _, file := range osc.Spec.ProvisioningConfig.Files {
+			if file.Path == caPath {
+				return file.Content.Inline.Data
+			}
+		}
+		return ""
+	}
+
+	osc := reconcileOSC()
+	hash := osc.Annotations[OperatingSystemConfigRegistryCertificatesHash]
+	if hash == "" {
+		t.Fatalf("expected OSC to have a registry certificates hash, got annotations %v", osc.Annotations)
+	}
+	if ca := caFile(osc); !strings.Contains(ca, "first-ca") {
+		t.Fatalf("expected the registry CA in %s, got %q", caPath, ca)
+	}
+
+	// Changing the certificates must rotate the OSC, although the machine deployment is unchanged.
+	certificatesSecret.Data["registry.example.com.ca.crt"] = []byte("second-ca")
+	if err := fakeClient.Update(ctx, certificatesSecret); err != nil {
+		t.Fatalf("failed to update registry certificates: %v", err)
+	}
+
+	osc = reconcileOSC()
+	if newHash := osc.Annotations[OperatingSystemConfigRegistryCertificatesHash]; newHash == hash {
+		t.Errorf("expected the registry certificates hash to change, got %q", newHash)
+	}
+	if ca := caFile(osc); !strings.Contains(ca, "second-ca") {
+		t.Errorf("expected the rotated registry CA in %s, got %q", caPath, ca)
+	}
+
+	predicate := filterSecretPredicate("kube-system/registry-credentials", reconciler.nodeRegistryCertificatesSecret)
+	if !predicate.Update(event.TypedUpdateEvent[*corev1.Secret]{ObjectOld: certificatesSecret, ObjectNew: certificatesSecret}) {
+		t.Error("expected the registry certificates secret to be watched")
+	}
+}
+
+func TestImageCredentialProviders(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	reconciler, fakeClient := newTestReconciler(t, md, osp)
+	reconciler.credentialProviderConfig = credentialprovider.Config{CloudProvider: true}
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		files[file.Path] = file.Content.Inline.Data
+	}
+
+	if config := files[credentialprovider.ConfigFileName]; !strings.Contains(config, "name: ecr-credential-provider") {
+		t.Errorf("expected ecr-credential-provider in %s, got:\n%s", credentialprovider.ConfigFileName, config)
+	}
+
+	kubeletFlag := "--image-credential-provider-config=" + credentialprovider.ConfigFileName
+	if unit := files["/etc/systemd/system/kubelet.service"]; !strings.Contains(unit, kubeletFlag) {
+		t.Errorf("expected the kubelet to be started with %s, got:\n%s", kubeletFlag, unit)
+	}
+
+	download := fmt.Sprintf(`curl -Lfo "%s/ecr-credential-provider"`, credentialprovider.BinDir)
+	if setup := files["/opt/bin/setup"]; !strings.Contains(setup, download) {
+		t.Errorf("expected the setup script to download ecr-credential-provider, got:\n%s", setup)
+	}
+}
+
+func TestRegistryCredentialsCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	osp := &osmv1alpha1.OperatingSystemProfile{}
+	if err := loadFile(osp, defaultOSPPathPrefix+fmt.Sprintf("%s.yaml", ospUbuntu)); err != nil {
+		t.Fatalf("failed loading osp from testdata: %v", err)
+	}
+
+	md := generateMachineDeployment(
+		t,
+		"ubuntu-aws",
+		"kube-system",
+		ospUbuntu,
+		defaultKubeletVersion,
+		providerconfig.OperatingSystemUbuntu,
+		"aws",
+		runtime.RawExtension{Raw: []byte(`{"availabilityZone": "eu-central-1b", "vpcId": "e-123f", "subnetID": "test-subnet"}`)},
+		nil,
+		mcnet.IPFamilyIPv4,
+	)
+
+	objects := []ctrlruntimeclient.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "registry-credentials",
+				Namespace: "kube-system",
+			},
+			Data: map[string][]byte{
+				"registry.example.com": []byte(`{"username": "user", "password": "top-secret-password"}`),
+				"sandbox.example.com":  []byte(`{"username": "user", "password": "sandbox-password"}`),
+			},
+		},
+		osp,
+	}
+
+	reconciler, fakeClient := newTestReconciler(t, md, objects...)
+	reconciler.nodeRegistryCredentialsSecret = "kube-system/registry-credentials"
+	reconciler.credentialProviderConfig = credentialprovider.Config{RegistryCredentials: true}
+	reconciler.containerRuntimeConfig.SandboxImage = "sandbox.example.com/pause:3.10"
+
+	if err := reconciler.reconcile(ctx, md); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	osc := &osmv1alpha1.OperatingSystemConfig{}
+	oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "kube-system", Name: oscName}, osc); err != nil {
+		t.Fatalf("failed to get osc: %v", err)
+	}
+
+	encodedOSC, err := json.Marshal(osc)
+	if err != nil {
+		t.Fatalf("failed to encode osc: %v", err)
+	}
+	if strings.Contains(string(encodedOSC), "top-secret-password") {
+		t.Errorf("expected the registry credentials to be kept out of the OSC")
+	}
+
+	credentialsSecret := &corev1.Secret{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: "cloud-init-settings", Name: "kube-system-ubuntu-aws-registry-credentials"}, credentialsSecret); err != nil {
+		t.Fatalf("failed to get registry credentials secret: %v", err)
+	}
+	if credentials := string(credentialsSecret.Data[credentialprovider.RegistryCredentialsSecretKey]); !strings.Contains(credentials, "top-secret-password") {
+		t.Errorf("expected the registry credentials in the secret, got %s", credentials)
+	}
+
+	files := map[string]osmv1alpha1.File{}
+	for _, file := range osc.Spec.ProvisioningConfig.Files {
+		files[file.Path] = file
+	}
+
+	if config := files[credentialprovider.ConfigFileName].Content.Inline; config == nil || !strings.Contains(config.Data, "registry.example.com") {
+		t.Errorf("expected a credential provider for registry.example.com in %s", credentialprovider.ConfigFileName)
+	}
+	if provider, ok := files[credentialprovider.BinDir+"/osm-registry-credentials"]; !ok || provider.Permissions != 755 {
+		t.Errorf("expected the executable osm-registry-credentials credential provider, got %+v", provider)
+	}
+	if setup := files["/opt/bin/setup"].Content.Inline.Data; !strings.Contains(setup, "secrets/kube-system-ubuntu-aws-registry-credentials") {
+		t.Errorf("expected the setup script to fetch the registry credentials, got:\n%s", setup)
+	}
+
+	// The container runtime pulls the sandbox image without asking the kubelet, so it keeps the credentials of its
+	// registry.
+	if config := files["/etc/containerd/config.toml"].Content.Inline; config == nil || !strings.Contains(config.Data, `registry.configs."sandbox.example.com".auth]`) {
+		t.Errorf("expected the credentials of the sandbox image registry in the containerd config")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/operating-system-manager/pkg/controllers/osc/resources"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateOperatingSystemProfileStatuses records the machine deployment in the status of the OSP it references and removes it
+// from the status of every other OSP, e.g. after the machine deployment switched to another OSP. If osp is nil, the machine
+// deployment is removed from all OSPs.
+func (r *Reconciler) updateOperatingSystemProfileStatuses(ctx context.Context, md *clusterv1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile, renderErr error) error {
+	ospList := &osmv1alpha1.OperatingSystemProfileList{}
+	if err := r.List(ctx, ospList); err != nil {
+		return fmt.Errorf("failed to list OperatingSystemProfiles: %w", err)
+	}
+
+	var entry *osmv1alpha1.OperatingSystemProfileMachineDeployment
+	if osp != nil {
+		entry = &osmv1alpha1.OperatingSystemProfileMachineDeployment{
+			Namespace: md.Namespace,
+			Name:      md.Name,
+		}
+
+		oscName := fmt.Sprintf(resources.OperatingSystemConfigNamePattern, md.Name, md.Namespace)
+		osc := &osmv1alpha1.OperatingSystemConfig{}
+		if err := r.Get(ctx, types.NamespacedName{Name: oscName, Namespace: r.namespace}, osc); err == nil {
+			entry.RenderedVersion = osc.Annotations[OperatingSystemConfigVersionAnnotation]
+		} else if !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get OperatingSystemConfig %q from namespace %q: %w", oscName, r.namespace, err)
+		}
+
+		if renderErr != nil {
+			entry.RenderError = renderErr.Error()
+		}
+	}
+
+	for i := range ospList.Items {
+		item := &ospList.Items[i]
+
+		if osp != nil && item.Namespace == osp.Namespace && item.Name == osp.Name {
+			if err := r.setOperatingSystemProfileMachineDeployment(ctx, item, md, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.setOperatingSystemProfileMachineDeployment(ctx, item, md, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setOperatingSystemProfileMachineDeployment replaces the status entry of the machine deployment in the OSP. A nil entry
+// removes the machine deployment from the status.
+func (r *Reconciler) setOperatingSystemProfileMachineDeployment(ctx context.Context, osp *osmv1alpha1.OperatingSystemProfile, md *clusterv1alpha1.MachineDeployment, entry *osmv1alpha1.OperatingSystemProfileMachineDeployment) error {
+	// Avoid fetching the OSP again if its status is already up-to-date.
+	if apiequality.Semantic.DeepEqual(osp.Status.MachineDeployments, machineDeploymentStatuses(osp.Status.MachineDeployments, md, entry)) {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &osmv1alpha1.OperatingSystemProfile{}
+		if err := r.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(osp), current); err != nil {
+			return err
+		}
+
+		machineDeployments := machineDeploymentStatuses(current.Status.MachineDeployments, md, entry)
+		if apiequality.Semantic.DeepEqual(current.Status.MachineDeployments, machineDeployments) {
+			return nil
+		}
+
+		current.Status.MachineDeployments = machineDeployments
+		if err := r.Status().Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to update status of OperatingSystemProfile %s/%s: %w", current.Namespace, current.Name, err)
+		}
+		return nil
+	})
+}
+
+// machineDeploymentStatuses returns a sorted copy of the given statuses with the entry of the machine deployment replaced.
+func machineDeploymentStatuses(statuses []osmv1alpha1.OperatingSystemProfileMachineDeployment, md *clusterv1alpha1.MachineDeployment, entry *osmv1alpha1.OperatingSystemProfileMachineDeployment) []osmv1alpha1.OperatingSystemProfileMachineDeployment {
+	var result []osmv1alpha1.OperatingSystemProfileMachineDeployment
+	for _, status := range statuses {
+		if status.Namespace == md.Namespace && status.Name == md.Name {
+			continue
+		}
+		result = append(result, status)
+	}
+
+	if entry != nil {
+		result = append(result, *entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	fileContentSecretKind    = "Secret"
+	fileContentConfigMapKind = "ConfigMap"
+)
+
+// ReferencedFileContents contains the resolved data of OSP files whose content is sourced from secrets or config maps.
+// The zero value is valid and resolves no references.
+type ReferencedFileContents struct {
+	// namespace is used for references that don't specify a namespace.
+	namespace string
+	data      map[string]string
+}
+
+// FetchReferencedFileContents resolves all secret and config map references used by the files of an OSP.
+func FetchReferencedFileContents(ctx context.Context, client ctrlruntimeclient.Client, osp *osmv1alpha1.OperatingSystemProfile) (ReferencedFileContents, error) {
+	contents := ReferencedFileContents{
+		namespace: osp.Namespace,
+		data:      map[string]string{},
+	}
+
+	for _, file := range ospFiles(osp) {
+		kind, ref := fileContentReference(file.Content)
+		if ref == nil {
+			continue
+		}
+
+		// OSM is only granted access to secrets and config maps in its own namespace, which is the namespace of the OSPs.
+		if ref.Namespace != "" && ref.Namespace != osp.Namespace {
+			return ReferencedFileContents{}, fmt.Errorf("content of file %q references %s %s/%s outside of the namespace %s of the OperatingSystemProfile", file.Path, kind, ref.Namespace, ref.Name, osp.Namespace)
+		}
+
+		key := contents.key(kind, ref)
+		if _, ok := contents.data[key]; ok {
+			continue
+		}
+
+		data, err := fetchReferencedData(ctx, client, kind, contents.namespaceFor(ref), ref)
+		if err != nil {
+			return ReferencedFileContents{}, fmt.Errorf("failed to resolve content of file %q: %w", file.Path, err)
+		}
+		contents.data[key] = data
+	}
+
+	return contents, nil
+}
+
+// Hash returns a hash over all resolved contents, it's empty if no references were resolved.
+func (c ReferencedFileContents) Hash() (string, error) {
+	if len(c.data) == 0 {
+		return "", nil
+	}
+
+	hash, err := HashJSON(c.data)
+	if err != nil {
+		return "", fmt.Errorf("failed to json encode referenced file contents: %w", err)
+	}
+
+	return hash, nil
+}
+
+// lookup returns the resolved data for the reference in the given file content.
+func (c ReferencedFileContents) lookup(content osmv1alpha1.FileContent) (string, error) {
+	kind, ref := fileContentReference(content)
+	if ref == nil {
+		return "", fmt.Errorf("file content has neither inline data nor a reference")
+	}
+
+	data, ok := c.data[c.key(kind, ref)]
+	if !ok {
+		return "", fmt.Errorf("%s %s/%s has not been resolved", kind, c.namespaceFor(ref), ref.Name)
+	}
+
+	return data, nil
+}
+
+func (c ReferencedFileContents) namespaceFor(ref *osmv1alpha1.FileContentReference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return c.namespace
+}
+
+func (c ReferencedFileContents) key(kind string, ref *osmv1alpha1.FileContentReference) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kind, c.namespaceFor(ref), ref.Name, ref.Key)
+}
+
+func fileContentReference(content osmv1alpha1.FileContent) (string, *osmv1alpha1.FileContentReference) {
+	switch {
+	case content.SecretRef != nil:
+		return fileContentSecretKind, content.SecretRef
+	case content.ConfigMapRef != nil:
+		return fileContentConfigMapKind, content.ConfigMapRef
+	default:
+		return "", nil
+	}
+}
+
+func fetchReferencedData(ctx context.Context, client ctrlruntimeclient.Client, kind, namespace string, ref *osmv1alpha1.FileContentReference) (string, error) {
+	name := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+
+	switch kind {
+	case fileContentSecretKind:
+		secret := &corev1.Secret{}
+		if err := client.Get(ctx, name, secret); err != nil {
+			return "", fmt.Errorf("failed to get secret %s: %w", name, err)
+		}
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("secret %s has no key %q", name, ref.Key)
+		}
+		return string(data), nil
+
+	case fileContentConfigMapKind:
+		configMap := &corev1.ConfigMap{}
+		if err := client.Get(ctx, name, configMap); err != nil {
+			return "", fmt.Errorf("failed to get config map %s: %w", name, err)
+		}
+		if data, ok := configMap.Data[ref.Key]; ok {
+			return data, nil
+		}
+		if data, ok := configMap.BinaryData[ref.Key]; ok {
+			return string(data), nil
+		}
+		return "", fmt.Errorf("config map %s has no key %q", name, ref.Key)
+	}
+
+	return "", fmt.Errorf("unknown reference kind %q", kind)
+}
+
+// ReferencesFileContent returns true if the content of one of the files of the OSP is sourced from the secret or config
+// map.
+func ReferencesFileContent(osp *osmv1alpha1.OperatingSystemProfile, obj ctrlruntimeclient.Object) bool {
+	var kind string
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = fileContentSecretKind
+	case *corev1.ConfigMap:
+		kind = fileContentConfigMapKind
+	default:
+		return false
+	}
+
+	// References are resolved in the namespace of the OSP.
+	if obj.GetNamespace() != osp.Namespace {
+		return false
+	}
+
+	for _, file := range ospFiles(osp) {
+		refKind, ref := fileContentReference(file.Content)
+		if ref == nil || refKind != kind || ref.Name != obj.GetName() {
+			continue
+		}
+		if ref.Namespace == "" || ref.Namespace == obj.GetNamespace() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FileContentReferences returns the secrets and config maps in the namespace of the OSP that its files source their
+// content from, in the form returned by FileContentReferenceOf.
+func FileContentReferences(osp *osmv1alpha1.OperatingSystemProfile) []string {
+	return fileContentReferences(osp.Namespace, ospFiles(osp))
+}
+
+// FragmentFileContentReferences returns the secrets and config maps in the namespace of the fragment that its files
+// source their content from, in the form returned by FileContentReferenceOf.
+func FragmentFileContentReferences(fragment *osmv1alpha1.OperatingSystemProfileFragment) []string {
+	var files []osmv1alpha1.File
+	for _, config := range []*osmv1alpha1.OSPFragmentConfig{fragment.Spec.BootstrapConfig, fragment.Spec.ProvisioningConfig} {
+		if config != nil {
+			files = append(files, config.Files...)
+		}
+	}
+
+	return fileContentReferences(fragment.Namespace, files)
+}
+
+// FileContentReferenceOf returns the kind and name of the secret or config map, it's empty for other objects.
+func FileContentReferenceOf(obj ctrlruntimeclient.Object) string {
+	switch obj.(type) {
+	case *corev1.Secret:
+		return fileContentSecretKind + "/" + obj.GetName()
+	case *corev1.ConfigMap:
+		return fileContentConfigMapKind + "/" + obj.GetName()
+	default:
+		return ""
+	}
+}
+
+func fileContentReferences(namespace string, files []osmv1alpha1.File) []string {
+	var references []string
+	for _, file := range files {
+		kind, ref := fileContentReference(file.Content)
+		if ref == nil || (ref.Namespace != "" && ref.Namespace != namespace) {
+			continue
+		}
+		if reference := kind + "/" + ref.Name; !slices.Contains(references, reference) {
+			references = append(references, reference)
+		}
+	}
+
+	return references
+}
+
+// ospFiles returns all files of an OSP, including the ones of the supported container runtimes.
+func ospFiles(osp *osmv1alpha1.OperatingSystemProfile) []osmv1alpha1.File {
+	var files []osmv1alpha1.File
+	for _, config := range []osmv1alpha1.OSPConfig{osp.Spec.BootstrapConfig, osp.Spec.ProvisioningConfig} {
+		files = append(files, config.Files...)
+		for _, cr := range config.SupportedContainerRuntimes {
+			files = append(files, cr.Files...)
+		}
+	}
+	return files
+}
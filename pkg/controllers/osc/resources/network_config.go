@@ -0,0 +1,211 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+
+	mcnet "k8c.io/machine-controller/sdk/net"
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+// staticNetworkInterface is the interface that static addressing is applied to. Predicting NIC names is not possible
+// across providers, so the configureStaticNetworkScript of the OSPs sets the variable to the first ethernet NIC of the
+// machine before writing the configuration.
+const staticNetworkInterface = "${STATIC_NETWORK_INTERFACE}"
+
+// networkConfigFields are the fields of the NetworkConfig of the provider spec. It has no routes, VLANs or bonds, so
+// those can't be rendered as static network config.
+var networkConfigFields = []string{"cidr", "gateway", "dns", "ipFamily"}
+
+// validateNetworkConfigFields rejects fields of the network config of the provider spec that the NetworkConfig doesn't
+// have, e.g. routes, VLANs or bonds. Decoding the provider spec would reject them as well, but without telling that
+// the static network config can't be rendered from them.
+func validateNetworkConfigFields(providerSpec []byte) error {
+	var spec struct {
+		Network map[string]json.RawMessage `json:"network"`
+	}
+	if err := json.Unmarshal(providerSpec, &spec); err != nil {
+		return fmt.Errorf("failed to decode network config: %w", err)
+	}
+
+	var unsupported []string
+	for field := range spec.Network {
+		if !slices.ContainsFunc(networkConfigFields, func(known string) bool { return strings.EqualFold(known, field) }) {
+			unsupported = append(unsupported, field)
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	sort.Strings(unsupported)
+
+	return fmt.Errorf("network config doesn't support %s, static IP config can only be rendered from %s", strings.Join(unsupported, ", "), strings.Join(networkConfigFields, ", "))
+}
+
+// renderStaticNetworkConfig renders the static network configuration of the machine in the native format of the
+// operating system, i.e. netplan for Ubuntu and a NetworkManager keyfile for RHEL and Rocky Linux.
+func renderStaticNetworkConfig(os providerconfig.OperatingSystem, network *providerconfig.NetworkConfig) (string, error) {
+	addressing, err := parseStaticNetworkConfig(network)
+	if err != nil {
+		return "", err
+	}
+
+	switch os {
+	case providerconfig.OperatingSystemUbuntu:
+		return addressing.netplan(), nil
+	case providerconfig.OperatingSystemRHEL, providerconfig.OperatingSystemRockyLinux:
+		return addressing.networkManagerKeyfile(), nil
+	default:
+		return "", fmt.Errorf("static IP config is not supported with: %s", os)
+	}
+}
+
+type staticNetworkConfig struct {
+	address    string
+	gateway    string
+	ipv6       bool
+	dnsServers []string
+}
+
+func parseStaticNetworkConfig(network *providerconfig.NetworkConfig) (staticNetworkConfig, error) {
+	config := staticNetworkConfig{
+		address: network.CIDR,
+		gateway: network.Gateway,
+	}
+
+	if config.address == "" {
+		return config, fmt.Errorf("static IP config requires a CIDR")
+	}
+
+	ip, _, err := net.ParseCIDR(config.address)
+	if err != nil {
+		return config, fmt.Errorf("invalid CIDR %q in static IP config: %w", config.address, err)
+	}
+	config.ipv6 = ip.To4() == nil
+
+	// Only a single address can be configured, dual-stack addressing would be dropped.
+	switch network.GetIPFamily() {
+	case mcnet.IPFamilyUnspecified:
+	case mcnet.IPFamilyIPv4, mcnet.IPFamilyIPv6:
+		if (network.GetIPFamily() == mcnet.IPFamilyIPv6) != config.ipv6 {
+			return config, fmt.Errorf("CIDR %q in static IP config doesn't belong to the IP family %s", config.address, network.GetIPFamily())
+		}
+	default:
+		return config, fmt.Errorf("static IP config doesn't support the IP family %s", network.GetIPFamily())
+	}
+
+	if config.gateway != "" {
+		gateway := net.ParseIP(config.gateway)
+		if gateway == nil {
+			return config, fmt.Errorf("invalid gateway %q in static IP config", config.gateway)
+		}
+		if (gateway.To4() == nil) != config.ipv6 {
+			return config, fmt.Errorf("gateway %q and CIDR %q in static IP config belong to different IP families", config.gateway, config.address)
+		}
+	}
+
+	for _, server := range network.DNS.Servers {
+		if net.ParseIP(server) == nil {
+			return config, fmt.Errorf("invalid DNS server %q in static IP config", server)
+		}
+		config.dnsServers = append(config.dnsServers, server)
+	}
+
+	return config, nil
+}
+
+func (c staticNetworkConfig) netplan() string {
+	var b strings.Builder
+
+	b.WriteString("network:\n")
+	b.WriteString("  version: 2\n")
+	b.WriteString("  ethernets:\n")
+	fmt.Fprintf(&b, "    %s:\n", staticNetworkInterface)
+	b.WriteString("      dhcp4: false\n")
+	b.WriteString("      dhcp6: false\n")
+	b.WriteString("      addresses:\n")
+	fmt.Fprintf(&b, "      - %q\n", c.address)
+	if c.gateway != "" {
+		b.WriteString("      routes:\n")
+		b.WriteString("      - to: default\n")
+		fmt.Fprintf(&b, "        via: %q\n", c.gateway)
+	}
+	if len(c.dnsServers) > 0 {
+		b.WriteString("      nameservers:\n")
+		b.WriteString("        addresses:\n")
+		for _, server := range c.dnsServers {
+			fmt.Fprintf(&b, "        - %q\n", server)
+		}
+	}
+
+	return b.String()
+}
+
+func (c staticNetworkConfig) networkManagerKeyfile() string {
+	var ipv4DNS, ipv6DNS []string
+	for _, server := range c.dnsServers {
+		if net.ParseIP(server).To4() != nil {
+			ipv4DNS = append(ipv4DNS, server)
+		} else {
+			ipv6DNS = append(ipv6DNS, server)
+		}
+	}
+
+	address := c.address
+	if c.gateway != "" {
+		address = fmt.Sprintf("%s,%s", c.address, c.gateway)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("[connection]\n")
+	b.WriteString("id=static\n")
+	b.WriteString("type=ethernet\n")
+	b.WriteString("autoconnect=true\n")
+	b.WriteString("autoconnect-priority=100\n")
+	fmt.Fprintf(&b, "interface-name=%s\n", staticNetworkInterface)
+
+	b.WriteString("\n[ipv4]\n")
+	if c.ipv6 {
+		b.WriteString("method=disabled\n")
+	} else {
+		b.WriteString("method=manual\n")
+		fmt.Fprintf(&b, "address1=%s\n", address)
+		if len(ipv4DNS) > 0 {
+			fmt.Fprintf(&b, "dns=%s;\n", strings.Join(ipv4DNS, ";"))
+		}
+	}
+
+	b.WriteString("\n[ipv6]\n")
+	if c.ipv6 {
+		b.WriteString("method=manual\n")
+		fmt.Fprintf(&b, "address1=%s\n", address)
+		if len(ipv6DNS) > 0 {
+			fmt.Fprintf(&b, "dns=%s;\n", strings.Join(ipv6DNS, ";"))
+		}
+	} else {
+		b.WriteString("method=ignore\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// MachineDeploymentSysctlsAnnotation adds kernel parameters to the node tuning, e.g. "vm.max_map_count=262144,net.core.somaxconn=1024".
+	MachineDeploymentSysctlsAnnotation = "k8c.io/node-tuning-sysctls"
+	// MachineDeploymentKernelModulesAnnotation adds kernel modules to the node tuning, e.g. "nvme_tcp,dm_thin_pool".
+	MachineDeploymentKernelModulesAnnotation = "k8c.io/node-tuning-kernel-modules"
+	// MachineDeploymentHugePagesAnnotation sets the number of huge pages per size of the node tuning, e.g. "2Mi=512,1Gi=2".
+	// It replaces the huge pages of the same size of the OSP, including their reserved pages.
+	MachineDeploymentHugePagesAnnotation = "k8c.io/node-tuning-hugepages"
+	// MachineDeploymentTransparentHugePagesAnnotation sets the transparent huge pages mode of the node tuning.
+	MachineDeploymentTransparentHugePagesAnnotation = "k8c.io/node-tuning-transparent-hugepages"
+
+	nodeTuningSysctlsPath       = "/etc/sysctl.d/90-node-tuning.conf"
+	nodeTuningKernelModulesPath = "/etc/modules-load.d/node-tuning.conf"
+	nodeTuningScriptPath        = "/opt/bin/node-tuning"
+	nodeTuningKubeletDropInPath = "/etc/systemd/system/kubelet.service.d/10-node-tuning.conf"
+)
+
+var (
+	kernelModuleRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	sysctlKeyRegexp    = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+)
+
+// resolveNodeTuning merges the node tuning annotations of the machine deployment into the node tuning of the OSP.
+func resolveNodeTuning(tuning *osmv1alpha1.NodeTuning, annotations map[string]string) (*osmv1alpha1.NodeTuning, error) {
+	override, err := nodeTuningFromAnnotations(annotations)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateNodeTuning(override); err != nil {
+		return nil, err
+	}
+
+	return mergeNodeTuning(tuning, override), nil
+}
+
+// nodeTuningInBootstrapConfig returns true if the node tuning of the OSP is applied by its bootstrap config. That's the
+// case if only the bootstrap config has a node tuning, or if the OSP has no provisioning files at all and keeps
+// everything in the bootstrap config.
+func nodeTuningInBootstrapConfig(osp *osmv1alpha1.OperatingSystemProfile) bool {
+	if osp.Spec.ProvisioningConfig.NodeTuning != nil {
+		return false
+	}
+
+	return osp.Spec.BootstrapConfig.NodeTuning != nil || len(osp.Spec.ProvisioningConfig.Files) == 0
+}
+
+// ValidateNodeTuning ensures that kernel module names and sysctl keys are plain names and that sysctl values are a
+// single line, since they are written to the node tuning script and the sysctl.d file. Huge pages can't reserve more
+// pages than they allocate.
+func ValidateNodeTuning(tuning *osmv1alpha1.NodeTuning) error {
+	if tuning == nil {
+		return nil
+	}
+
+	for _, hugePages := range tuning.HugePages {
+		if hugePages.Reserved > hugePages.Count {
+			return fmt.Errorf("invalid huge pages of size %s: %d reserved pages exceed the %d allocated ones", hugePages.Size, hugePages.Reserved, hugePages.Count)
+		}
+	}
+
+	for _, module := range tuning.KernelModules {
+		if !kernelModuleRegexp.MatchString(module) {
+			return fmt.Errorf("invalid kernel module %q: must match %s", module, kernelModuleRegexp)
+		}
+	}
+
+	for key, value := range tuning.Sysctls {
+		if !sysctlKeyRegexp.MatchString(key) {
+			return fmt.Errorf("invalid sysctl %q: must match %s", key, sysctlKeyRegexp)
+		}
+		if strings.ContainsAny(value, "\n\r") {
+			return fmt.Errorf("invalid value of sysctl %q: must not contain line breaks", key)
+		}
+	}
+
+	return nil
+}
+
+func nodeTuningFromAnnotations(annotations map[string]string) (*osmv1alpha1.NodeTuning, error) {
+	var tuning *osmv1alpha1.NodeTuning
+	ensure := func() *osmv1alpha1.NodeTuning {
+		if tuning == nil {
+			tuning = &osmv1alpha1.NodeTuning{}
+		}
+		return tuning
+	}
+
+	if val, ok := annotations[MachineDeploymentSysctlsAnnotation]; ok {
+		sysctls := getKeyValueMap(val, "=")
+		if sysctls != nil {
+			ensure().Sysctls = *sysctls
+		}
+	}
+
+	if val, ok := annotations[MachineDeploymentKernelModulesAnnotation]; ok {
+		for _, module := range strings.Split(val, ",") {
+			if module = strings.TrimSpace(module); module != "" {
+				ensure().KernelModules = append(ensure().KernelModules, module)
+			}
+		}
+	}
+
+	if val, ok := annotations[MachineDeploymentHugePagesAnnotation]; ok {
+		hugePages := getKeyValueMap(val, "=")
+		if hugePages != nil {
+			sizes := make([]string, 0, len(*hugePages))
+			for size := range *hugePages {
+				sizes = append(sizes, size)
+			}
+			sort.Strings(sizes)
+
+			for _, size := range sizes {
+				count, err := strconv.ParseInt((*hugePages)[size], 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("parsing huge pages count of size %s: %w", size, err)
+				}
+				ensure().HugePages = append(ensure().HugePages, osmv1alpha1.HugePages{Size: size, Count: int32(count)})
+			}
+		}
+	}
+
+	if val, ok := annotations[MachineDeploymentTransparentHugePagesAnnotation]; ok {
+		mode := osmv1alpha1.TransparentHugePagesMode(strings.TrimSpace(val))
+		switch mode {
+		case osmv1alpha1.TransparentHugePagesAlways, osmv1alpha1.TransparentHugePagesMadvise, osmv1alpha1.TransparentHugePagesNever:
+			ensure().TransparentHugePages = mode
+		default:
+			return nil, fmt.Errorf("invalid transparent huge pages mode %q", val)
+		}
+	}
+
+	return tuning, nil
+}
+
+// mergeNodeTuning merges the override into the base. Sysctls and huge pages of the override replace the ones with the
+// same key, kernel modules are added and the transparent huge pages mode is replaced if it's set.
+func mergeNodeTuning(base, override *osmv1alpha1.NodeTuning) *osmv1alpha1.NodeTuning {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override.DeepCopy()
+	}
+
+	result := base.DeepCopy()
+	for key, value := range override.Sysctls {
+		if result.Sysctls == nil {
+			result.Sysctls = map[string]string{}
+		}
+		result.Sysctls[key] = value
+	}
+	for _, module := range override.KernelModules {
+		if !slices.Contains(result.KernelModules, module) {
+			result.KernelModules = append(result.KernelModules, module)
+		}
+	}
+	result.HugePages = overlayByKey(result.HugePages, override.HugePages, nil, func(hugePages osmv1alpha1.HugePages) string { return hugePages.Size })
+	if override.TransparentHugePages != "" {
+		result.TransparentHugePages = override.TransparentHugePages
+	}
+
+	return result
+}
+
+// nodeTuningFiles returns the files that apply the node tuning. The kernel parameters and modules are persisted for
+// systemd, and the node tuning script applies them together with the huge pages before the kubelet starts.
+func nodeTuningFiles(tuning *osmv1alpha1.NodeTuning) ([]osmv1alpha1.File, error) {
+	if tuning == nil {
+		return nil, nil
+	}
+	if err := ValidateNodeTuning(tuning); err != nil {
+		return nil, err
+	}
+
+	var files []osmv1alpha1.File
+	script := []string{"#!/usr/bin/env bash", "set -euo pipefail", ""}
+
+	if len(tuning.KernelModules) > 0 {
+		files = append(files, inlineFile(nodeTuningKernelModulesPath, defaultFilePermissions, strings.Join(tuning.KernelModules, "\n")+"\n"))
+		script = append(script, fmt.Sprintf("modprobe -a %s", strings.Join(tuning.KernelModules, " ")))
+	}
+
+	if len(tuning.Sysctls) > 0 {
+		keys := make([]string, 0, len(tuning.Sysctls))
+		for key := range tuning.Sysctls {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var sysctls strings.Builder
+		for _, key := range keys {
+			fmt.Fprintf(&sysctls, "%s = %s\n", key, tuning.Sysctls[key])
+		}
+
+		files = append(files, inlineFile(nodeTuningSysctlsPath, defaultFilePermissions, sysctls.String()))
+		script = append(script, fmt.Sprintf("sysctl --load %s", nodeTuningSysctlsPath))
+	}
+
+	for _, hugePages := range tuning.HugePages {
+		size, err := parseHugePagesSize(hugePages.Size)
+		if err != nil {
+			return nil, err
+		}
+
+		// The kernel allocates fewer pages if there isn't enough contiguous memory. Failing keeps the kubelet from
+		// starting with less huge pages than requested, it detects the allocated pages as capacity on startup.
+		nrHugePages := fmt.Sprintf("/sys/kernel/mm/hugepages/hugepages-%dkB/nr_hugepages", size.Value()/1024)
+		script = append(script,
+			fmt.Sprintf("echo %d > %s", hugePages.Count, nrHugePages),
+			fmt.Sprintf(`if [ "$(cat %s)" -lt %d ]; then echo "failed to allocate %d huge pages of size %s" >&2; exit 1; fi`, nrHugePages, hugePages.Count, hugePages.Count, hugePages.Size),
+		)
+	}
+
+	if tuning.TransparentHugePages != "" {
+		script = append(script,
+			fmt.Sprintf("echo %s > /sys/kernel/mm/transparent_hugepage/enabled", tuning.TransparentHugePages))
+	}
+
+	if len(script) == 3 {
+		return files, nil
+	}
+
+	files = append(files,
+		inlineFile(nodeTuningScriptPath, 755, strings.Join(script, "\n")+"\n"),
+		inlineFile(nodeTuningKubeletDropInPath, defaultFilePermissions, fmt.Sprintf("[Service]\nExecStartPre=%s\n", nodeTuningScriptPath)),
+	)
+
+	return files, nil
+}
+
+// systemReservedHugePages returns the reserved huge pages of the node tuning as systemReserved resources of the kubelet,
+// e.g. hugepages-2Mi: 128Mi for 64 reserved pages of 2Mi.
+func systemReservedHugePages(tuning *osmv1alpha1.NodeTuning) (map[string]string, error) {
+	if tuning == nil {
+		return nil, nil
+	}
+
+	var reserved map[string]string
+	for _, hugePages := range tuning.HugePages {
+		if hugePages.Reserved == 0 {
+			continue
+		}
+
+		size, err := parseHugePagesSize(hugePages.Size)
+		if err != nil {
+			return nil, err
+		}
+		if reserved == nil {
+			reserved = map[string]string{}
+		}
+		// The kubelet names the huge pages resources by the canonical size, e.g. hugepages-2Mi for 2048Ki.
+		reserved["hugepages-"+size.String()] = resource.NewQuantity(size.Value()*int64(hugePages.Reserved), resource.BinarySI).String()
+	}
+
+	return reserved, nil
+}
+
+func parseHugePagesSize(value string) (resource.Quantity, error) {
+	size, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid huge pages size %q: %w", value, err)
+	}
+	if size.Value() <= 0 || size.Value()%1024 != 0 {
+		return resource.Quantity{}, fmt.Errorf("invalid huge pages size %q: must be a multiple of 1Ki", value)
+	}
+
+	return size, nil
+}
+
+func inlineFile(path string, permissions int32, data string) osmv1alpha1.File {
+	return osmv1alpha1.File{
+		Path:        path,
+		Permissions: permissions,
+		Content: osmv1alpha1.FileContent{
+			Inline: &osmv1alpha1.FileContentInline{
+				Data: data,
+			},
+		},
+	}
+}
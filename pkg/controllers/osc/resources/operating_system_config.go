@@ -20,7 +20,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
@@ -36,6 +38,7 @@ import (
 	"k8c.io/operating-system-manager/pkg/cloudprovider"
 	"k8c.io/operating-system-manager/pkg/containerruntime"
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	"k8c.io/operating-system-manager/pkg/credentialprovider"
 	"k8c.io/operating-system-manager/pkg/providerconfig/amzn2"
 	"k8c.io/operating-system-manager/pkg/providerconfig/flatcar"
 	"k8c.io/operating-system-manager/pkg/providerconfig/rhel"
@@ -57,10 +60,61 @@ const (
 	OperatingSystemConfigNamePattern        = "%s-%s-config"
 	MachineDeploymentOSPAnnotation          = "k8c.io/operating-system-profile"
 	MachineDeploymentOSPNamespaceAnnotation = "k8c.io/operating-system-profile-namespace"
+	// MachineDeploymentContainerRuntimeAnnotation selects the container runtime of the machine deployment, it
+	// overrides the container runtime that is configured for OSM.
+	MachineDeploymentContainerRuntimeAnnotation = "k8c.io/container-runtime"
+	// MachineDeploymentRuntimeHandlersAnnotation sets the additional containerd runtime handlers of the machine
+	// deployment in the format of containerruntime.ParseRuntimeHandlers, e.g. "runsc,kata". It replaces the runtime
+	// handlers that are configured for OSM, an empty value disables them.
+	MachineDeploymentRuntimeHandlersAnnotation = "k8c.io/container-runtime-handlers"
+	// RuntimeHandlerLabelPrefix prefixes the node labels that the kubelet registers for the runtime handlers of its
+	// machine, e.g. "runtime-handler.k8c.io/runsc". The RuntimeClass of the handler selects the nodes by the label.
+	RuntimeHandlerLabelPrefix = "runtime-handler.k8c.io/"
 
 	defaultFilePermissions = 644
 )
 
+// MachineDeploymentContainerRuntime returns the container runtime of the machine deployment, which defaults to the
+// given container runtime.
+func MachineDeploymentContainerRuntime(md *v1alpha1.MachineDeployment, defaultContainerRuntime string) (string, error) {
+	containerRuntime, ok := md.Annotations[MachineDeploymentContainerRuntimeAnnotation]
+	if !ok || containerRuntime == "" {
+		return defaultContainerRuntime, nil
+	}
+
+	if !containerruntime.IsSupported(containerRuntime) {
+		return "", fmt.Errorf("container runtime %q is not supported", containerRuntime)
+	}
+
+	return containerRuntime, nil
+}
+
+// MachineDeploymentRuntimeHandlers returns the runtime handlers of the machine deployment, which default to the given
+// runtime handlers. Runtime handlers are only supported by containerd, so the default ones are dropped for other
+// container runtimes.
+func MachineDeploymentRuntimeHandlers(md *v1alpha1.MachineDeployment, containerRuntime string, defaultHandlers []containerruntime.RuntimeHandler) ([]containerruntime.RuntimeHandler, error) {
+	isContainerd := containerRuntime == string(osmv1alpha1.ContainerRuntimeContainerd)
+
+	value, ok := md.Annotations[MachineDeploymentRuntimeHandlersAnnotation]
+	if !ok {
+		if !isContainerd {
+			return nil, nil
+		}
+		return defaultHandlers, nil
+	}
+
+	handlers, err := containerruntime.ParseRuntimeHandlers(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(handlers) > 0 && !isContainerd {
+		return nil, fmt.Errorf("runtime handlers are not supported by container runtime %q", containerRuntime)
+	}
+
+	return handlers, nil
+}
+
 // GenerateOperatingSystemConfig return an OperatingSystemConfig generated against the input data
 func GenerateOperatingSystemConfig(
 	md *v1alpha1.MachineDeployment,
@@ -79,7 +133,13 @@ func GenerateOperatingSystemConfig(
 	nodeHTTPProxy string,
 	nodeNoProxy string,
 	containerRuntimeConfig containerruntime.Config,
+	registryOverrides RegistryOverrides,
+	credentialProviderConfig credentialprovider.Config,
+	registryCredentialsSecretName string,
 	kubeletFeatureGates map[string]bool,
+	referencedFileContents ReferencedFileContents,
+	parameterValues map[string]string,
+	overrides []osmv1alpha1.OperatingSystemConfigOverride,
 ) (*osmv1alpha1.OperatingSystemConfig, error) {
 	ospOriginal := osp.DeepCopy()
 
@@ -98,7 +158,7 @@ func GenerateOperatingSystemConfig(
 	}
 
 	// Prepare container runtime configuration
-	crConfig, crAuthConfig, registryHostConfigs, err := prepareContainerRuntimeConfig(md, containerRuntimeConfig)
+	crConfig, crAuthConfig, registryHostConfigs, err := prepareContainerRuntimeConfig(md, containerRuntimeConfig, registryOverrides)
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +175,12 @@ func GenerateOperatingSystemConfig(
 		return nil, err
 	}
 
+	// Prepare image credential providers of the kubelet
+	credentialProviders, credentialProviderFiles, credentialProviderExecutables, err := prepareCredentialProviderConfig(providerConfig, credentialProviderConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare kubelet version string
 	kubeletVersionStr, err := formatKubeletVersion(md.Spec.Template.Spec.Versions.Kubelet)
 	if err != nil {
@@ -147,17 +213,85 @@ func GenerateOperatingSystemConfig(
 		return nil, err
 	}
 
+	// Resolve node tuning of the config that applies the node tuning of the OSP
+	bootstrapNodeTuning, provisioningNodeTuning := osp.Spec.BootstrapConfig.NodeTuning, osp.Spec.ProvisioningConfig.NodeTuning
+	if nodeTuningInBootstrapConfig(osp) {
+		bootstrapNodeTuning, err = resolveNodeTuning(bootstrapNodeTuning, md.Annotations)
+		data.NodeTuning = bootstrapNodeTuning
+	} else {
+		provisioningNodeTuning, err = resolveNodeTuning(provisioningNodeTuning, md.Annotations)
+		data.NodeTuning = provisioningNodeTuning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve node tuning: %w", err)
+	}
+	data.SystemReservedHugePages, err = systemReservedHugePages(data.NodeTuning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve huge pages: %w", err)
+	}
+
+	if len(credentialProviders) > 0 {
+		data.ImageCredentialProviderConfig = credentialprovider.ConfigFileName
+		data.ImageCredentialProviderBinDir = credentialprovider.BinDir
+		data.ImageCredentialProviders = credentialProviders
+	}
+	data.NodeLabels = runtimeHandlerNodeLabels(containerRuntimeConfig.RuntimeHandlers)
+	if registryCredentialsSecretName != "" {
+		data.RegistryCredentialsSecretName = registryCredentialsSecretName
+		data.RegistryCredentialsFile = credentialprovider.RegistryCredentialsFileName
+	}
+
+	// Resolve the parameter values of the machine deployment
+	params, err := ResolveParameters(osp.Spec.Parameters, parameterValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve OperatingSystemProfile parameters: %w", err)
+	}
+	data.Params = params
+
+	// Expose the machine deployment and its provider spec to the templates
+	data.MachineDeployment = newMachineDeploymentView(md)
+	data.ProviderSpec, err = newProviderSpecView(providerConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure RHEL subscription if needed
 	configureRHELSubscription(providerConfig, osp, data)
 
 	// Render files and build OSC spec
-	renderedBootstrappingFiles, renderedProvisioningFiles, err := renderOSPFiles(osp, containerRuntime, data, registryHostConfigs)
+	target := newSelectorTarget(md, osp, osmv1alpha1.CloudProvider(providerConfig.CloudProvider))
+	generatedFiles := make(map[string]string, len(registryHostConfigs)+len(credentialProviderFiles))
+	maps.Copy(generatedFiles, registryHostConfigs)
+	maps.Copy(generatedFiles, credentialProviderFiles)
+	renderedBootstrappingFiles, renderedProvisioningFiles, err := renderOSPFiles(osp, containerRuntime, data, target, generatedFiles, referencedFileContents)
+	if err != nil {
+		return nil, err
+	}
+	renderedProvisioningFiles = injectGeneratedFiles(renderedProvisioningFiles, credentialProviderExecutables, 755)
+
+	renderedBootstrappingFiles, err = injectNodeTuningFiles(renderedBootstrappingFiles, bootstrapNodeTuning)
+	if err != nil {
+		return nil, err
+	}
+	renderedProvisioningFiles, err = injectNodeTuningFiles(renderedProvisioningFiles, provisioningNodeTuning)
 	if err != nil {
 		return nil, err
 	}
 
 	buildOSCSpec(osc, ospOriginal, osp, providerConfig, renderedBootstrappingFiles, renderedProvisioningFiles)
 
+	if err := applyOperatingSystemConfigOverrides(osc, overrides); err != nil {
+		return nil, err
+	}
+
+	// Drop the units and files of the OSP and the overrides that don't apply to the machines of the machine deployment
+	if err := selectOSCConfig(&osc.Spec.BootstrapConfig, target); err != nil {
+		return nil, fmt.Errorf("failed to select bootstrapping config: %w", err)
+	}
+	if err := selectOSCConfig(&osc.Spec.ProvisioningConfig, target); err != nil {
+		return nil, fmt.Errorf("failed to select provisioning config: %w", err)
+	}
+
 	return osc, nil
 }
 
@@ -167,6 +301,10 @@ func parseProviderConfig(md *v1alpha1.MachineDeployment) (providerconfig.Config,
 		return providerconfig.Config{}, fmt.Errorf("providerSpec cannot be empty")
 	}
 
+	if err := validateNetworkConfigFields(md.Spec.Template.Spec.ProviderSpec.Value.Raw); err != nil {
+		return providerconfig.Config{}, err
+	}
+
 	var providerConfig providerconfig.Config
 	if err := jsonutil.StrictUnmarshal(md.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerConfig); err != nil {
 		return providerconfig.Config{}, fmt.Errorf("failed to decode provider configs: %w", err)
@@ -175,8 +313,9 @@ func parseProviderConfig(md *v1alpha1.MachineDeployment) (providerconfig.Config,
 	return providerConfig, nil
 }
 
-// prepareContainerRuntimeConfig prepares container runtime configuration and returns configs
-func prepareContainerRuntimeConfig(md *v1alpha1.MachineDeployment, containerRuntimeConfig containerruntime.Config) (string, string, map[string]string, error) {
+// prepareContainerRuntimeConfig prepares container runtime configuration and returns configs, the registry overrides of
+// the machine deployment are merged into the configured registry settings.
+func prepareContainerRuntimeConfig(md *v1alpha1.MachineDeployment, containerRuntimeConfig containerruntime.Config, registryOverrides RegistryOverrides) (string, string, map[string]string, error) {
 	kubeletConfigs, err := getKubeletConfigs(md.Annotations)
 	if err != nil {
 		return "", "", nil, err
@@ -190,7 +329,7 @@ func prepareContainerRuntimeConfig(md *v1alpha1.MachineDeployment, containerRunt
 		containerRuntimeConfig.ContainerLogMaxFiles = *kubeletConfigs.ContainerLogMaxFiles
 	}
 
-	crEngine := containerRuntimeConfig.Engine()
+	crEngine := registryOverrides.Apply(containerRuntimeConfig).Engine()
 	crConfig, err := crEngine.Config()
 	if err != nil {
 		return "", "", nil, fmt.Errorf("failed to generate container runtime config: %w", err)
@@ -255,6 +394,54 @@ func prepareCloudProviderConfig(providerConfig providerconfig.Config, kubeletVer
 	return cloudConfig, inTreeCCM, external, nil
 }
 
+// prepareCredentialProviderConfig returns the image credential providers of the kubelet and the files they require,
+// i.e. the CredentialProviderConfig and the cloud-configs of the providers, and the executables of script providers
+func prepareCredentialProviderConfig(providerConfig providerconfig.Config, credentialProviderConfig credentialprovider.Config) ([]credentialprovider.Provider, map[string]string, map[string]string, error) {
+	providers, err := credentialProviderConfig.Providers(osmv1alpha1.CloudProvider(providerConfig.CloudProvider))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get image credential providers: %w", err)
+	}
+	if len(providers) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	kubeletConfig, err := credentialprovider.KubeletConfig(providers)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	files := map[string]string{credentialprovider.ConfigFileName: kubeletConfig}
+	executables := map[string]string{}
+
+	for _, provider := range providers {
+		if provider.Script != "" {
+			executables[path.Join(credentialprovider.BinDir, provider.Name)] = provider.Script
+		}
+
+		if provider.CloudConfigFile == "" {
+			continue
+		}
+
+		cloudConfig, err := cloudprovider.GetImageCredentialProviderCloudConfig(providerConfig)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to fetch cloud-config of image credential provider %s: %w", provider.Name, err)
+		}
+		files[provider.CloudConfigFile] = cloudConfig
+	}
+
+	return providers, files, executables, nil
+}
+
+// runtimeHandlerNodeLabels returns the node labels of the runtime handlers in the format of the --node-labels flag of
+// the kubelet.
+func runtimeHandlerNodeLabels(handlers []containerruntime.RuntimeHandler) string {
+	labels := make([]string, 0, len(handlers))
+	for _, handler := range handlers {
+		labels = append(labels, RuntimeHandlerLabelPrefix+handler.Name+"=true")
+	}
+
+	return strings.Join(labels, ",")
+}
+
 // formatKubeletVersion ensures kubelet version is prefixed with "v"
 func formatKubeletVersion(version string) (string, error) {
 	kubeletVersion, err := semver.NewVersion(version)
@@ -330,8 +517,12 @@ func buildFilesData(
 		data.NetworkConfig = providerConfig.Network
 	}
 
+	// Flatcar OSPs configure static addressing with systemd-networkd from the NetworkConfig directly.
 	if providerConfig.Network.IsStaticIPConfig() && providerConfig.OperatingSystem != providerconfig.OperatingSystemFlatcar {
-		return filesData{}, fmt.Errorf("static IP config is not supported with: %s", providerConfig.OperatingSystem)
+		data.StaticNetworkConfig, err = renderStaticNetworkConfig(providerConfig.OperatingSystem, providerConfig.Network)
+		if err != nil {
+			return filesData{}, fmt.Errorf("failed to render static network config: %w", err)
+		}
 	}
 
 	err = setOperatingSystemConfig(providerConfig.OperatingSystem, providerConfig.OperatingSystemSpec, &data)
@@ -361,31 +552,42 @@ func configureRHELSubscription(providerConfig providerconfig.Config, osp *osmv1a
 	osp.Spec.ProvisioningConfig.CloudInitModules.RHSubscription = rhSubscription
 }
 
-// renderOSPFiles renders OSP files and injects registry host configs
-func renderOSPFiles(osp *osmv1alpha1.OperatingSystemProfile, containerRuntime string, data filesData, registryHostConfigs map[string]string) ([]osmv1alpha1.File, []osmv1alpha1.File, error) {
-	renderedBootstrappingFiles, err := renderedFiles(osp.Spec.BootstrapConfig, containerRuntime, data)
+// renderOSPFiles renders OSP files, resolves referenced file contents and injects the files that are generated by OSM
+func renderOSPFiles(osp *osmv1alpha1.OperatingSystemProfile, containerRuntime string, data filesData, target selectorTarget, generatedFiles map[string]string, referencedFileContents ReferencedFileContents) ([]osmv1alpha1.File, []osmv1alpha1.File, error) {
+	renderedBootstrappingFiles, err := renderedFiles(osp.Spec.BootstrapConfig, containerRuntime, data, target, referencedFileContents)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to render bootstrapping file templates: %w", err)
 	}
 
-	renderedProvisioningFiles, err := renderedFiles(osp.Spec.ProvisioningConfig, containerRuntime, data)
+	renderedProvisioningFiles, err := renderedFiles(osp.Spec.ProvisioningConfig, containerRuntime, data, target, referencedFileContents)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to render provisioning file templates: %w", err)
 	}
 
-	// Inject registry host configuration files (hosts.toml) into provisioning files
-	if len(registryHostConfigs) > 0 {
-		renderedProvisioningFiles = injectRegistryHostConfigs(renderedProvisioningFiles, registryHostConfigs)
+	// Inject generated files, e.g. registry host configuration files (hosts.toml), into provisioning files
+	if len(generatedFiles) > 0 {
+		renderedProvisioningFiles = injectGeneratedFiles(renderedProvisioningFiles, generatedFiles, 600)
 	}
 
 	return renderedBootstrappingFiles, renderedProvisioningFiles, nil
 }
 
-// injectRegistryHostConfigs injects registry host configuration files into provisioning files
-func injectRegistryHostConfigs(files []osmv1alpha1.File, registryHostConfigs map[string]string) []osmv1alpha1.File {
+// injectNodeTuningFiles adds the node tuning files to the files, replacing files with the same path
+func injectNodeTuningFiles(files []osmv1alpha1.File, tuning *osmv1alpha1.NodeTuning) ([]osmv1alpha1.File, error) {
+	tuningFiles, err := nodeTuningFiles(tuning)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render node tuning files: %w", err)
+	}
+
+	return overlayByKey(files, tuningFiles, nil, func(file osmv1alpha1.File) string { return file.Path }), nil
+}
+
+// injectGeneratedFiles injects the files that are generated by OSM, e.g. registry host configuration files, into
+// provisioning files
+func injectGeneratedFiles(files []osmv1alpha1.File, generatedFiles map[string]string, permissions int32) []osmv1alpha1.File {
 	// Sort paths for deterministic output
-	paths := make([]string, 0, len(registryHostConfigs))
-	for path := range registryHostConfigs {
+	paths := make([]string, 0, len(generatedFiles))
+	for path := range generatedFiles {
 		paths = append(paths, path)
 	}
 	sort.Strings(paths)
@@ -393,10 +595,10 @@ func injectRegistryHostConfigs(files []osmv1alpha1.File, registryHostConfigs map
 	for _, path := range paths {
 		files = append(files, osmv1alpha1.File{
 			Path:        path,
-			Permissions: 600,
+			Permissions: permissions,
 			Content: osmv1alpha1.FileContent{
 				Inline: &osmv1alpha1.FileContentInline{
-					Data: registryHostConfigs[path],
+					Data: generatedFiles[path],
 				},
 			},
 		})
@@ -417,13 +619,25 @@ func buildOSCSpec(osc *osmv1alpha1.OperatingSystemConfig, ospOriginal *osmv1alph
 		BootstrapConfig: osmv1alpha1.OSCConfig{
 			Units:            ospOriginal.Spec.BootstrapConfig.Units,
 			Files:            renderedBootstrappingFiles,
+			Directories:      ospOriginal.Spec.BootstrapConfig.Directories,
+			Links:            ospOriginal.Spec.BootstrapConfig.Links,
+			Storage:          ospOriginal.Spec.BootstrapConfig.Storage,
+			Packages:         ospOriginal.Spec.BootstrapConfig.Packages,
 			UserSSHKeys:      providerConfig.SSHPublicKeys,
+			Users:            ospOriginal.Spec.BootstrapConfig.Users,
+			Groups:           ospOriginal.Spec.BootstrapConfig.Groups,
 			CloudInitModules: osp.Spec.BootstrapConfig.CloudInitModules,
 		},
 		ProvisioningConfig: osmv1alpha1.OSCConfig{
 			Units:            ospOriginal.Spec.ProvisioningConfig.Units,
 			Files:            renderedProvisioningFiles,
+			Directories:      ospOriginal.Spec.ProvisioningConfig.Directories,
+			Links:            ospOriginal.Spec.ProvisioningConfig.Links,
+			Storage:          ospOriginal.Spec.ProvisioningConfig.Storage,
+			Packages:         ospOriginal.Spec.ProvisioningConfig.Packages,
 			UserSSHKeys:      providerConfig.SSHPublicKeys,
+			Users:            ospOriginal.Spec.ProvisioningConfig.Users,
+			Groups:           ospOriginal.Spec.ProvisioningConfig.Groups,
 			CloudInitModules: osp.Spec.ProvisioningConfig.CloudInitModules,
 		},
 	}
@@ -444,6 +658,11 @@ type filesData struct {
 	ContainerRuntime           string
 	CloudProviderName          osmv1alpha1.CloudProvider
 	NetworkConfig              *providerconfig.NetworkConfig
+	StaticNetworkConfig        string
+	NodeTuning                 *osmv1alpha1.NodeTuning
+	Params                     map[string]interface{}
+	MachineDeployment          machineDeploymentView
+	ProviderSpec               providerSpecView
 	ExternalCloudProvider      bool
 	InitialTaints              string
 	HTTPProxy                  *string
@@ -455,6 +674,22 @@ type filesData struct {
 	NetworkIPFamily            string
 	PauseImage                 string
 
+	// NodeLabels are the labels that the kubelet registers the node with, in the format of its --node-labels flag.
+	NodeLabels string
+	// SystemReservedHugePages are the huge pages that the node tuning reserves for system daemons, keyed by their
+	// resource name. They're added to the systemReserved of the kubelet.
+	SystemReservedHugePages map[string]string
+	// ImageCredentialProviderConfig is the path of the CredentialProviderConfig of the kubelet, it's empty if no image
+	// credential provider is configured.
+	ImageCredentialProviderConfig string
+	ImageCredentialProviderBinDir string
+	ImageCredentialProviders      []credentialprovider.Provider
+	// RegistryCredentialsSecretName is the name of the secret in the cloud-init-settings namespace with the registry
+	// credentials that the nodes fetch into RegistryCredentialsFile, it's empty if the credentials are part of the
+	// container runtime config.
+	RegistryCredentialsSecretName string
+	RegistryCredentialsFile       string
+
 	kubeletConfig
 	operatingSystemConfig
 	bootstrapConfig
@@ -488,23 +723,48 @@ type bootstrapConfig struct {
 	BootstrapKubeconfigSecretName string
 }
 
-func renderedFiles(config osmv1alpha1.OSPConfig, containerRuntime string, data filesData) ([]osmv1alpha1.File, error) {
-	config.Files = append(config.Files, selectAdditionalFiles(config, containerRuntime)...)
+func renderedFiles(config osmv1alpha1.OSPConfig, containerRuntime string, data filesData, target selectorTarget, referencedFileContents ReferencedFileContents) ([]osmv1alpha1.File, error) {
+	files, err := selectFiles(append(config.Files, selectAdditionalFiles(config, containerRuntime)...), target)
+	if err != nil {
+		return nil, err
+	}
+	config.Files = files
 	additionalTemplates, err := selectAdditionalTemplates(config, containerRuntime, data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add OSP templates: %w", err)
 	}
-	populatedFiles, err := populateFilesList(config.Files, additionalTemplates, data)
+	populatedFiles, err := populateFilesList(config.Files, additionalTemplates, data, referencedFileContents)
 	if err != nil {
 		return nil, fmt.Errorf("failed to populate OSP file template: %w", err)
 	}
 	return populatedFiles, nil
 }
 
-func populateFilesList(files []osmv1alpha1.File, additionalTemplates []string, d filesData) ([]osmv1alpha1.File, error) {
+func populateFilesList(files []osmv1alpha1.File, additionalTemplates []string, d filesData, referencedFileContents ReferencedFileContents) ([]osmv1alpha1.File, error) {
 	funcMap := fm.ExtraTxtFuncMap()
 	var pfiles []osmv1alpha1.File
 	for _, file := range files {
+		// Referenced contents are not templated, they are inlined into the OSC as they are.
+		if file.Content.Inline == nil {
+			content, err := referencedFileContents.lookup(file.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve OSP file [%s] content: %w", file.Path, err)
+			}
+
+			pfile := file.DeepCopy()
+			pfile.Content = osmv1alpha1.FileContent{
+				Inline: &osmv1alpha1.FileContentInline{
+					Data: content,
+				},
+			}
+
+			if pfile.Permissions == 0 {
+				pfile.Permissions = defaultFilePermissions
+			}
+			pfiles = append(pfiles, *pfile)
+			continue
+		}
+
 		content := file.Content.Inline.Data
 		tmpl, err := template.New(file.Path).Funcs(funcMap).Parse(content)
 		if err != nil {
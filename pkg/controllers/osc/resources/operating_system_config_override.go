@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FetchOperatingSystemConfigOverrides returns the overrides in the given namespace that apply to the machine deployment,
+// sorted by name. It also returns a hash over the overrides, which is empty if no override applies.
+func FetchOperatingSystemConfigOverrides(ctx context.Context, client ctrlruntimeclient.Client, namespace string, md *clusterv1alpha1.MachineDeployment) ([]osmv1alpha1.OperatingSystemConfigOverride, string, error) {
+	overrides := &osmv1alpha1.OperatingSystemConfigOverrideList{}
+	if err := client.List(ctx, overrides, ctrlruntimeclient.InNamespace(namespace)); err != nil {
+		return nil, "", fmt.Errorf("failed to list OperatingSystemConfigOverrides in namespace %q: %w", namespace, err)
+	}
+
+	sort.Slice(overrides.Items, func(i, j int) bool {
+		return overrides.Items[i].Name < overrides.Items[j].Name
+	})
+
+	var matching []osmv1alpha1.OperatingSystemConfigOverride
+	for _, override := range overrides.Items {
+		matches, err := OverrideMatches(&override, md)
+		if err != nil {
+			return nil, "", err
+		}
+		if matches {
+			matching = append(matching, override)
+		}
+	}
+
+	if len(matching) == 0 {
+		return nil, "", nil
+	}
+
+	specs := make(map[string]osmv1alpha1.OperatingSystemConfigOverrideSpec, len(matching))
+	for _, override := range matching {
+		specs[override.Name] = override.Spec
+	}
+
+	hash, err := HashJSON(specs)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to json encode OperatingSystemConfigOverrides: %w", err)
+	}
+
+	return matching, hash, nil
+}
+
+// OverrideMatches checks if the override lists the machine deployment or selects it by its labels. Machine deployments
+// are listed as namespace/name, names without a namespace refer to the namespace of the override.
+func OverrideMatches(override *osmv1alpha1.OperatingSystemConfigOverride, md *clusterv1alpha1.MachineDeployment) (bool, error) {
+	for _, ref := range override.Spec.MachineDeployments {
+		namespace, name, found := strings.Cut(ref, "/")
+		if !found {
+			namespace, name = override.Namespace, ref
+		}
+		if namespace == md.Namespace && name == md.Name {
+			return true, nil
+		}
+	}
+
+	if override.Spec.MachineDeploymentSelector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(override.Spec.MachineDeploymentSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector in OperatingSystemConfigOverride %q: %w", override.Name, err)
+	}
+
+	return selector.Matches(labels.Set(md.Labels)), nil
+}
+
+// applyOperatingSystemConfigOverrides adds the configuration of the overrides to the OSC in the given order.
+func applyOperatingSystemConfigOverrides(osc *osmv1alpha1.OperatingSystemConfig, overrides []osmv1alpha1.OperatingSystemConfigOverride) error {
+	for _, override := range overrides {
+		if override.Spec.BootstrapConfig != nil {
+			if err := applyOverrideConfig(&osc.Spec.BootstrapConfig, override.Spec.BootstrapConfig.DeepCopy()); err != nil {
+				return fmt.Errorf("failed to apply bootstrap config of OperatingSystemConfigOverride %q: %w", override.Name, err)
+			}
+		}
+		if override.Spec.ProvisioningConfig != nil {
+			if err := applyOverrideConfig(&osc.Spec.ProvisioningConfig, override.Spec.ProvisioningConfig.DeepCopy()); err != nil {
+				return fmt.Errorf("failed to apply provisioning config of OperatingSystemConfigOverride %q: %w", override.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func applyOverrideConfig(config *osmv1alpha1.OSCConfig, override *osmv1alpha1.OSCOverrideConfig) error {
+	for _, file := range override.Files {
+		if file.Content.Inline == nil {
+			return fmt.Errorf("content of file %q must be inline", file.Path)
+		}
+	}
+
+	config.Files = overlayByKey(config.Files, override.Files, nil, func(file osmv1alpha1.File) string { return file.Path })
+	config.Units = overlayByKey(config.Units, override.Units, nil, func(unit osmv1alpha1.Unit) string { return unit.Name })
+
+	for _, key := range override.UserSSHKeys {
+		if !slices.Contains(config.UserSSHKeys, key) {
+			config.UserSSHKeys = append(config.UserSSHKeys, key)
+		}
+	}
+
+	// The modules may be shared with the OSP, they are copied before they're modified.
+	if override.CloudInitModules != nil {
+		config.CloudInitModules = mergeCloudInitModules(config.CloudInitModules.DeepCopy(), override.CloudInitModules)
+	}
+	config.Packages = mergePackages(config.Packages, override.Packages)
+
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveOperatingSystemProfile overlays the OSP onto the chain of base OSPs that it extends. It returns the resolved OSP
+// and the versions of the base OSPs, which is empty if the OSP doesn't extend another OSP.
+func ResolveOperatingSystemProfile(ctx context.Context, client ctrlruntimeclient.Reader, osp *osmv1alpha1.OperatingSystemProfile) (*osmv1alpha1.OperatingSystemProfile, string, error) {
+	// chain contains the OSP followed by its base OSPs.
+	chain := []*osmv1alpha1.OperatingSystemProfile{osp}
+	visited := map[string]bool{osp.Name: true}
+
+	for current := osp; current.Spec.Extends != ""; {
+		name := current.Spec.Extends
+		if visited[name] {
+			return nil, "", fmt.Errorf("OperatingSystemProfile %q has a cyclic base chain: %s -> %s", osp.Name, chainNames(chain), name)
+		}
+		visited[name] = true
+
+		base := &osmv1alpha1.OperatingSystemProfile{}
+		if err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: osp.Namespace}, base); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil, "", fmt.Errorf("base OperatingSystemProfile %q of %q not found", name, current.Name)
+			}
+			return nil, "", fmt.Errorf("failed to get base OperatingSystemProfile %q from namespace %q: %w", name, osp.Namespace, err)
+		}
+
+		chain = append(chain, base)
+		current = base
+	}
+
+	if len(chain) == 1 {
+		return osp, "", nil
+	}
+
+	// Overlay the chain starting with the root OSP.
+	resolved := chain[len(chain)-1].DeepCopy()
+	for i := len(chain) - 2; i >= 0; i-- {
+		if err := overlayOperatingSystemProfile(resolved, chain[i]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	baseVersions := make([]string, 0, len(chain)-1)
+	for _, base := range chain[1:] {
+		baseVersions = append(baseVersions, fmt.Sprintf("%s=%s", base.Name, base.Spec.Version))
+	}
+
+	return resolved, strings.Join(baseVersions, ","), nil
+}
+
+func chainNames(chain []*osmv1alpha1.OperatingSystemProfile) string {
+	names := make([]string, 0, len(chain))
+	for _, osp := range chain {
+		names = append(names, osp.Name)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// overlayOperatingSystemProfile overlays the child OSP onto the resolved base OSP. The result keeps the metadata and version
+// of the child.
+func overlayOperatingSystemProfile(base, child *osmv1alpha1.OperatingSystemProfile) error {
+	if base.Spec.OSName != child.Spec.OSName {
+		return fmt.Errorf("OperatingSystemProfile %q for %s cannot extend OperatingSystemProfile %q for %s", child.Name, child.Spec.OSName, base.Name, base.Spec.OSName)
+	}
+	// The provisioning utility defaults to cloud-init, so a child that doesn't set it can't be told apart from a child
+	// that switches to cloud-init. The files of the base are written for its provisioning utility anyway.
+	if base.Spec.ProvisioningUtility != child.Spec.ProvisioningUtility {
+		return fmt.Errorf("OperatingSystemProfile %q using %s cannot extend OperatingSystemProfile %q using %s", child.Name, child.Spec.ProvisioningUtility, base.Name, base.Spec.ProvisioningUtility)
+	}
+
+	base.ObjectMeta = *child.ObjectMeta.DeepCopy()
+	base.Status = *child.Status.DeepCopy()
+	base.Spec.Version = child.Spec.Version
+	base.Spec.Extends = child.Spec.Extends
+	if child.Spec.OSVersion != "" {
+		base.Spec.OSVersion = child.Spec.OSVersion
+	}
+	if len(child.Spec.SupportedCloudProviders) > 0 {
+		base.Spec.SupportedCloudProviders = child.DeepCopy().Spec.SupportedCloudProviders
+	}
+	base.Spec.Parameters = overlayByKey(base.Spec.Parameters, child.DeepCopy().Spec.Parameters, nil, func(parameter osmv1alpha1.Parameter) string { return parameter.Name })
+
+	overlayOSPConfig(&base.Spec.BootstrapConfig, child.Spec.BootstrapConfig.DeepCopy())
+	overlayOSPConfig(&base.Spec.ProvisioningConfig, child.Spec.ProvisioningConfig.DeepCopy())
+
+	return nil
+}
+
+func overlayOSPConfig(base, child *osmv1alpha1.OSPConfig) {
+	removals := osmv1alpha1.OSPConfigRemovals{}
+	if child.Remove != nil {
+		removals = *child.Remove
+	}
+
+	base.Files = overlayByKey(base.Files, child.Files, removals.Files, func(file osmv1alpha1.File) string { return file.Path })
+	base.Directories = overlayByKey(base.Directories, child.Directories, nil, func(directory osmv1alpha1.Directory) string { return directory.Path })
+	base.Links = overlayByKey(base.Links, child.Links, nil, func(link osmv1alpha1.Link) string { return link.Path })
+	base.Units = overlayByKey(base.Units, child.Units, removals.Units, func(unit osmv1alpha1.Unit) string { return unit.Name })
+	base.Users = overlayByKey(base.Users, child.Users, nil, func(user osmv1alpha1.User) string { return user.Name })
+	base.Groups = overlayByKey(base.Groups, child.Groups, nil, func(group osmv1alpha1.Group) string { return group.Name })
+	base.SupportedContainerRuntimes = overlayByKey(base.SupportedContainerRuntimes, child.SupportedContainerRuntimes, removals.ContainerRuntimes,
+		func(cr osmv1alpha1.ContainerRuntimeSpec) osmv1alpha1.ContainerRuntime { return cr.Name })
+
+	for _, name := range removals.Templates {
+		delete(base.Templates, name)
+	}
+	for name, template := range child.Templates {
+		if base.Templates == nil {
+			base.Templates = map[string]string{}
+		}
+		base.Templates[name] = template
+	}
+	for _, name := range child.TemplateLibraries {
+		if !slices.Contains(base.TemplateLibraries, name) {
+			base.TemplateLibraries = append(base.TemplateLibraries, name)
+		}
+	}
+
+	if child.CloudInitModules != nil {
+		base.CloudInitModules = child.CloudInitModules
+	}
+	if child.Storage != nil {
+		base.Storage = child.Storage
+	}
+	base.NodeTuning = mergeNodeTuning(base.NodeTuning, child.NodeTuning)
+	base.Packages = mergePackages(base.Packages, child.Packages)
+
+	// Removals only apply to the base of an OSP, they're not carried over to the resolved OSP.
+	base.Remove = nil
+}
+
+// mergePackages adds the repositories and packages of the override to the base, replacing the ones with the same name.
+func mergePackages(base, override *osmv1alpha1.Packages) *osmv1alpha1.Packages {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override.DeepCopy()
+	}
+
+	return &osmv1alpha1.Packages{
+		Repositories: overlayByKey(base.Repositories, override.Repositories, nil, func(repository osmv1alpha1.PackageRepository) string { return repository.Name }),
+		Packages:     overlayByKey(base.Packages, override.Packages, nil, func(pkg osmv1alpha1.Package) string { return pkg.Name }),
+	}
+}
+
+// overlayByKey removes the base items with the given keys and then replaces the base items with the child items that
+// have the same key. Child items with new keys are appended.
+func overlayByKey[T any, K comparable](base, child []T, remove []K, key func(T) K) []T {
+	var result []T
+	for _, item := range base {
+		if !slices.Contains(remove, key(item)) {
+			result = append(result, item)
+		}
+	}
+
+	for _, item := range child {
+		index := slices.IndexFunc(result, func(existing T) bool { return key(existing) == key(item) })
+		if index >= 0 {
+			result[index] = item
+			continue
+		}
+		result = append(result, item)
+	}
+
+	return result
+}
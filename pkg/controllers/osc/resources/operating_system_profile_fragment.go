@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// appliedFragment is used to calculate the hash over the fragments that were merged into an OSP.
+type appliedFragment struct {
+	Name string                                         `json:"name"`
+	Spec osmv1alpha1.OperatingSystemProfileFragmentSpec `json:"spec"`
+}
+
+// ApplyOperatingSystemProfileFragments merges the fragments in the namespace of the OSP that select the OSP or the machine
+// deployment into the OSP. Fragments are merged in the order of their names. It returns the OSP with the fragments merged
+// and a hash over the merged fragments, which is empty if no fragment matched.
+func ApplyOperatingSystemProfileFragments(ctx context.Context, client ctrlruntimeclient.Client, osp *osmv1alpha1.OperatingSystemProfile, md *clusterv1alpha1.MachineDeployment) (*osmv1alpha1.OperatingSystemProfile, string, error) {
+	fragments := &osmv1alpha1.OperatingSystemProfileFragmentList{}
+	if err := client.List(ctx, fragments, ctrlruntimeclient.InNamespace(osp.Namespace)); err != nil {
+		return nil, "", fmt.Errorf("failed to list OperatingSystemProfileFragments in namespace %q: %w", osp.Namespace, err)
+	}
+
+	sort.Slice(fragments.Items, func(i, j int) bool {
+		return fragments.Items[i].Name < fragments.Items[j].Name
+	})
+
+	result := osp.DeepCopy()
+	var applied []appliedFragment
+	for _, fragment := range fragments.Items {
+		matches, err := FragmentMatches(&fragment, osp, md)
+		if err != nil {
+			return nil, "", err
+		}
+		if !matches {
+			continue
+		}
+
+		// Units are only supported by Flatcar, which is enforced for OSPs by the admission webhook.
+		if osp.Spec.OSName != osmv1alpha1.OperatingSystemFlatcar && fragmentHasUnits(&fragment) {
+			return nil, "", fmt.Errorf("OperatingSystemProfileFragment %q declares units, which are not supported for %s", fragment.Name, osp.Spec.OSName)
+		}
+
+		if fragment.Spec.BootstrapConfig != nil {
+			mergeFragmentConfig(&result.Spec.BootstrapConfig, fragment.Spec.BootstrapConfig.DeepCopy())
+		}
+		if fragment.Spec.ProvisioningConfig != nil {
+			mergeFragmentConfig(&result.Spec.ProvisioningConfig, fragment.Spec.ProvisioningConfig.DeepCopy())
+		}
+
+		applied = append(applied, appliedFragment{Name: fragment.Name, Spec: fragment.Spec})
+	}
+
+	if len(applied) == 0 {
+		return osp, "", nil
+	}
+
+	hash, err := HashJSON(applied)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to json encode OperatingSystemProfileFragments: %w", err)
+	}
+
+	return result, hash, nil
+}
+
+// FragmentMatches checks if all selectors of the fragment match. A fragment without selectors doesn't match anything.
+func FragmentMatches(fragment *osmv1alpha1.OperatingSystemProfileFragment, osp *osmv1alpha1.OperatingSystemProfile, md *clusterv1alpha1.MachineDeployment) (bool, error) {
+	if fragment.Spec.OperatingSystemProfileSelector == nil && fragment.Spec.MachineDeploymentSelector == nil {
+		return false, nil
+	}
+
+	for _, candidate := range []struct {
+		selector *metav1.LabelSelector
+		labels   map[string]string
+	}{
+		{selector: fragment.Spec.OperatingSystemProfileSelector, labels: osp.Labels},
+		{selector: fragment.Spec.MachineDeploymentSelector, labels: md.Labels},
+	} {
+		if candidate.selector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(candidate.selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid selector in OperatingSystemProfileFragment %q: %w", fragment.Name, err)
+		}
+
+		if !selector.Matches(labels.Set(candidate.labels)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func fragmentHasUnits(fragment *osmv1alpha1.OperatingSystemProfileFragment) bool {
+	for _, config := range []*osmv1alpha1.OSPFragmentConfig{fragment.Spec.BootstrapConfig, fragment.Spec.ProvisioningConfig} {
+		if config != nil && len(config.Units) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeFragmentConfig(config *osmv1alpha1.OSPConfig, fragment *osmv1alpha1.OSPFragmentConfig) {
+	config.Files = overlayByKey(config.Files, fragment.Files, nil, func(file osmv1alpha1.File) string { return file.Path })
+	config.Units = overlayByKey(config.Units, fragment.Units, nil, func(unit osmv1alpha1.Unit) string { return unit.Name })
+
+	for name, template := range fragment.Templates {
+		if config.Templates == nil {
+			config.Templates = map[string]string{}
+		}
+		config.Templates[name] = template
+	}
+
+	config.CloudInitModules = mergeCloudInitModules(config.CloudInitModules, fragment.CloudInitModules)
+}
+
+// mergeCloudInitModules appends the commands, mounts and files of the fragment modules, adds or replaces its
+// repositories and subscription settings and replaces the other modules that are set.
+func mergeCloudInitModules(modules, fragment *osmv1alpha1.CloudInitModule) *osmv1alpha1.CloudInitModule {
+	if fragment == nil {
+		return modules
+	}
+	if modules == nil {
+		modules = &osmv1alpha1.CloudInitModule{}
+	}
+
+	modules.BootCMD = append(modules.BootCMD, fragment.BootCMD...)
+	modules.RunCMD = append(modules.RunCMD, fragment.RunCMD...)
+
+	for key, value := range fragment.RHSubscription {
+		if modules.RHSubscription == nil {
+			modules.RHSubscription = map[string]string{}
+		}
+		modules.RHSubscription[key] = value
+	}
+
+	for name, repo := range fragment.YumRepos {
+		if modules.YumRepos == nil {
+			modules.YumRepos = map[string]map[string]string{}
+		}
+		modules.YumRepos[name] = repo
+	}
+
+	if fragment.YumRepoDir != "" {
+		modules.YumRepoDir = fragment.YumRepoDir
+	}
+
+	if fragment.Timezone != "" {
+		modules.Timezone = fragment.Timezone
+	}
+	if fragment.Apt != nil {
+		modules.Apt = fragment.Apt
+	}
+	if fragment.NTP != nil {
+		modules.NTP = fragment.NTP
+	}
+	if fragment.CACerts != nil {
+		modules.CACerts = fragment.CACerts
+	}
+	if fragment.PowerState != nil {
+		modules.PowerState = fragment.PowerState
+	}
+	if fragment.PhoneHome != nil {
+		modules.PhoneHome = fragment.PhoneHome
+	}
+
+	modules.Mounts = append(modules.Mounts, fragment.Mounts...)
+	modules.WriteFiles = append(modules.WriteFiles, fragment.WriteFiles...)
+
+	return modules
+}
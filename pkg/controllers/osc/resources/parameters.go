@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MachineDeploymentParametersAnnotation sets the values of the OSP parameters, e.g. "proxyPort=3128,debug=true".
+	MachineDeploymentParametersAnnotation = "k8c.io/osp-parameters"
+	// MachineDeploymentParametersConfigMapAnnotation is the name of a config map in the namespace of the machine
+	// deployment whose data sets the values of the OSP parameters. Values of the annotation take precedence.
+	MachineDeploymentParametersConfigMapAnnotation = "k8c.io/osp-parameters-configmap"
+)
+
+// FetchParameterValues returns the values that the machine deployment sets for the OSP parameters and a hash over
+// them, which is empty if no values are set.
+func FetchParameterValues(ctx context.Context, client ctrlruntimeclient.Reader, md *v1alpha1.MachineDeployment) (map[string]string, string, error) {
+	values := map[string]string{}
+
+	if name := md.Annotations[MachineDeploymentParametersConfigMapAnnotation]; name != "" {
+		configMap := &corev1.ConfigMap{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: md.Namespace, Name: name}, configMap); err != nil {
+			return nil, "", fmt.Errorf("failed to get parameters config map %s/%s: %w", md.Namespace, name, err)
+		}
+		for key, value := range configMap.Data {
+			values[key] = value
+		}
+	}
+
+	if val, ok := md.Annotations[MachineDeploymentParametersAnnotation]; ok {
+		if parameters := getKeyValueMap(val, "="); parameters != nil {
+			for key, value := range *parameters {
+				values[key] = value
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return values, "", nil
+	}
+
+	hash, err := HashJSON(values)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to json encode parameter values: %w", err)
+	}
+
+	return values, hash, nil
+}
+
+// ValidateParameters ensures that the parameters of an OSP have unique names, and that their defaults match their type.
+func ValidateParameters(parameters []osmv1alpha1.Parameter) error {
+	names := map[string]struct{}{}
+	for _, parameter := range parameters {
+		if _, ok := names[parameter.Name]; ok {
+			return fmt.Errorf("parameter %q is declared more than once", parameter.Name)
+		}
+		names[parameter.Name] = struct{}{}
+
+		if !isParameterType(parameter.Type) {
+			return fmt.Errorf("parameter %q has unknown type %q", parameter.Name, parameter.Type)
+		}
+
+		if parameter.Default != nil {
+			if _, err := parameterDefault(parameter); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ResolveParameters resolves the values of the OSP parameters from the values set by the machine deployment and the
+// defaults of the parameters. The values are converted to the types of the parameters.
+func ResolveParameters(parameters []osmv1alpha1.Parameter, values map[string]string) (map[string]interface{}, error) {
+	declared := map[string]struct{}{}
+	for _, parameter := range parameters {
+		declared[parameter.Name] = struct{}{}
+	}
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			return nil, fmt.Errorf("parameter %q is not declared by the OperatingSystemProfile", name)
+		}
+	}
+
+	resolved := map[string]interface{}{}
+	for _, parameter := range parameters {
+		if value, ok := values[parameter.Name]; ok {
+			parsed, err := parseParameterValue(parameter.Type, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of parameter %q: %w", parameter.Name, err)
+			}
+			resolved[parameter.Name] = parsed
+			continue
+		}
+
+		if parameter.Default != nil {
+			value, err := parameterDefault(parameter)
+			if err != nil {
+				return nil, err
+			}
+			resolved[parameter.Name] = value
+			continue
+		}
+
+		if parameter.Required {
+			return nil, fmt.Errorf("required parameter %q is not set", parameter.Name)
+		}
+	}
+
+	return resolved, nil
+}
+
+func isParameterType(parameterType osmv1alpha1.ParameterType) bool {
+	switch parameterType {
+	case osmv1alpha1.ParameterTypeString, osmv1alpha1.ParameterTypeInteger, osmv1alpha1.ParameterTypeNumber, osmv1alpha1.ParameterTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+func parseParameterValue(parameterType osmv1alpha1.ParameterType, value string) (interface{}, error) {
+	switch parameterType {
+	case osmv1alpha1.ParameterTypeString:
+		return value, nil
+	case osmv1alpha1.ParameterTypeInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case osmv1alpha1.ParameterTypeNumber:
+		return strconv.ParseFloat(value, 64)
+	case osmv1alpha1.ParameterTypeBoolean:
+		return strconv.ParseBool(value)
+	default:
+		return nil, fmt.Errorf("unknown type %q", parameterType)
+	}
+}
+
+// parameterDefault decodes the default of the parameter and ensures that it's of the type of the parameter.
+func parameterDefault(parameter osmv1alpha1.Parameter) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(parameter.Default.Raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode default of parameter %q: %w", parameter.Name, err)
+	}
+
+	var (
+		resolved interface{}
+		ok       bool
+	)
+	switch parameter.Type {
+	case osmv1alpha1.ParameterTypeString:
+		resolved, ok = value.(string)
+	case osmv1alpha1.ParameterTypeBoolean:
+		resolved, ok = value.(bool)
+	case osmv1alpha1.ParameterTypeInteger:
+		if number, isNumber := value.(json.Number); isNumber {
+			integer, err := number.Int64()
+			resolved, ok = integer, err == nil
+		}
+	case osmv1alpha1.ParameterTypeNumber:
+		if number, isNumber := value.(json.Number); isNumber {
+			float, err := number.Float64()
+			resolved, ok = float, err == nil
+		}
+	default:
+		return nil, fmt.Errorf("parameter %q has unknown type %q", parameter.Name, parameter.Type)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("default %s of parameter %q is not of type %s", parameter.Default.Raw, parameter.Name, parameter.Type)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/operating-system-manager/pkg/containerruntime"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MachineDeploymentRegistryMirrorsAnnotation sets the registry mirrors of the machine deployment in the format of
+	// the -node-registry-mirrors flag, e.g. "docker.io=mirror.example.com,quay.io=https://quay.mirror.example.com". They
+	// replace the mirrors that are configured for OSM for the same registries.
+	MachineDeploymentRegistryMirrorsAnnotation = "k8c.io/registry-mirrors"
+	// MachineDeploymentInsecureRegistriesAnnotation is a comma separated list of registries that are insecure for the
+	// machine deployment, in addition to the insecure registries that are configured for OSM.
+	MachineDeploymentInsecureRegistriesAnnotation = "k8c.io/insecure-registries"
+	// MachineDeploymentRegistryConfigMapAnnotation is the name of a config map in the namespace of the machine
+	// deployment with the registryMirrors and insecureRegistries keys, in the format of the annotations. Values of the
+	// annotations take precedence.
+	MachineDeploymentRegistryConfigMapAnnotation = "k8c.io/registry-configmap"
+	// MachineDeploymentRegistryCredentialsSecretAnnotation is the name of a secret in the namespace of the machine
+	// deployment with registry credentials, in the format of the -node-registry-credentials-secret secret. They take
+	// precedence over the credentials that are configured for OSM for the same registries.
+	MachineDeploymentRegistryCredentialsSecretAnnotation = "k8c.io/registry-credentials-secret"
+
+	registryMirrorsConfigMapKey    = "registryMirrors"
+	insecureRegistriesConfigMapKey = "insecureRegistries"
+)
+
+// RegistryOverrides are the registry settings of a machine deployment, they're merged into the registry settings that
+// are configured for OSM.
+type RegistryOverrides struct {
+	RegistryMirrors     map[string][]string                    `json:",omitempty"`
+	InsecureRegistries  []string                               `json:",omitempty"`
+	RegistryCredentials map[string]containerruntime.AuthConfig `json:",omitempty"`
+}
+
+// Apply returns a copy of the container runtime config with the registry overrides.
+func (o RegistryOverrides) Apply(config containerruntime.Config) containerruntime.Config {
+	return config.WithRegistryOverrides(o.RegistryMirrors, o.InsecureRegistries, o.RegistryCredentials)
+}
+
+// ParseRegistryOverrides parses the registry mirrors and insecure registries that the annotations of the machine
+// deployment set.
+func ParseRegistryOverrides(md *v1alpha1.MachineDeployment) (RegistryOverrides, error) {
+	registryMirrors, err := containerruntime.ParseRegistryMirrors(md.Annotations[MachineDeploymentRegistryMirrorsAnnotation])
+	if err != nil {
+		return RegistryOverrides{}, fmt.Errorf("invalid %s annotation: %w", MachineDeploymentRegistryMirrorsAnnotation, err)
+	}
+
+	return RegistryOverrides{
+		RegistryMirrors:    registryMirrors,
+		InsecureRegistries: containerruntime.ParseInsecureRegistries(md.Annotations[MachineDeploymentInsecureRegistriesAnnotation]),
+	}, nil
+}
+
+// FetchRegistryOverrides returns the registry settings of the machine deployment, including the ones of the referenced
+// config map and secret, and a hash over them, which is empty if the machine deployment has no registry settings.
+func FetchRegistryOverrides(ctx context.Context, client ctrlruntimeclient.Client, md *v1alpha1.MachineDeployment) (RegistryOverrides, string, error) {
+	overrides := RegistryOverrides{}
+
+	if name := md.Annotations[MachineDeploymentRegistryConfigMapAnnotation]; name != "" {
+		configMap := &corev1.ConfigMap{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: md.Namespace, Name: name}, configMap); err != nil {
+			return RegistryOverrides{}, "", fmt.Errorf("failed to get registry config map %s/%s: %w", md.Namespace, name, err)
+		}
+
+		registryMirrors, err := containerruntime.ParseRegistryMirrors(configMap.Data[registryMirrorsConfigMapKey])
+		if err != nil {
+			return RegistryOverrides{}, "", fmt.Errorf("invalid %s of registry config map %s/%s: %w", registryMirrorsConfigMapKey, md.Namespace, name, err)
+		}
+		overrides.RegistryMirrors = registryMirrors
+		overrides.InsecureRegistries = containerruntime.ParseInsecureRegistries(configMap.Data[insecureRegistriesConfigMapKey])
+	}
+
+	annotationOverrides, err := ParseRegistryOverrides(md)
+	if err != nil {
+		return RegistryOverrides{}, "", err
+	}
+	for registry, mirrors := range annotationOverrides.RegistryMirrors {
+		if overrides.RegistryMirrors == nil {
+			overrides.RegistryMirrors = map[string][]string{}
+		}
+		overrides.RegistryMirrors[registry] = mirrors
+	}
+	overrides.InsecureRegistries = append(overrides.InsecureRegistries, annotationOverrides.InsecureRegistries...)
+
+	if name := md.Annotations[MachineDeploymentRegistryCredentialsSecretAnnotation]; name != "" {
+		registryCredentials, err := containerruntime.GetContainerdAuthConfig(ctx, client, fmt.Sprintf("%s/%s", md.Namespace, name))
+		if err != nil {
+			return RegistryOverrides{}, "", err
+		}
+		overrides.RegistryCredentials = registryCredentials
+	}
+
+	if len(overrides.RegistryMirrors) == 0 && len(overrides.InsecureRegistries) == 0 && len(overrides.RegistryCredentials) == 0 {
+		return RegistryOverrides{}, "", nil
+	}
+
+	hash, err := HashJSON(overrides)
+	if err != nil {
+		return RegistryOverrides{}, "", fmt.Errorf("failed to json encode registry overrides: %w", err)
+	}
+
+	return overrides, hash, nil
+}
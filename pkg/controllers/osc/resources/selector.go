@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// selectorTarget describes the machines of a machine deployment that the selectors of files and units are matched
+// against.
+type selectorTarget struct {
+	cloudProvider  osmv1alpha1.CloudProvider
+	osVersion      string
+	kubeletVersion string
+	architecture   string
+	labels         map[string]string
+}
+
+// newSelectorTarget returns the target for the machines of the machine deployment. Their architecture is taken from the
+// kubernetes.io/arch label of the machine template or of its machine spec, it's unknown if neither is set.
+func newSelectorTarget(md *v1alpha1.MachineDeployment, osp *osmv1alpha1.OperatingSystemProfile, cloudProvider osmv1alpha1.CloudProvider) selectorTarget {
+	architecture := md.Spec.Template.Labels[corev1.LabelArchStable]
+	if architecture == "" {
+		architecture = md.Spec.Template.Spec.Labels[corev1.LabelArchStable]
+	}
+
+	return selectorTarget{
+		cloudProvider:  cloudProvider,
+		osVersion:      osp.Spec.OSVersion,
+		kubeletVersion: md.Spec.Template.Spec.Versions.Kubelet,
+		architecture:   architecture,
+		labels:         md.Labels,
+	}
+}
+
+// matches checks if the target matches all conditions of the selector. A nil selector matches all targets.
+func (t selectorTarget) matches(selector *osmv1alpha1.Selector) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	if len(selector.CloudProviders) > 0 && !slices.Contains(selector.CloudProviders, t.cloudProvider) {
+		return false, nil
+	}
+
+	if len(selector.Architectures) > 0 {
+		// Guessing the architecture would silently select the files of another architecture.
+		if t.architecture == "" {
+			return false, fmt.Errorf("architecture selector requires the %s label on the machine template", corev1.LabelArchStable)
+		}
+		if !slices.Contains(selector.Architectures, t.architecture) {
+			return false, nil
+		}
+	}
+
+	for _, constraint := range []struct {
+		name       string
+		constraint string
+		version    string
+	}{
+		{name: "operating system", constraint: selector.OSVersions, version: t.osVersion},
+		{name: "kubernetes", constraint: selector.KubernetesVersions, version: t.kubeletVersion},
+	} {
+		if constraint.constraint == "" {
+			continue
+		}
+
+		matches, err := versionMatches(constraint.constraint, constraint.version)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s version selector: %w", constraint.name, err)
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+
+	if selector.MachineDeploymentSelector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(selector.MachineDeploymentSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid machine deployment selector: %w", err)
+		}
+		if !labelSelector.Matches(labels.Set(t.labels)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func versionMatches(constraint, version string) (bool, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse constraint %q: %w", constraint, err)
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse version %q: %w", version, err)
+	}
+
+	return c.Check(v), nil
+}
+
+// selectFiles returns the files whose selector matches the target. The selectors are removed from the returned files,
+// since they're not relevant for the rendered config.
+func selectFiles(files []osmv1alpha1.File, target selectorTarget) ([]osmv1alpha1.File, error) {
+	var selected []osmv1alpha1.File
+	for _, file := range files {
+		matches, err := target.matches(file.When)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match file %q: %w", file.Path, err)
+		}
+		if matches {
+			file.When = nil
+			selected = append(selected, file)
+		}
+	}
+	return selected, nil
+}
+
+// selectUnits returns the units whose selector matches the target. The selectors are removed from the returned units,
+// since they're not relevant for the rendered config.
+func selectUnits(units []osmv1alpha1.Unit, target selectorTarget) ([]osmv1alpha1.Unit, error) {
+	var selected []osmv1alpha1.Unit
+	for _, unit := range units {
+		matches, err := target.matches(unit.When)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match unit %q: %w", unit.Name, err)
+		}
+		if matches {
+			unit.When = nil
+			selected = append(selected, unit)
+		}
+	}
+	return selected, nil
+}
+
+// selectOSCConfig drops the units and files of the config whose selector doesn't match the target.
+func selectOSCConfig(config *osmv1alpha1.OSCConfig, target selectorTarget) error {
+	units, err := selectUnits(config.Units, target)
+	if err != nil {
+		return err
+	}
+
+	files, err := selectFiles(config.Files, target)
+	if err != nil {
+		return err
+	}
+
+	config.Units = units
+	config.Files = files
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ImportsTemplateLibrary returns true if the bootstrap or provisioning config of the OSP imports the library.
+func ImportsTemplateLibrary(osp *osmv1alpha1.OperatingSystemProfile, library *osmv1alpha1.TemplateLibrary) bool {
+	if library.Namespace != osp.Namespace {
+		return false
+	}
+	return slices.Contains(osp.Spec.BootstrapConfig.TemplateLibraries, library.Name) || slices.Contains(osp.Spec.ProvisioningConfig.TemplateLibraries, library.Name)
+}
+
+// ImportTemplateLibraries adds the templates of the TemplateLibraries that the configs of the OSP import to the
+// templates of the configs. Templates of the configs take precedence over the ones of the libraries. It returns the OSP
+// with the templates imported and the versions of the imported libraries, which are empty if no library is imported.
+// Each version includes a hash over the templates of the library, so that changing the templates without bumping the
+// version still rotates the OperatingSystemConfigs.
+func ImportTemplateLibraries(ctx context.Context, client ctrlruntimeclient.Reader, osp *osmv1alpha1.OperatingSystemProfile) (*osmv1alpha1.OperatingSystemProfile, string, error) {
+	if len(osp.Spec.BootstrapConfig.TemplateLibraries) == 0 && len(osp.Spec.ProvisioningConfig.TemplateLibraries) == 0 {
+		return osp, "", nil
+	}
+
+	result := osp.DeepCopy()
+	libraries := map[string]*osmv1alpha1.TemplateLibrary{}
+	for _, config := range []*osmv1alpha1.OSPConfig{&result.Spec.BootstrapConfig, &result.Spec.ProvisioningConfig} {
+		templates := map[string]string{}
+		for _, name := range config.TemplateLibraries {
+			library, ok := libraries[name]
+			if !ok {
+				library = &osmv1alpha1.TemplateLibrary{}
+				if err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: osp.Namespace}, library); err != nil {
+					if kerrors.IsNotFound(err) {
+						return nil, "", fmt.Errorf("TemplateLibrary %q imported by OperatingSystemProfile %q not found", name, osp.Name)
+					}
+					return nil, "", fmt.Errorf("failed to get TemplateLibrary %q from namespace %q: %w", name, osp.Namespace, err)
+				}
+				libraries[name] = library
+			}
+
+			for templateName, template := range library.Spec.Templates {
+				templates[templateName] = template
+			}
+		}
+
+		for templateName, template := range config.Templates {
+			templates[templateName] = template
+		}
+		config.Templates = templates
+	}
+
+	versions := make([]string, 0, len(libraries))
+	for name, library := range libraries {
+		hash, err := HashJSON(library.Spec.Templates)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to json encode templates of TemplateLibrary %q: %w", name, err)
+		}
+		versions = append(versions, fmt.Sprintf("%s=%s@%s", name, library.Spec.Version, hash))
+	}
+	sort.Strings(versions)
+
+	return result, strings.Join(versions, ","), nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// machineDeploymentView is the view of the machine deployment that is available to templates as .MachineDeployment.
+// It's rendered once per OSC, changes of the labels rotate the OSC if the OSP uses them while changes of the replicas
+// don't.
+type machineDeploymentView struct {
+	Name        string
+	Namespace   string
+	Labels      map[string]string
+	Annotations map[string]string
+	Replicas    int32
+	// MachineLabels are the labels of the machines of the machine deployment.
+	MachineLabels map[string]string
+}
+
+// providerSpecView is the view of the provider spec of the machine deployment that is available to templates as
+// .ProviderSpec. The cloud provider and operating system specs are decoded as they are, values that reference secrets
+// or config maps aren't resolved. Since the specs can contain credentials, templates should only render the fields
+// they need.
+type providerSpecView struct {
+	CloudProvider       string
+	CloudProviderSpec   map[string]interface{}
+	OperatingSystem     string
+	OperatingSystemSpec map[string]interface{}
+}
+
+// newMachineDeploymentView copies the metadata of the machine deployment, so that templates can't modify it.
+func newMachineDeploymentView(md *v1alpha1.MachineDeployment) machineDeploymentView {
+	view := machineDeploymentView{
+		Name:          md.Name,
+		Namespace:     md.Namespace,
+		Labels:        maps.Clone(md.Labels),
+		Annotations:   maps.Clone(md.Annotations),
+		MachineLabels: maps.Clone(md.Spec.Template.Labels),
+	}
+
+	if md.Spec.Replicas != nil {
+		view.Replicas = *md.Spec.Replicas
+	}
+
+	return view
+}
+
+func newProviderSpecView(providerConfig providerconfig.Config) (providerSpecView, error) {
+	cloudProviderSpec, err := decodeRawSpec(providerConfig.CloudProviderSpec)
+	if err != nil {
+		return providerSpecView{}, fmt.Errorf("failed to decode cloud provider spec: %w", err)
+	}
+
+	operatingSystemSpec, err := decodeRawSpec(providerConfig.OperatingSystemSpec)
+	if err != nil {
+		return providerSpecView{}, fmt.Errorf("failed to decode operating system spec: %w", err)
+	}
+
+	return providerSpecView{
+		CloudProvider:       string(providerConfig.CloudProvider),
+		CloudProviderSpec:   cloudProviderSpec,
+		OperatingSystem:     string(providerConfig.OperatingSystem),
+		OperatingSystemSpec: operatingSystemSpec,
+	}, nil
+}
+
+func decodeRawSpec(spec runtime.RawExtension) (map[string]interface{}, error) {
+	decoded := map[string]interface{}{}
+	if len(spec.Raw) == 0 {
+		return decoded, nil
+	}
+
+	if err := json.Unmarshal(spec.Raw, &decoded); err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// UsesMachineDeploymentLabels returns true if the rendered config of the OSP depends on the labels of the machine
+// deployment, either because files or units select machine deployments by their labels or because templates reference
+// the labels. Templates are checked for ".Labels", which also matches labels that are accessed through a variable.
+func UsesMachineDeploymentLabels(osp *osmv1alpha1.OperatingSystemProfile) bool {
+	return configUsesMachineDeploymentLabels(osp.Spec.BootstrapConfig) || configUsesMachineDeploymentLabels(osp.Spec.ProvisioningConfig)
+}
+
+func configUsesMachineDeploymentLabels(config osmv1alpha1.OSPConfig) bool {
+	files := slices.Clone(config.Files)
+	templates := slices.Collect(maps.Values(config.Templates))
+	for _, cr := range config.SupportedContainerRuntimes {
+		files = append(files, cr.Files...)
+		templates = slices.AppendSeq(templates, maps.Values(cr.Templates))
+	}
+
+	for _, unit := range config.Units {
+		if unit.When != nil && unit.When.MachineDeploymentSelector != nil {
+			return true
+		}
+	}
+
+	for _, file := range files {
+		if file.When != nil && file.When.MachineDeploymentSelector != nil {
+			return true
+		}
+		if file.Content.Inline != nil && referencesLabels(file.Content.Inline.Data) {
+			return true
+		}
+	}
+
+	for _, tmpl := range templates {
+		if referencesLabels(tmpl) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func referencesLabels(template string) bool {
+	return strings.Contains(template, ".Labels")
+}
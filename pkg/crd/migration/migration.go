@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	osmv1beta1 "k8c.io/operating-system-manager/pkg/crd/osm/v1beta1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const retryInterval = 30 * time.Second
+
+// resource is a custom resource whose objects are migrated to the storage version.
+type resource struct {
+	crdName string
+	newList func() ctrlruntimeclient.ObjectList
+}
+
+var resources = []resource{
+	{
+		crdName: osmv1beta1.OperatingSystemProfileResourceName + "." + osmv1beta1.GroupName,
+		newList: func() ctrlruntimeclient.ObjectList { return &osmv1beta1.OperatingSystemProfileList{} },
+	},
+	{
+		crdName: osmv1beta1.OperatingSystemConfigResourceName + "." + osmv1beta1.GroupName,
+		newList: func() ctrlruntimeclient.ObjectList { return &osmv1beta1.OperatingSystemConfigList{} },
+	},
+	{
+		crdName: osmv1beta1.OperatingSystemProfileFragmentResourceName + "." + osmv1beta1.GroupName,
+		newList: func() ctrlruntimeclient.ObjectList { return &osmv1beta1.OperatingSystemProfileFragmentList{} },
+	},
+	{
+		crdName: osmv1beta1.OperatingSystemConfigOverrideResourceName + "." + osmv1beta1.GroupName,
+		newList: func() ctrlruntimeclient.ObjectList { return &osmv1beta1.OperatingSystemConfigOverrideList{} },
+	},
+	{
+		crdName: osmv1beta1.TemplateLibraryResourceName + "." + osmv1beta1.GroupName,
+		newList: func() ctrlruntimeclient.ObjectList { return &osmv1beta1.TemplateLibraryList{} },
+	},
+}
+
+// Add adds a runnable to the manager that migrates all OperatingSystemProfiles, OperatingSystemConfigs,
+// OperatingSystemProfileFragments, OperatingSystemConfigOverrides and TemplateLibraries to the storage version v1beta1.
+// Once all objects have been rewritten, v1alpha1 is removed from the stored versions of the CRDs so that it can be
+// dropped in the future. The migration is retried until it succeeds.
+func Add(mgr manager.Manager, log *zap.SugaredLogger) error {
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		err := wait.PollUntilContextCancel(ctx, retryInterval, true, func(ctx context.Context) (bool, error) {
+			if err := migrate(ctx, mgr.GetAPIReader(), mgr.GetClient()); err != nil {
+				log.Errorw("Failed to migrate resources to storage version, retrying", zap.Error(err))
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			// The manager is shutting down, the migration is resumed on the next start.
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		log.Infow("Migrated resources to storage version", "version", osmv1beta1.GroupVersion)
+		return nil
+	}))
+}
+
+func migrate(ctx context.Context, reader ctrlruntimeclient.Reader, client ctrlruntimeclient.Client) error {
+	for _, res := range resources {
+		if err := migrateResource(ctx, reader, client, res); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", res.crdName, err)
+		}
+	}
+	return nil
+}
+
+func migrateResource(ctx context.Context, reader ctrlruntimeclient.Reader, client ctrlruntimeclient.Client, res resource) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: res.crdName}, crd); err != nil {
+		return fmt.Errorf("failed to get CustomResourceDefinition: %w", err)
+	}
+
+	if isMigrated(crd) {
+		return nil
+	}
+
+	// The API server always persists an object in the storage version, rewriting the objects without any changes is
+	// sufficient to migrate them.
+	list := res.newList()
+	if err := reader.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	objects, err := meta.ExtractList(list)
+	if err != nil {
+		return fmt.Errorf("failed to extract objects from list: %w", err)
+	}
+
+	for _, item := range objects {
+		obj, ok := item.(ctrlruntimeclient.Object)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", item)
+		}
+
+		if err := client.Update(ctx, obj); err != nil {
+			if kerrors.IsNotFound(err) || kerrors.IsConflict(err) {
+				// A deleted object doesn't need to be migrated and a conflicting update has already rewritten the object.
+				continue
+			}
+			return fmt.Errorf("failed to update %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := reader.Get(ctx, types.NamespacedName{Name: res.crdName}, crd); err != nil {
+			return err
+		}
+
+		crd.Status.StoredVersions = []string{osmv1beta1.GroupVersion}
+		return client.Status().Update(ctx, crd)
+	})
+}
+
+func isMigrated(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == osmv1beta1.GroupVersion
+}
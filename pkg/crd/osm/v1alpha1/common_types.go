@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -59,12 +60,13 @@ const (
 )
 
 // ContainerRuntime represents supported container runtime
-// +kubebuilder:validation:Enum=docker;containerd
+// +kubebuilder:validation:Enum=docker;containerd;crio
 type ContainerRuntime string
 
 const (
 	ContainerRuntimeDocker     ContainerRuntime = "docker"
 	ContainerRuntimeContainerd ContainerRuntime = "containerd"
+	ContainerRuntimeCRIO       ContainerRuntime = "crio"
 )
 
 // ProvisioningUtility used to provision the machines
@@ -97,6 +99,31 @@ type Unit struct {
 	Content *string `json:"content,omitempty"`
 	// DropIns is a list of drop_ins for this unit.
 	DropIns []DropIn `json:"dropIns,omitempty"`
+	// When restricts the unit to the machines that match the selector.
+	// +optional
+	When *Selector `json:"when,omitempty"`
+}
+
+// Selector restricts a file or unit to the machines that match all of its conditions. Conditions that are left empty
+// match all machines.
+type Selector struct {
+	// CloudProviders are the cloud providers of the machines.
+	// +optional
+	CloudProviders []CloudProvider `json:"cloudProviders,omitempty"`
+	// OSVersions is a semver constraint on the operating system version of the profile, e.g. ">= 22.04".
+	// +optional
+	OSVersions string `json:"osVersions,omitempty"`
+	// KubernetesVersions is a semver constraint on the kubelet version of the machines, e.g. ">= 1.30, < 1.33".
+	// +optional
+	KubernetesVersions string `json:"kubernetesVersions,omitempty"`
+	// Architectures are the CPU architectures of the machines, e.g. amd64 or arm64. The architecture is taken from
+	// the kubernetes.io/arch label of the machine template or of its machine spec. The label is required by this
+	// selector, rendering fails for machine deployments without it.
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+	// MachineDeploymentSelector selects the machine deployments by their labels.
+	// +optional
+	MachineDeploymentSelector *metav1.LabelSelector `json:"machineDeploymentSelector,omitempty"`
 }
 
 // DropIn is a drop-in configuration for a systemd unit.
@@ -107,8 +134,40 @@ type DropIn struct {
 	Content string `json:"content"`
 }
 
+// User is an operating system user that is created on the instance.
+type User struct {
+	// Name is the name of the user.
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_-]*$`
+	Name string `json:"name"`
+	// UID is the user ID of the user. The next free ID is used if it's not set.
+	// +optional
+	UID *int64 `json:"uid,omitempty"`
+	// Groups are the supplementary groups of the user.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// Shell is the login shell of the user.
+	// +optional
+	Shell string `json:"shell,omitempty"`
+	// Sudo is a list of sudo rules for the user, e.g. "ALL=(ALL) NOPASSWD:ALL".
+	// +optional
+	Sudo []string `json:"sudo,omitempty"`
+	// SSHAuthorizedKeys is a list of ssh keys that are authorized to log in as the user.
+	// +optional
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+}
+
+// Group is an operating system group that is created on the instance.
+type Group struct {
+	// Name is the name of the group.
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_-]*$`
+	Name string `json:"name"`
+	// GID is the group ID of the group. It's only supported by ignition, cloud-init always uses the next free ID.
+	// +optional
+	GID *int64 `json:"gid,omitempty"`
+}
+
 // File is a file that should get written to the host's file system. The content can either be inlined or
-// referenced from a secret in the same namespace.
+// referenced from a secret or a config map.
 type File struct {
 	// Path is the path of the file system where the file should get written to.
 	Path string `json:"path"`
@@ -118,6 +177,228 @@ type File struct {
 	Permissions int32 `json:"permissions,omitempty"`
 	// Content describe the file's content.
 	Content FileContent `json:"content"`
+	// Owner is the name of the user that owns the file. Defaults to root.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Group is the name of the group that owns the file. Defaults to root.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// WritePolicy defines how the content is written if the file already exists. Defaults to Overwrite.
+	// +optional
+	WritePolicy FileWritePolicy `json:"writePolicy,omitempty"`
+	// When restricts the file to the machines that match the selector. The file is only rendered for those machines.
+	// +optional
+	When *Selector `json:"when,omitempty"`
+}
+
+// FileWritePolicy defines how the content of a file is written if the file already exists.
+// +kubebuilder:validation:Enum=Overwrite;Append
+type FileWritePolicy string
+
+const (
+	// FileWritePolicyOverwrite replaces the content of an existing file.
+	FileWritePolicyOverwrite FileWritePolicy = "Overwrite"
+	// FileWritePolicyAppend appends the content to an existing file.
+	FileWritePolicyAppend FileWritePolicy = "Append"
+)
+
+// Directory is a directory that should get created on the host's file system.
+type Directory struct {
+	// Path is the path of the directory. Missing parent directories are created as well.
+	Path string `json:"path"`
+	// Permissions describes with which permissions the directory should get created.
+	// Should be in decimal base and without any leading zeroes.
+	// +kubebuilder:default=755
+	Permissions int32 `json:"permissions,omitempty"`
+	// Owner is the name of the user that owns the directory. Defaults to root.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Group is the name of the group that owns the directory. Defaults to root.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// Link is a link that should get created on the host's file system.
+type Link struct {
+	// Path is the path of the link.
+	Path string `json:"path"`
+	// Target is the path that the link points to.
+	Target string `json:"target"`
+	// Hard creates a hard link instead of a symbolic link.
+	// +optional
+	Hard bool `json:"hard,omitempty"`
+	// Owner is the name of the user that owns the link. Defaults to root.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Group is the name of the group that owns the link. Defaults to root.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// Packages describes the package repositories and packages of the instance. They're rendered for the package manager
+// of the operating system: apt on Ubuntu, yum/dnf on RHEL, Rocky Linux and Amazon Linux, and system extensions on Flatcar.
+type Packages struct {
+	// Repositories are added to the package manager before the packages are installed.
+	// +optional
+	Repositories []PackageRepository `json:"repositories,omitempty"`
+	// Packages are installed on the instance.
+	// +optional
+	Packages []Package `json:"packages,omitempty"`
+}
+
+// PackageRepository is a package repository. Apt uses the URL, distribution and components for the source, yum/dnf
+// uses the URL as base URL and Flatcar downloads system extension images from the URL.
+type PackageRepository struct {
+	// Name of the repository.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`
+	Name string `json:"name"`
+	// URL of the repository.
+	URL string `json:"url"`
+	// Distribution of an apt repository, e.g. jammy.
+	// +optional
+	Distribution string `json:"distribution,omitempty"`
+	// Components of an apt repository, e.g. main.
+	// +optional
+	Components []string `json:"components,omitempty"`
+	// GPGKeyURL is the URL of the key that the repository is signed with.
+	// +optional
+	GPGKeyURL string `json:"gpgKeyURL,omitempty"`
+}
+
+// Package is installed on the instance. On Flatcar it's a system extension image that is downloaded from
+// <repository url>/<name>-<version>.raw, which requires the version, the hash and the repository.
+type Package struct {
+	// Name of the package.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9.+_-]*$`
+	Name string `json:"name"`
+	// Version pins the version of the package, e.g. 2.2* for apt or 2.2.* for yum/dnf.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Repository is the name of the repository the package is installed from. Apt installs the package with the
+	// distribution of the repository as target release, yum/dnf prefers the repository through its priority while the
+	// dependencies are resolved from all enabled repositories.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+	// Hash of the system extension image in the form sha512-<hex digest>. Flatcar verifies the downloaded image
+	// against it, the other package managers ignore it.
+	// +kubebuilder:validation:Pattern=`^sha512-[a-f0-9]{128}$`
+	// +optional
+	Hash string `json:"hash,omitempty"`
+	// Hold prevents the package from being upgraded or removed by the package manager.
+	// +optional
+	Hold bool `json:"hold,omitempty"`
+}
+
+// NodeTuning configures kernel parameters, kernel modules and huge pages of the instance.
+// The reserved huge pages are added to the system reservation of the kubelet, the other kubelet reservations are
+// configured with the kubelet configuration annotations of the MachineDeployment.
+type NodeTuning struct {
+	// Sysctls are the kernel parameters that are set on the instance, keyed by their name.
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// KernelModules are the kernel modules that are loaded on the instance.
+	// +optional
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// HugePages are the huge pages that are allocated before the kubelet starts. The kubelet detects them as capacity
+	// of the node and removes them from its allocatable memory, it doesn't start if fewer pages could be allocated.
+	// +optional
+	HugePages []HugePages `json:"hugePages,omitempty"`
+	// TransparentHugePages is the transparent huge pages mode of the instance.
+	// +optional
+	TransparentHugePages TransparentHugePagesMode `json:"transparentHugePages,omitempty"`
+}
+
+// HugePages is a number of huge pages of a size.
+type HugePages struct {
+	// Size of the huge pages, e.g. 2Mi or 1Gi.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Ki|Mi|Gi)$`
+	Size string `json:"size"`
+	// Count is the number of huge pages that are allocated.
+	// +kubebuilder:validation:Minimum=0
+	Count int32 `json:"count"`
+	// Reserved is the number of the allocated huge pages that are reserved for system daemons. They are rendered as
+	// hugepages-<size> into the systemReserved of the kubelet, which doesn't allocate them to pods.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Reserved int32 `json:"reserved,omitempty"`
+}
+
+// TransparentHugePagesMode is the mode of transparent huge pages.
+// +kubebuilder:validation:Enum=always;madvise;never
+type TransparentHugePagesMode string
+
+const (
+	TransparentHugePagesAlways  TransparentHugePagesMode = "always"
+	TransparentHugePagesMadvise TransparentHugePagesMode = "madvise"
+	TransparentHugePagesNever   TransparentHugePagesMode = "never"
+)
+
+// Storage describes the disks and filesystems of the instance.
+type Storage struct {
+	// Disks is a list of disks that are partitioned.
+	// +optional
+	Disks []Disk `json:"disks,omitempty"`
+	// Filesystems is a list of filesystems, including swap, that are created and mounted.
+	// +optional
+	Filesystems []Filesystem `json:"filesystems,omitempty"`
+}
+
+// Disk is a disk that is partitioned.
+type Disk struct {
+	// Device is the path of the disk, e.g. /dev/sdb.
+	Device string `json:"device"`
+	// WipeTable wipes the partition table of the disk before it's partitioned.
+	// +optional
+	WipeTable bool `json:"wipeTable,omitempty"`
+	// Partitions is a list of partitions that are created on the disk. cloud-init only supports a single partition
+	// that spans the whole disk.
+	// +optional
+	Partitions []Partition `json:"partitions,omitempty"`
+}
+
+// Partition is a partition of a disk.
+type Partition struct {
+	// Number is the number of the partition. The next free number is used if it's not set. It's only supported by
+	// ignition.
+	// +optional
+	Number int32 `json:"number,omitempty"`
+	// Label is the label of the partition. It's only supported by ignition.
+	// +optional
+	Label string `json:"label,omitempty"`
+	// SizeMiB is the size of the partition in MiB. The partition fills the remaining space of the disk if it's not set.
+	// +optional
+	SizeMiB int64 `json:"sizeMiB,omitempty"`
+}
+
+// FilesystemFormat is the format of a filesystem.
+// +kubebuilder:validation:Enum=ext4;xfs;swap
+type FilesystemFormat string
+
+const (
+	FilesystemFormatExt4 FilesystemFormat = "ext4"
+	FilesystemFormatXFS  FilesystemFormat = "xfs"
+	FilesystemFormatSwap FilesystemFormat = "swap"
+)
+
+// Filesystem is a filesystem that is created on a device and mounted. Swap is enabled instead of mounted.
+// +kubebuilder:validation:XValidation:rule="self.format != 'swap' || !has(self.path)",message="swap must not have a path"
+type Filesystem struct {
+	// Device is the path of the device, e.g. /dev/sdb1.
+	Device string `json:"device"`
+	// Format is the format of the filesystem.
+	Format FilesystemFormat `json:"format"`
+	// Label is the label of the filesystem.
+	// +optional
+	Label string `json:"label,omitempty"`
+	// WipeFilesystem creates the filesystem even if the device already contains a filesystem.
+	// +optional
+	WipeFilesystem bool `json:"wipeFilesystem,omitempty"`
+	// Path is the path that the filesystem is mounted at. The filesystem isn't mounted if it's not set.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// MountOptions are the options that the filesystem is mounted with.
+	// +optional
+	MountOptions []string `json:"mountOptions,omitempty"`
 }
 
 // ContainerRuntimeSpec aggregates information about a specific container runtime
@@ -130,10 +411,28 @@ type ContainerRuntimeSpec struct {
 	Templates map[string]string `json:"templates,omitempty"`
 }
 
-// FileContent can either reference a secret or contain inline configuration.
+// FileContent can either reference a secret or a config map, or contain inline configuration.
+// Exactly one of inline, secretRef and configMapRef must be set.
 type FileContent struct {
 	// Inline is a struct that contains information about the inlined data.
 	Inline *FileContentInline `json:"inline,omitempty"`
+	// SecretRef references a key of a secret that holds the file's data.
+	SecretRef *FileContentReference `json:"secretRef,omitempty"`
+	// ConfigMapRef references a key of a config map that holds the file's data.
+	ConfigMapRef *FileContentReference `json:"configMapRef,omitempty"`
+}
+
+// FileContentReference points to a key of a secret or config map. Referenced data is resolved when the
+// OperatingSystemConfig is rendered and is written to the file as-is, without being templated.
+type FileContentReference struct {
+	// Name is the name of the referenced object.
+	Name string `json:"name"`
+	// Key is the key of the referenced object that holds the file's data.
+	Key string `json:"key"`
+	// Namespace is the namespace of the referenced object. Defaults to the namespace of the OperatingSystemProfile,
+	// which is the only namespace that is allowed since OSM can only read secrets and config maps in its own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // FileContentInline contains keys for inlining a file content's data and encoding.
@@ -156,4 +455,163 @@ type CloudInitModule struct {
 	YumRepos map[string]map[string]string `json:"yum_repos,omitempty"`
 	// YumRepoDir the repo parts directory where individual yum repo config files will be written. Default: /etc/yum.repos.d
 	YumRepoDir string `json:"yum_repo_dir,omitempty"`
+	// Apt configures the apt sources, the primary mirror and the apt proxy.
+	// +optional
+	Apt *CloudInitApt `json:"apt,omitempty"`
+	// NTP configures the NTP client of the system.
+	// +optional
+	NTP *CloudInitNTP `json:"ntp,omitempty"`
+	// Timezone sets the timezone of the system, e.g. Europe/Berlin.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// CACerts adds CA certificates to the trusted certificates of the system.
+	// +optional
+	CACerts *CloudInitCACerts `json:"ca_certs,omitempty"`
+	// Mounts configures mount points and swap. Each entry contains the fields of an fstab entry, starting with the
+	// device.
+	// +optional
+	Mounts [][]string `json:"mounts,omitempty"`
+	// PowerState changes the power state of the system after cloud-init finished.
+	// +optional
+	PowerState *CloudInitPowerState `json:"power_state,omitempty"`
+	// WriteFiles writes files to the system, in addition to the files of the config.
+	// +optional
+	WriteFiles []CloudInitWriteFile `json:"write_files,omitempty"`
+	// PhoneHome posts data about the system to a URL after cloud-init finished.
+	// +optional
+	PhoneHome *CloudInitPhoneHome `json:"phone_home,omitempty"`
+}
+
+// CloudInitApt contains the fields of the cloud-init apt module.
+type CloudInitApt struct {
+	// PreserveSourcesList keeps the sources.list of the image instead of generating it from the primary mirror.
+	// +optional
+	PreserveSourcesList *bool `json:"preserve_sources_list,omitempty"`
+	// Primary are the primary mirrors, selected by architecture.
+	// +optional
+	Primary []CloudInitAptMirror `json:"primary,omitempty"`
+	// Sources are additional apt sources, keyed by the name of their file in /etc/apt/sources.list.d.
+	// +optional
+	Sources map[string]CloudInitAptSource `json:"sources,omitempty"`
+	// Proxy is the proxy that apt uses for all requests.
+	// +optional
+	Proxy string `json:"proxy,omitempty"`
+	// HTTPProxy is the proxy that apt uses for HTTP requests.
+	// +optional
+	HTTPProxy string `json:"http_proxy,omitempty"`
+	// HTTPSProxy is the proxy that apt uses for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"https_proxy,omitempty"`
+}
+
+// CloudInitAptMirror is an apt mirror for a set of architectures.
+type CloudInitAptMirror struct {
+	// Arches are the architectures the mirror is used for, e.g. default or amd64.
+	Arches []string `json:"arches"`
+	// URI of the mirror.
+	// +optional
+	URI string `json:"uri,omitempty"`
+	// Search are the URIs of mirrors that are used if they can be reached.
+	// +optional
+	Search []string `json:"search,omitempty"`
+}
+
+// CloudInitAptSource is an apt source.
+type CloudInitAptSource struct {
+	// Source is the sources.list entry, e.g. "deb http://archive.example.com $RELEASE main".
+	// +optional
+	Source string `json:"source,omitempty"`
+	// KeyID is the ID of the key that signs the source, which is imported from the keyserver.
+	// +optional
+	KeyID string `json:"keyid,omitempty"`
+	// Key is the ASCII armored key that signs the source.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Keyserver is the server the key is imported from.
+	// +optional
+	Keyserver string `json:"keyserver,omitempty"`
+	// Filename of the source in /etc/apt/sources.list.d.
+	// +optional
+	Filename string `json:"filename,omitempty"`
+}
+
+// CloudInitNTP contains the fields of the cloud-init ntp module.
+type CloudInitNTP struct {
+	// Enabled enables the NTP client.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// NTPClient is the NTP client to use, e.g. chrony or systemd-timesyncd.
+	// +optional
+	NTPClient string `json:"ntp_client,omitempty"`
+	// Servers are the NTP servers.
+	// +optional
+	Servers []string `json:"servers,omitempty"`
+	// Pools are the NTP pools.
+	// +optional
+	Pools []string `json:"pools,omitempty"`
+}
+
+// CloudInitCACerts contains the fields of the cloud-init ca_certs module.
+type CloudInitCACerts struct {
+	// RemoveDefaults removes the default CA certificates of the system.
+	// +optional
+	RemoveDefaults bool `json:"remove_defaults,omitempty"`
+	// Trusted are the PEM encoded CA certificates that are added.
+	// +optional
+	Trusted []string `json:"trusted,omitempty"`
+}
+
+// CloudInitPowerState contains the fields of the cloud-init power_state module.
+type CloudInitPowerState struct {
+	// Mode is the power state the system is put into.
+	// +kubebuilder:validation:Enum=poweroff;halt;reboot
+	Mode string `json:"mode"`
+	// Delay is the delay of the change, e.g. now or +5 minutes.
+	// +optional
+	Delay string `json:"delay,omitempty"`
+	// Message is written to the console before the change.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Timeout is the time in seconds cloud-init waits for its processes to finish.
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+	// Condition is a command that has to succeed for the change to happen.
+	// +optional
+	Condition string `json:"condition,omitempty"`
+}
+
+// CloudInitWriteFile contains the fields of a file of the cloud-init write_files module.
+type CloudInitWriteFile struct {
+	// Path of the file.
+	Path string `json:"path"`
+	// Content of the file.
+	// +optional
+	Content string `json:"content,omitempty"`
+	// Encoding of the content, e.g. b64.
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+	// Owner of the file as user:group.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Permissions of the file as octal string, e.g. '0644'.
+	// +optional
+	Permissions string `json:"permissions,omitempty"`
+	// Append appends the content to the file instead of overwriting it.
+	// +optional
+	Append bool `json:"append,omitempty"`
+	// Defer writes the file after users and packages were created, which is required for files owned by them.
+	// +optional
+	Defer bool `json:"defer,omitempty"`
+}
+
+// CloudInitPhoneHome contains the fields of the cloud-init phone_home module.
+type CloudInitPhoneHome struct {
+	// URL the data is posted to. $INSTANCE_ID is replaced by the ID of the instance.
+	URL string `json:"url"`
+	// Post are the keys of the data that is posted, e.g. pub_key_rsa, instance_id or fqdn. Defaults to all.
+	// +optional
+	Post []string `json:"post,omitempty"`
+	// Tries is the number of attempts to post the data.
+	// +optional
+	Tries int32 `json:"tries,omitempty"`
 }
@@ -0,0 +1,844 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"k8c.io/operating-system-manager/pkg/crd/osm/v1beta1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConversionDataAnnotation holds the fields of an object that can't be represented in the API version it was converted
+// to. They are restored when the object is converted back to the API version that supports them.
+const ConversionDataAnnotation = "operatingsystemmanager.k8c.io/conversion-data"
+
+// conversionData contains the fields that are lost when converting between v1alpha1 and v1beta1.
+type conversionData struct {
+	BootstrapConfig    configConversionData `json:"bootstrapConfig,omitzero"`
+	ProvisioningConfig configConversionData `json:"provisioningConfig,omitzero"`
+}
+
+type configConversionData struct {
+	// ContainerRuntimes are the v1alpha1 container runtimes that were dropped from v1beta1, i.e. docker.
+	ContainerRuntimes []ContainerRuntimeSpec `json:"containerRuntimes,omitempty"`
+	// Modules are the v1beta1 cloud-init modules that aren't supported by v1alpha1.
+	Modules map[string]apiextensionsv1.JSON `json:"modules,omitempty"`
+}
+
+// ConvertTo converts the OperatingSystemProfile to the hub version v1beta1.
+func (osp *OperatingSystemProfile) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1beta1.OperatingSystemProfile)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = *osp.ObjectMeta.DeepCopy()
+	restored, err := popConversionData(&dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	lost := conversionData{}
+	dst.Spec.OSName = v1beta1.OperatingSystem(osp.Spec.OSName)
+	dst.Spec.OSVersion = osp.Spec.OSVersion
+	dst.Spec.Version = osp.Spec.Version
+	dst.Spec.ProvisioningUtility = v1beta1.ProvisioningUtility(osp.Spec.ProvisioningUtility)
+	dst.Spec.Extends = osp.Spec.Extends
+	if err := convertJSON(osp.Spec.SupportedCloudProviders, &dst.Spec.SupportedCloudProviders); err != nil {
+		return err
+	}
+	if err := convertJSON(osp.Spec.Parameters, &dst.Spec.Parameters); err != nil {
+		return err
+	}
+	if err := convertOSPConfigTo(&osp.Spec.BootstrapConfig, &dst.Spec.BootstrapConfig, &restored.BootstrapConfig, &lost.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if err := convertOSPConfigTo(&osp.Spec.ProvisioningConfig, &dst.Spec.ProvisioningConfig, &restored.ProvisioningConfig, &lost.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+	if err := convertJSON(osp.Status, &dst.Status); err != nil {
+		return err
+	}
+
+	return setConversionData(&dst.ObjectMeta, lost)
+}
+
+// ConvertFrom converts the OperatingSystemProfile from the hub version v1beta1.
+func (osp *OperatingSystemProfile) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1beta1.OperatingSystemProfile)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	osp.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	restored, err := popConversionData(&osp.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	lost := conversionData{}
+	osp.Spec.OSName = OperatingSystem(src.Spec.OSName)
+	osp.Spec.OSVersion = src.Spec.OSVersion
+	osp.Spec.Version = src.Spec.Version
+	osp.Spec.ProvisioningUtility = ProvisioningUtility(src.Spec.ProvisioningUtility)
+	osp.Spec.Extends = src.Spec.Extends
+	if err := convertJSON(src.Spec.SupportedCloudProviders, &osp.Spec.SupportedCloudProviders); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.Parameters, &osp.Spec.Parameters); err != nil {
+		return err
+	}
+	if err := convertOSPConfigFrom(&src.Spec.BootstrapConfig, &osp.Spec.BootstrapConfig, &restored.BootstrapConfig, &lost.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if err := convertOSPConfigFrom(&src.Spec.ProvisioningConfig, &osp.Spec.ProvisioningConfig, &restored.ProvisioningConfig, &lost.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+	if err := convertJSON(src.Status, &osp.Status); err != nil {
+		return err
+	}
+
+	return setConversionData(&osp.ObjectMeta, lost)
+}
+
+// ConvertTo converts the OperatingSystemConfig to the hub version v1beta1.
+func (osc *OperatingSystemConfig) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1beta1.OperatingSystemConfig)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = *osc.ObjectMeta.DeepCopy()
+	restored, err := popConversionData(&dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	lost := conversionData{}
+	dst.Spec.OSName = v1beta1.OperatingSystem(osc.Spec.OSName)
+	dst.Spec.OSVersion = osc.Spec.OSVersion
+	dst.Spec.ProvisioningUtility = v1beta1.ProvisioningUtility(osc.Spec.ProvisioningUtility)
+	if err := convertJSON(osc.Spec.CloudProvider, &dst.Spec.CloudProvider); err != nil {
+		return err
+	}
+	if err := convertOSCConfigTo(&osc.Spec.BootstrapConfig, &dst.Spec.BootstrapConfig, &restored.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if err := convertOSCConfigTo(&osc.Spec.ProvisioningConfig, &dst.Spec.ProvisioningConfig, &restored.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+	if err := convertJSON(osc.Status, &dst.Status); err != nil {
+		return err
+	}
+
+	return setConversionData(&dst.ObjectMeta, lost)
+}
+
+// ConvertFrom converts the OperatingSystemConfig from the hub version v1beta1.
+func (osc *OperatingSystemConfig) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1beta1.OperatingSystemConfig)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	osc.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if _, err := popConversionData(&osc.ObjectMeta); err != nil {
+		return err
+	}
+
+	lost := conversionData{}
+	osc.Spec.OSName = OperatingSystem(src.Spec.OSName)
+	osc.Spec.OSVersion = src.Spec.OSVersion
+	osc.Spec.ProvisioningUtility = ProvisioningUtility(src.Spec.ProvisioningUtility)
+	if err := convertJSON(src.Spec.CloudProvider, &osc.Spec.CloudProvider); err != nil {
+		return err
+	}
+	if err := convertOSCConfigFrom(&src.Spec.BootstrapConfig, &osc.Spec.BootstrapConfig, &lost.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if err := convertOSCConfigFrom(&src.Spec.ProvisioningConfig, &osc.Spec.ProvisioningConfig, &lost.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+	if err := convertJSON(src.Status, &osc.Status); err != nil {
+		return err
+	}
+
+	return setConversionData(&osc.ObjectMeta, lost)
+}
+
+// ConvertTo converts the OperatingSystemProfileFragment to the hub version v1beta1.
+func (fragment *OperatingSystemProfileFragment) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1beta1.OperatingSystemProfileFragment)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = *fragment.ObjectMeta.DeepCopy()
+	restored, err := popConversionData(&dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.OperatingSystemProfileSelector = fragment.Spec.OperatingSystemProfileSelector.DeepCopy()
+	dst.Spec.MachineDeploymentSelector = fragment.Spec.MachineDeploymentSelector.DeepCopy()
+	if dst.Spec.BootstrapConfig, err = convertOSPFragmentConfigTo(fragment.Spec.BootstrapConfig, &restored.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if dst.Spec.ProvisioningConfig, err = convertOSPFragmentConfigTo(fragment.Spec.ProvisioningConfig, &restored.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the OperatingSystemProfileFragment from the hub version v1beta1.
+func (fragment *OperatingSystemProfileFragment) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1beta1.OperatingSystemProfileFragment)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	fragment.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if _, err := popConversionData(&fragment.ObjectMeta); err != nil {
+		return err
+	}
+
+	var err error
+	lost := conversionData{}
+	fragment.Spec.OperatingSystemProfileSelector = src.Spec.OperatingSystemProfileSelector.DeepCopy()
+	fragment.Spec.MachineDeploymentSelector = src.Spec.MachineDeploymentSelector.DeepCopy()
+	if fragment.Spec.BootstrapConfig, err = convertOSPFragmentConfigFrom(src.Spec.BootstrapConfig, &lost.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if fragment.Spec.ProvisioningConfig, err = convertOSPFragmentConfigFrom(src.Spec.ProvisioningConfig, &lost.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+
+	return setConversionData(&fragment.ObjectMeta, lost)
+}
+
+// ConvertTo converts the OperatingSystemConfigOverride to the hub version v1beta1.
+func (override *OperatingSystemConfigOverride) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1beta1.OperatingSystemConfigOverride)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = *override.ObjectMeta.DeepCopy()
+	restored, err := popConversionData(&dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec.MachineDeployments = slices.Clone(override.Spec.MachineDeployments)
+	dst.Spec.MachineDeploymentSelector = override.Spec.MachineDeploymentSelector.DeepCopy()
+	if dst.Spec.BootstrapConfig, err = convertOSCOverrideConfigTo(override.Spec.BootstrapConfig, &restored.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if dst.Spec.ProvisioningConfig, err = convertOSCOverrideConfigTo(override.Spec.ProvisioningConfig, &restored.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the OperatingSystemConfigOverride from the hub version v1beta1.
+func (override *OperatingSystemConfigOverride) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1beta1.OperatingSystemConfigOverride)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	override.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if _, err := popConversionData(&override.ObjectMeta); err != nil {
+		return err
+	}
+
+	var err error
+	lost := conversionData{}
+	override.Spec.MachineDeployments = slices.Clone(src.Spec.MachineDeployments)
+	override.Spec.MachineDeploymentSelector = src.Spec.MachineDeploymentSelector.DeepCopy()
+	if override.Spec.BootstrapConfig, err = convertOSCOverrideConfigFrom(src.Spec.BootstrapConfig, &lost.BootstrapConfig); err != nil {
+		return fmt.Errorf("failed to convert bootstrap config: %w", err)
+	}
+	if override.Spec.ProvisioningConfig, err = convertOSCOverrideConfigFrom(src.Spec.ProvisioningConfig, &lost.ProvisioningConfig); err != nil {
+		return fmt.Errorf("failed to convert provisioning config: %w", err)
+	}
+
+	return setConversionData(&override.ObjectMeta, lost)
+}
+
+// ConvertTo converts the TemplateLibrary to the hub version v1beta1.
+func (library *TemplateLibrary) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1beta1.TemplateLibrary)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = *library.ObjectMeta.DeepCopy()
+	dst.Spec.Version = library.Spec.Version
+	dst.Spec.Templates = copyStringMap(library.Spec.Templates)
+
+	return nil
+}
+
+// ConvertFrom converts the TemplateLibrary from the hub version v1beta1.
+func (library *TemplateLibrary) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1beta1.TemplateLibrary)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	library.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	library.Spec.Version = src.Spec.Version
+	library.Spec.Templates = copyStringMap(src.Spec.Templates)
+
+	return nil
+}
+
+func convertOSPConfigTo(in *OSPConfig, out *v1beta1.OSPConfig, restored, lost *configConversionData) error {
+	out.SupportedContainerRuntimes = nil
+	for _, runtime := range in.SupportedContainerRuntimes {
+		// Docker isn't supported by v1beta1, it's kept in the conversion data to not lose it on a round trip.
+		if runtime.Name == ContainerRuntimeDocker {
+			lost.ContainerRuntimes = append(lost.ContainerRuntimes, *runtime.DeepCopy())
+			continue
+		}
+
+		files, err := convertFilesTo(runtime.Files)
+		if err != nil {
+			return err
+		}
+		out.SupportedContainerRuntimes = append(out.SupportedContainerRuntimes, v1beta1.ContainerRuntimeSpec{
+			Name:      v1beta1.ContainerRuntime(runtime.Name),
+			Files:     files,
+			Templates: copyStringMap(runtime.Templates),
+		})
+	}
+
+	out.Templates = copyStringMap(in.Templates)
+	out.TemplateLibraries = slices.Clone(in.TemplateLibraries)
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Remove, &out.Remove); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Users, &out.Users); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Groups, &out.Groups); err != nil {
+		return err
+	}
+
+	var err error
+	if out.Files, err = convertFilesTo(in.Files); err != nil {
+		return err
+	}
+	out.Directories = convertDirectoriesTo(in.Directories)
+	if err := convertJSON(in.Links, &out.Links); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Storage, &out.Storage); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Packages, &out.Packages); err != nil {
+		return err
+	}
+	if err := convertJSON(in.NodeTuning, &out.NodeTuning); err != nil {
+		return err
+	}
+	if out.CloudInitModules, err = convertCloudInitModulesTo(in.CloudInitModules, restored.Modules); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func convertOSPConfigFrom(in *v1beta1.OSPConfig, out *OSPConfig, restored, lost *configConversionData) error {
+	out.SupportedContainerRuntimes = nil
+	for _, runtime := range in.SupportedContainerRuntimes {
+		files, err := convertFilesFrom(runtime.Files)
+		if err != nil {
+			return err
+		}
+		out.SupportedContainerRuntimes = append(out.SupportedContainerRuntimes, ContainerRuntimeSpec{
+			Name:      ContainerRuntime(runtime.Name),
+			Files:     files,
+			Templates: copyStringMap(runtime.Templates),
+		})
+	}
+	for _, runtime := range restored.ContainerRuntimes {
+		out.SupportedContainerRuntimes = append(out.SupportedContainerRuntimes, *runtime.DeepCopy())
+	}
+
+	out.Templates = copyStringMap(in.Templates)
+	out.TemplateLibraries = slices.Clone(in.TemplateLibraries)
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Remove, &out.Remove); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Users, &out.Users); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Groups, &out.Groups); err != nil {
+		return err
+	}
+
+	var err error
+	if out.Files, err = convertFilesFrom(in.Files); err != nil {
+		return err
+	}
+	if out.Directories, err = convertDirectoriesFrom(in.Directories); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Links, &out.Links); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Storage, &out.Storage); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Packages, &out.Packages); err != nil {
+		return err
+	}
+	if err := convertJSON(in.NodeTuning, &out.NodeTuning); err != nil {
+		return err
+	}
+	if out.CloudInitModules, lost.Modules, err = convertCloudInitModulesFrom(in.CloudInitModules); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func convertOSPFragmentConfigTo(in *OSPFragmentConfig, restored *configConversionData) (*v1beta1.OSPFragmentConfig, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &v1beta1.OSPFragmentConfig{Templates: copyStringMap(in.Templates)}
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if out.Files, err = convertFilesTo(in.Files); err != nil {
+		return nil, err
+	}
+	if out.CloudInitModules, err = convertCloudInitModulesTo(in.CloudInitModules, restored.Modules); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func convertOSPFragmentConfigFrom(in *v1beta1.OSPFragmentConfig, lost *configConversionData) (*OSPFragmentConfig, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &OSPFragmentConfig{Templates: copyStringMap(in.Templates)}
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if out.Files, err = convertFilesFrom(in.Files); err != nil {
+		return nil, err
+	}
+	if out.CloudInitModules, lost.Modules, err = convertCloudInitModulesFrom(in.CloudInitModules); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func convertOSCConfigTo(in *OSCConfig, out *v1beta1.OSCConfig, restored *configConversionData) error {
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return err
+	}
+	out.UserSSHKeys = append([]string(nil), in.UserSSHKeys...)
+	if err := convertJSON(in.Users, &out.Users); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Groups, &out.Groups); err != nil {
+		return err
+	}
+
+	var err error
+	if out.Files, err = convertFilesTo(in.Files); err != nil {
+		return err
+	}
+	out.Directories = convertDirectoriesTo(in.Directories)
+	if err := convertJSON(in.Links, &out.Links); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Storage, &out.Storage); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Packages, &out.Packages); err != nil {
+		return err
+	}
+	if out.CloudInitModules, err = convertCloudInitModulesTo(in.CloudInitModules, restored.Modules); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func convertOSCConfigFrom(in *v1beta1.OSCConfig, out *OSCConfig, lost *configConversionData) error {
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return err
+	}
+	out.UserSSHKeys = append([]string(nil), in.UserSSHKeys...)
+	if err := convertJSON(in.Users, &out.Users); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Groups, &out.Groups); err != nil {
+		return err
+	}
+
+	var err error
+	if out.Files, err = convertFilesFrom(in.Files); err != nil {
+		return err
+	}
+	if out.Directories, err = convertDirectoriesFrom(in.Directories); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Links, &out.Links); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Storage, &out.Storage); err != nil {
+		return err
+	}
+	if err := convertJSON(in.Packages, &out.Packages); err != nil {
+		return err
+	}
+	if out.CloudInitModules, lost.Modules, err = convertCloudInitModulesFrom(in.CloudInitModules); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func convertOSCOverrideConfigTo(in *OSCOverrideConfig, restored *configConversionData) (*v1beta1.OSCOverrideConfig, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &v1beta1.OSCOverrideConfig{UserSSHKeys: slices.Clone(in.UserSSHKeys)}
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return nil, err
+	}
+	if err := convertJSON(in.Packages, &out.Packages); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if out.Files, err = convertFilesTo(in.Files); err != nil {
+		return nil, err
+	}
+	if out.CloudInitModules, err = convertCloudInitModulesTo(in.CloudInitModules, restored.Modules); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func convertOSCOverrideConfigFrom(in *v1beta1.OSCOverrideConfig, lost *configConversionData) (*OSCOverrideConfig, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &OSCOverrideConfig{UserSSHKeys: slices.Clone(in.UserSSHKeys)}
+	if err := convertJSON(in.Units, &out.Units); err != nil {
+		return nil, err
+	}
+	if err := convertJSON(in.Packages, &out.Packages); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if out.Files, err = convertFilesFrom(in.Files); err != nil {
+		return nil, err
+	}
+	if out.CloudInitModules, lost.Modules, err = convertCloudInitModulesFrom(in.CloudInitModules); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func convertFilesTo(in []File) ([]v1beta1.File, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := make([]v1beta1.File, 0, len(in))
+	for _, file := range in {
+		converted := v1beta1.File{
+			Path:        file.Path,
+			Owner:       file.Owner,
+			Group:       file.Group,
+			WritePolicy: v1beta1.FileWritePolicy(file.WritePolicy),
+		}
+		if err := convertJSON(file.When, &converted.When); err != nil {
+			return nil, err
+		}
+		// v1alpha1 permissions are octal modes written as decimal numbers, e.g. 644.
+		if file.Permissions != 0 {
+			converted.Permissions = fmt.Sprintf("%04d", file.Permissions)
+		}
+		if err := convertJSON(file.Content, &converted.Content); err != nil {
+			return nil, err
+		}
+		out = append(out, converted)
+	}
+
+	return out, nil
+}
+
+func convertFilesFrom(in []v1beta1.File) ([]File, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := make([]File, 0, len(in))
+	for _, file := range in {
+		converted := File{
+			Path:        file.Path,
+			Owner:       file.Owner,
+			Group:       file.Group,
+			WritePolicy: FileWritePolicy(file.WritePolicy),
+		}
+		if err := convertJSON(file.When, &converted.When); err != nil {
+			return nil, err
+		}
+		if file.Permissions != "" {
+			permissions, err := strconv.ParseInt(file.Permissions, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid permissions %q of file %q: %w", file.Permissions, file.Path, err)
+			}
+			converted.Permissions = int32(permissions)
+		}
+		if err := convertJSON(file.Content, &converted.Content); err != nil {
+			return nil, err
+		}
+		out = append(out, converted)
+	}
+
+	return out, nil
+}
+
+func convertDirectoriesTo(in []Directory) []v1beta1.Directory {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]v1beta1.Directory, 0, len(in))
+	for _, directory := range in {
+		converted := v1beta1.Directory{Path: directory.Path, Owner: directory.Owner, Group: directory.Group}
+		if directory.Permissions != 0 {
+			converted.Permissions = fmt.Sprintf("%04d", directory.Permissions)
+		}
+		out = append(out, converted)
+	}
+
+	return out
+}
+
+func convertDirectoriesFrom(in []v1beta1.Directory) ([]Directory, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := make([]Directory, 0, len(in))
+	for _, directory := range in {
+		converted := Directory{Path: directory.Path, Owner: directory.Owner, Group: directory.Group}
+		if directory.Permissions != "" {
+			permissions, err := strconv.ParseInt(directory.Permissions, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid permissions %q of directory %q: %w", directory.Permissions, directory.Path, err)
+			}
+			converted.Permissions = int32(permissions)
+		}
+		out = append(out, converted)
+	}
+
+	return out, nil
+}
+
+// convertCloudInitModulesTo converts the fixed set of v1alpha1 modules to v1beta1 modules and adds the modules that
+// were lost in a previous conversion to v1alpha1.
+func convertCloudInitModulesTo(in *CloudInitModule, restored map[string]apiextensionsv1.JSON) (v1beta1.CloudInitModules, error) {
+	if in == nil && len(restored) == 0 {
+		return nil, nil
+	}
+
+	out := v1beta1.CloudInitModules{}
+	for name, value := range restored {
+		out[name] = *value.DeepCopy()
+	}
+
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cloud-init modules: %w", err)
+		}
+
+		modules := map[string]json.RawMessage{}
+		if err := json.Unmarshal(encoded, &modules); err != nil {
+			return nil, fmt.Errorf("failed to decode cloud-init modules: %w", err)
+		}
+
+		for name, value := range modules {
+			out[name] = apiextensionsv1.JSON{Raw: value}
+		}
+	}
+
+	return out, nil
+}
+
+// convertCloudInitModulesFrom converts v1beta1 modules to v1alpha1 modules. Modules that can't be represented by
+// v1alpha1 are returned separately.
+func convertCloudInitModulesFrom(in v1beta1.CloudInitModules) (*CloudInitModule, map[string]apiextensionsv1.JSON, error) {
+	if in == nil {
+		return nil, nil, nil
+	}
+
+	out := &CloudInitModule{}
+	var lost map[string]apiextensionsv1.JSON
+	for name, value := range in {
+		encoded, err := json.Marshal(map[string]apiextensionsv1.JSON{name: value})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode cloud-init module %q: %w", name, err)
+		}
+
+		if !isCloudInitModule(encoded) {
+			if lost == nil {
+				lost = map[string]apiextensionsv1.JSON{}
+			}
+			lost[name] = *value.DeepCopy()
+			continue
+		}
+
+		if err := json.Unmarshal(encoded, out); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode cloud-init module %q: %w", name, err)
+		}
+	}
+
+	return out, lost, nil
+}
+
+// isCloudInitModule checks if the encoded module can be represented by CloudInitModule without losing information.
+func isCloudInitModule(encoded []byte) bool {
+	module := &CloudInitModule{}
+	if err := json.Unmarshal(encoded, module); err != nil {
+		return false
+	}
+
+	reencoded, err := json.Marshal(module)
+	if err != nil {
+		return false
+	}
+
+	var want, got any
+	if err := json.Unmarshal(encoded, &want); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(reencoded, &got); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(want, got)
+}
+
+// convertJSON converts between types that share the same JSON representation in both API versions.
+func convertJSON(in, out any) error {
+	encoded, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode %T: %w", in, err)
+	}
+
+	if err := json.Unmarshal(encoded, out); err != nil {
+		return fmt.Errorf("failed to decode %T: %w", out, err)
+	}
+
+	return nil
+}
+
+func copyStringMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+// popConversionData removes the conversion data annotation from the object and returns its content.
+func popConversionData(meta *metav1.ObjectMeta) (conversionData, error) {
+	data := conversionData{}
+
+	value, ok := meta.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return data, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &data); err != nil {
+		return data, fmt.Errorf("failed to decode %s annotation: %w", ConversionDataAnnotation, err)
+	}
+
+	delete(meta.Annotations, ConversionDataAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+
+	return data, nil
+}
+
+// setConversionData stores the fields that were lost during the conversion in an annotation of the object.
+func setConversionData(meta *metav1.ObjectMeta, data conversionData) error {
+	if reflect.DeepEqual(data, conversionData{}) {
+		return nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s annotation: %w", ConversionDataAnnotation, err)
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[ConversionDataAnnotation] = string(encoded)
+
+	return nil
+}
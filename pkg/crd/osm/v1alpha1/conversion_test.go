@@ -0,0 +1,387 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"k8c.io/operating-system-manager/pkg/crd/osm/v1beta1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func TestOperatingSystemProfileConversion(t *testing.T) {
+	osp := &OperatingSystemProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "osp-ubuntu",
+			Namespace:   "kube-system",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec: OperatingSystemProfileSpec{
+			OSName:    OperatingSystemUbuntu,
+			OSVersion: "22.04",
+			Version:   "v1.0.0",
+			SupportedCloudProviders: []CloudProviderSpec{
+				{Name: CloudProviderAWS, Spec: runtime.RawExtension{Raw: []byte(`{"ami":"ami-1234"}`)}},
+			},
+			ProvisioningUtility: ProvisioningUtilityCloudInit,
+			Extends:             "osp-ubuntu-base",
+			Parameters: []Parameter{
+				{Name: "proxyHost", Description: "Host of the HTTP proxy", Type: ParameterTypeString, Required: true},
+				{Name: "proxyPort", Type: ParameterTypeInteger, Default: &apiextensionsv1.JSON{Raw: []byte(`3128`)}},
+			},
+			BootstrapConfig: OSPConfig{
+				Files: []File{
+					{Path: "/opt/bin/bootstrap", Permissions: 755, Content: FileContent{Inline: &FileContentInline{Data: "#!/bin/bash"}}},
+					{Path: "/etc/environment", Owner: "operator", WritePolicy: FileWritePolicyAppend, Content: FileContent{Inline: &FileContentInline{Data: "FOO=bar"}}},
+				},
+				Units:       []Unit{{Name: "bootstrap.service", Enable: ptr.To(true), Content: ptr.To("[Unit]")}},
+				Users:       []User{{Name: "operator", UID: ptr.To[int64](1500), Groups: []string{"operators"}, Sudo: []string{"ALL=(ALL) NOPASSWD:ALL"}}},
+				Groups:      []Group{{Name: "operators", GID: ptr.To[int64](1500)}},
+				Directories: []Directory{{Path: "/opt/operator", Permissions: 750, Owner: "operator", Group: "operators"}},
+				Links:       []Link{{Path: "/usr/local/bin/bootstrap", Target: "/opt/bin/bootstrap"}},
+				Packages: &Packages{
+					Repositories: []PackageRepository{{Name: "docker", URL: "https://download.docker.com/linux/ubuntu", Distribution: "jammy", Components: []string{"stable"}}},
+					Packages:     []Package{{Name: "containerd.io", Version: "2.2*", Repository: "docker", Hold: true}},
+				},
+				NodeTuning: &NodeTuning{
+					Sysctls:              map[string]string{"vm.max_map_count": "262144"},
+					KernelModules:        []string{"nvme_tcp"},
+					HugePages:            []HugePages{{Size: "2Mi", Count: 512, Reserved: 64}},
+					TransparentHugePages: TransparentHugePagesMadvise,
+				},
+				Storage: &Storage{
+					Disks:       []Disk{{Device: "/dev/sdb", Partitions: []Partition{{Number: 1, Label: "data", SizeMiB: 1024}}}},
+					Filesystems: []Filesystem{{Device: "/dev/sdb1", Format: FilesystemFormatXFS, Path: "/var/lib/data", MountOptions: []string{"noatime"}}},
+				},
+				Remove: &OSPConfigRemovals{Files: []string{"/etc/motd"}, Templates: []string{"motd"}},
+			},
+			ProvisioningConfig: OSPConfig{
+				SupportedContainerRuntimes: []ContainerRuntimeSpec{
+					{
+						Name:      ContainerRuntimeContainerd,
+						Files:     []File{{Path: "/etc/containerd/config.toml", Permissions: 644, Content: FileContent{Inline: &FileContentInline{Data: "{{ .Config }}"}}}},
+						Templates: map[string]string{"config": "version = 2"},
+					},
+					{
+						Name:  ContainerRuntimeDocker,
+						Files: []File{{Path: "/etc/docker/daemon.json", Permissions: 644, Content: FileContent{Inline: &FileContentInline{Data: "{}"}}}},
+					},
+				},
+				Templates:         map[string]string{"setup": "echo setup"},
+				TemplateLibraries: []string{"common"},
+				Files: []File{
+					{Path: "/etc/kubernetes/ca.crt", Permissions: 600, Content: FileContent{SecretRef: &FileContentReference{Name: "ca", Key: "ca.crt"}}},
+					{
+						Path:    "/etc/aws.conf",
+						Content: FileContent{Inline: &FileContentInline{Data: "aws"}},
+						When: &Selector{
+							CloudProviders:            []CloudProvider{CloudProviderAWS},
+							OSVersions:                ">= 22.04",
+							KubernetesVersions:        "~1.31",
+							Architectures:             []string{"arm64"},
+							MachineDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+						},
+					},
+				},
+				Units: []Unit{{Name: "aws.service", When: &Selector{CloudProviders: []CloudProvider{CloudProviderAWS}}}},
+				CloudInitModules: &CloudInitModule{
+					RunCMD:     []string{"systemctl restart setup"},
+					YumRepoDir: "/etc/yum.repos.d",
+				},
+			},
+		},
+		Status: OperatingSystemProfileStatus{
+			MachineDeployments: []OperatingSystemProfileMachineDeployment{
+				{Namespace: "kube-system", Name: "md", RenderedVersion: "v1.0.0"},
+			},
+		},
+	}
+
+	hub := &v1beta1.OperatingSystemProfile{}
+	if err := osp.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if hub.Spec.BootstrapConfig.Files[0].Permissions != "0755" {
+		t.Errorf("expected permissions 0755, got %q", hub.Spec.BootstrapConfig.Files[0].Permissions)
+	}
+	if len(hub.Spec.ProvisioningConfig.SupportedContainerRuntimes) != 1 {
+		t.Errorf("expected docker to be dropped from container runtimes, got %v", hub.Spec.ProvisioningConfig.SupportedContainerRuntimes)
+	}
+	if string(hub.Spec.ProvisioningConfig.CloudInitModules["runcmd"].Raw) != `["systemctl restart setup"]` {
+		t.Errorf("unexpected runcmd module %q", hub.Spec.ProvisioningConfig.CloudInitModules["runcmd"].Raw)
+	}
+
+	converted := &OperatingSystemProfile{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(converted, osp); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemProfile: %v", diff)
+	}
+}
+
+func TestOperatingSystemProfileConversionPreservesUnknownModules(t *testing.T) {
+	hub := &v1beta1.OperatingSystemProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "osp-rhel", Namespace: "kube-system"},
+		Spec: v1beta1.OperatingSystemProfileSpec{
+			OSName:    v1beta1.OperatingSystemRHEL,
+			OSVersion: "8.5",
+			Version:   "v1.0.0",
+			ProvisioningConfig: v1beta1.OSPConfig{
+				Files: []v1beta1.File{
+					{Path: "/etc/motd", Permissions: "0644", Content: v1beta1.FileContent{Inline: &v1beta1.FileContentInline{Data: "hello"}}},
+				},
+				CloudInitModules: v1beta1.CloudInitModules{
+					"bootcmd": {Raw: []byte(`["echo boot"]`)},
+					"snap":    {Raw: []byte(`{"commands":["snap install jq"]}`)},
+					// Known to v1alpha1 but with a type that it can't represent.
+					"runcmd": {Raw: []byte(`[["echo","run"]]`)},
+				},
+			},
+		},
+	}
+
+	spoke := &OperatingSystemProfile{}
+	if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(spoke.Spec.ProvisioningConfig.CloudInitModules, &CloudInitModule{BootCMD: []string{"echo boot"}}); diff != nil {
+		t.Errorf("unexpected v1alpha1 modules: %v", diff)
+	}
+	if spoke.Spec.ProvisioningConfig.Files[0].Permissions != 644 {
+		t.Errorf("expected permissions 644, got %d", spoke.Spec.ProvisioningConfig.Files[0].Permissions)
+	}
+	if _, ok := spoke.Annotations[ConversionDataAnnotation]; !ok {
+		t.Errorf("expected unsupported modules to be stored in the %s annotation", ConversionDataAnnotation)
+	}
+
+	converted := &v1beta1.OperatingSystemProfile{}
+	if err := spoke.ConvertTo(converted); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(converted, hub); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemProfile: %v", diff)
+	}
+}
+
+func TestOperatingSystemConfigConversion(t *testing.T) {
+	osc := &OperatingSystemConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "md-osc-provisioning", Namespace: "kube-system"},
+		Spec: OperatingSystemConfigSpec{
+			OSName:        OperatingSystemFlatcar,
+			OSVersion:     "3374.2.0",
+			CloudProvider: CloudProviderSpec{Name: CloudProviderHetzner},
+			BootstrapConfig: OSCConfig{
+				Files: []File{{Path: "/opt/bin/bootstrap", Permissions: 755, Content: FileContent{Inline: &FileContentInline{Data: "#!/bin/bash"}}}},
+			},
+			ProvisioningConfig: OSCConfig{
+				Units:       []Unit{{Name: "setup.service", Mask: ptr.To(false), DropIns: []DropIn{{Name: "10-env.conf", Content: "[Service]"}}}},
+				UserSSHKeys: []string{"ssh-ed25519 AAAA"},
+				CloudInitModules: &CloudInitModule{
+					YumRepos: map[string]map[string]string{"epel": {"baseurl": "https://example.com"}},
+					NTP:      &CloudInitNTP{Enabled: ptr.To(true), Servers: []string{"ntp.example.com"}},
+					Timezone: "Europe/Berlin",
+					Mounts:   [][]string{{"/dev/sdb", "/data", "ext4", "defaults", "0", "2"}},
+					WriteFiles: []CloudInitWriteFile{
+						{Path: "/etc/motd", Content: "hello", Owner: "operator:operators", Defer: true},
+					},
+				},
+			},
+			ProvisioningUtility: ProvisioningUtilityIgnition,
+		},
+		Status: OperatingSystemConfigStatus{
+			OperatingSystemProfile: "osp-flatcar",
+			CloudConfigSecrets:     []CloudConfigSecretStatus{{Name: "md-osc-provisioning", Namespace: "cloud-init-settings", Type: "provisioning", ContentHash: "abc"}},
+			Conditions:             []metav1.Condition{{Type: OperatingSystemConfigRendered, Status: metav1.ConditionTrue, Reason: "Rendered"}},
+		},
+	}
+
+	hub := &v1beta1.OperatingSystemConfig{}
+	if err := osc.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if _, ok := hub.Spec.ProvisioningConfig.CloudInitModules["yum_repos"]; !ok {
+		t.Errorf("expected yum_repos module, got %v", hub.Spec.ProvisioningConfig.CloudInitModules)
+	}
+	if string(hub.Spec.ProvisioningConfig.CloudInitModules["ntp"].Raw) != `{"enabled":true,"servers":["ntp.example.com"]}` {
+		t.Errorf("unexpected ntp module %q", hub.Spec.ProvisioningConfig.CloudInitModules["ntp"].Raw)
+	}
+
+	converted := &OperatingSystemConfig{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(converted, osc); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemConfig: %v", diff)
+	}
+}
+
+func TestOperatingSystemProfileFragmentConversion(t *testing.T) {
+	fragment := &OperatingSystemProfileFragment{
+		ObjectMeta: metav1.ObjectMeta{Name: "custom-ca", Namespace: "kube-system"},
+		Spec: OperatingSystemProfileFragmentSpec{
+			OperatingSystemProfileSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"os": "ubuntu"}},
+			ProvisioningConfig: &OSPFragmentConfig{
+				Templates: map[string]string{"caScript": "update-ca-certificates"},
+				Units:     []Unit{{Name: "ca.service", Enable: ptr.To(true)}},
+				Files:     []File{{Path: "/etc/pki/custom-ca.crt", Permissions: 644, Content: FileContent{Inline: &FileContentInline{Data: "{{ .caScript }}"}}}},
+				CloudInitModules: &CloudInitModule{
+					RunCMD: []string{"update-ca-certificates"},
+				},
+			},
+		},
+	}
+
+	hub := &v1beta1.OperatingSystemProfileFragment{}
+	if err := fragment.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if hub.Spec.BootstrapConfig != nil {
+		t.Errorf("expected no bootstrap config, got %+v", hub.Spec.BootstrapConfig)
+	}
+	if hub.Spec.ProvisioningConfig.Files[0].Permissions != "0644" {
+		t.Errorf("expected permissions 0644, got %q", hub.Spec.ProvisioningConfig.Files[0].Permissions)
+	}
+
+	converted := &OperatingSystemProfileFragment{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(converted, fragment); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemProfileFragment: %v", diff)
+	}
+
+	// Modules that v1alpha1 can't represent survive a round trip through v1alpha1.
+	hub.Spec.ProvisioningConfig.CloudInitModules["snap"] = apiextensionsv1.JSON{Raw: []byte(`{"commands":["snap install jq"]}`)}
+	spoke := &OperatingSystemProfileFragment{}
+	if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	roundTripped := &v1beta1.OperatingSystemProfileFragment{}
+	if err := spoke.ConvertTo(roundTripped); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(roundTripped, hub); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemProfileFragment: %v", diff)
+	}
+}
+
+func TestOperatingSystemConfigOverrideConversion(t *testing.T) {
+	override := &OperatingSystemConfigOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "kube-system"},
+		Spec: OperatingSystemConfigOverrideSpec{
+			MachineDeployments:        []string{"gpu-workers"},
+			MachineDeploymentSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+			ProvisioningConfig: &OSCOverrideConfig{
+				Units:       []Unit{{Name: "nvidia.service", Enable: ptr.To(true)}},
+				Files:       []File{{Path: "/etc/modprobe.d/nvidia.conf", Permissions: 644, Content: FileContent{Inline: &FileContentInline{Data: "options nvidia"}}}},
+				UserSSHKeys: []string{"ssh-ed25519 AAAA"},
+				CloudInitModules: &CloudInitModule{
+					RunCMD: []string{"modprobe nvidia"},
+				},
+				Packages: &Packages{
+					Packages: []Package{{Name: "nvidia-driver"}},
+				},
+			},
+		},
+	}
+
+	hub := &v1beta1.OperatingSystemConfigOverride{}
+	if err := override.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if hub.Spec.ProvisioningConfig.Files[0].Permissions != "0644" {
+		t.Errorf("expected permissions 0644, got %q", hub.Spec.ProvisioningConfig.Files[0].Permissions)
+	}
+
+	converted := &OperatingSystemConfigOverride{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(converted, override); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemConfigOverride: %v", diff)
+	}
+
+	// Modules that v1alpha1 can't represent survive a round trip through v1alpha1.
+	hub.Spec.ProvisioningConfig.CloudInitModules["snap"] = apiextensionsv1.JSON{Raw: []byte(`{"commands":["snap install jq"]}`)}
+	spoke := &OperatingSystemConfigOverride{}
+	if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	roundTripped := &v1beta1.OperatingSystemConfigOverride{}
+	if err := spoke.ConvertTo(roundTripped); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(roundTripped, hub); diff != nil {
+		t.Errorf("round trip changed the OperatingSystemConfigOverride: %v", diff)
+	}
+}
+
+func TestTemplateLibraryConversion(t *testing.T) {
+	library := &TemplateLibrary{
+		ObjectMeta: metav1.ObjectMeta{Name: "common", Namespace: "kube-system"},
+		Spec: TemplateLibrarySpec{
+			Version:   "v1.0.0",
+			Templates: map[string]string{"greeting": "hello from the library"},
+		},
+	}
+
+	hub := &v1beta1.TemplateLibrary{}
+	if err := library.DeepCopy().ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+
+	converted := &TemplateLibrary{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from v1beta1: %v", err)
+	}
+
+	if diff := deep.Equal(converted, library); diff != nil {
+		t.Errorf("round trip changed the TemplateLibrary: %v", diff)
+	}
+}
+
+func TestConvertCloudInitModulesToRestoresLostModules(t *testing.T) {
+	modules, err := convertCloudInitModulesTo(nil, map[string]apiextensionsv1.JSON{"ntp": {Raw: []byte(`{}`)}})
+	if err != nil {
+		t.Fatalf("failed to convert modules: %v", err)
+	}
+
+	if diff := deep.Equal(modules, v1beta1.CloudInitModules{"ntp": {Raw: []byte(`{}`)}}); diff != nil {
+		t.Errorf("unexpected modules: %v", diff)
+	}
+}
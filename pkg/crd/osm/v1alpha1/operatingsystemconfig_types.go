@@ -28,9 +28,20 @@ const (
 	OperatingSystemConfigKindName = "OperatingSystemConfig"
 )
 
+const (
+	// OperatingSystemConfigRendered indicates that the OperatingSystemConfig was rendered from its OperatingSystemProfile.
+	OperatingSystemConfigRendered = "Rendered"
+	// OperatingSystemConfigSecretsSynced indicates that the cloud-config secrets were generated from the OperatingSystemConfig.
+	OperatingSystemConfigSecretsSynced = "SecretsSynced"
+	// OperatingSystemConfigRenderFailed indicates that rendering an updated OperatingSystemConfig failed. The existing
+	// OperatingSystemConfig is kept until rendering succeeds.
+	OperatingSystemConfigRenderFailed = "RenderFailed"
+)
+
 //+genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:shortName=osc
+// +kubebuilder:subresource:status
 
 // OperatingSystemConfig is the object that represents the OperatingSystemConfig
 type OperatingSystemConfig struct {
@@ -39,6 +50,9 @@ type OperatingSystemConfig struct {
 
 	// OperatingSystemConfigSpec represents the operating system configuration spec.
 	Spec OperatingSystemConfigSpec `json:"spec"`
+	// Status represents the observed state of the operating system configuration.
+	// +optional
+	Status OperatingSystemConfigStatus `json:"status,omitempty"`
 }
 
 // OperatingSystemConfigSpec represents the data in the newly created OperatingSystemConfig
@@ -58,13 +72,67 @@ type OperatingSystemConfigSpec struct {
 	ProvisioningUtility ProvisioningUtility `json:"provisioningUtility,omitempty"`
 }
 
+// OperatingSystemConfigStatus represents the observed state of an OperatingSystemConfig
+type OperatingSystemConfigStatus struct {
+	// ObservedGeneration is the most recent generation of the OperatingSystemConfig observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// OperatingSystemProfile is the name of the OperatingSystemProfile the config was rendered from.
+	OperatingSystemProfile string `json:"operatingSystemProfile,omitempty"`
+	// OperatingSystemProfileVersion is the version of the OperatingSystemProfile the config was rendered from.
+	OperatingSystemProfileVersion string `json:"operatingSystemProfileVersion,omitempty"`
+	// MachineDeploymentRevision is the revision of the MachineDeployment the config was rendered for.
+	MachineDeploymentRevision string `json:"machineDeploymentRevision,omitempty"`
+	// MachineDeploymentAnnotationsHash is the hash of the MachineDeployment annotations the config was rendered with.
+	MachineDeploymentAnnotationsHash string `json:"machineDeploymentAnnotationsHash,omitempty"`
+	// CloudConfigSecrets are the cloud-config secrets generated from the config.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	CloudConfigSecrets []CloudConfigSecretStatus `json:"cloudConfigSecrets,omitempty"`
+	// Conditions represent the latest observations of the config's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CloudConfigSecretStatus describes a generated cloud-config secret
+type CloudConfigSecretStatus struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+	// Namespace is the namespace of the secret in the worker cluster.
+	Namespace string `json:"namespace"`
+	// Type is the type of the cloud-config e.g: bootstrap or provisioning
+	Type string `json:"type"`
+	// ContentHash is the hash of the secret's data.
+	ContentHash string `json:"contentHash"`
+}
+
 type OSCConfig struct {
 	// Units a list of the systemd unit files which will run on the instance
 	Units []Unit `json:"units,omitempty"`
 	// Files is a list of files that should exist in the instance
 	Files []File `json:"files,omitempty"`
+	// Directories is a list of directories that should exist in the instance
+	// +optional
+	Directories []Directory `json:"directories,omitempty"`
+	// Links is a list of links that should exist in the instance
+	// +optional
+	Links []Link `json:"links,omitempty"`
+	// Storage describes the disks and filesystems of the instance
+	// +optional
+	Storage *Storage `json:"storage,omitempty"`
+	// Packages are the package repositories and packages of the instance
+	// +optional
+	Packages *Packages `json:"packages,omitempty"`
 	// UserSSHKeys is a list of attached user ssh keys
 	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
+	// Users is a list of users that are created on the instance in addition to the default user
+	// +optional
+	Users []User `json:"users,omitempty"`
+	// Groups is a list of groups that are created on the instance
+	// +optional
+	Groups []Group `json:"groups,omitempty"`
 	// CloudInitModules contains the supported cloud-init modules
 	// +optional
 	CloudInitModules *CloudInitModule `json:"modules,omitempty"`
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperatingSystemConfigOverrideResourceName represents "Resource" defined in Kubernetes
+	OperatingSystemConfigOverrideResourceName = "operatingsystemconfigoverrides"
+
+	// OperatingSystemConfigOverrideKindName represents "Kind" defined in Kubernetes
+	OperatingSystemConfigOverrideKindName = "OperatingSystemConfigOverride"
+)
+
+//+genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=osco
+
+// OperatingSystemConfigOverride is the object that represents additional configuration that is added to the
+// OperatingSystemConfigs of the matching MachineDeployments. It must be created in the namespace of the
+// OperatingSystemConfigs.
+type OperatingSystemConfigOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// OperatingSystemConfigOverrideSpec represents the operating system config override spec.
+	Spec OperatingSystemConfigOverrideSpec `json:"spec"`
+}
+
+// OperatingSystemConfigOverrideSpec represents the data in the newly created OperatingSystemConfigOverride
+// +kubebuilder:validation:XValidation:rule="has(self.machineDeployments) || has(self.machineDeploymentSelector)",message="at least one of machineDeployments and machineDeploymentSelector must be set"
+type OperatingSystemConfigOverrideSpec struct {
+	// MachineDeployments are the MachineDeployments that the override applies to as namespace/name. Names without a
+	// namespace refer to MachineDeployments in the namespace of the override.
+	// +optional
+	MachineDeployments []string `json:"machineDeployments,omitempty"`
+	// MachineDeploymentSelector selects the MachineDeployments that the override applies to by their labels. An empty
+	// selector matches all MachineDeployments.
+	// The override applies to a MachineDeployment if it's either listed by name or matches the selector.
+	// +optional
+	MachineDeploymentSelector *metav1.LabelSelector `json:"machineDeploymentSelector,omitempty"`
+	// BootstrapConfig is added to the bootstrap config of the matching OperatingSystemConfigs.
+	// +optional
+	BootstrapConfig *OSCOverrideConfig `json:"bootstrapConfig,omitempty"`
+	// ProvisioningConfig is added to the provisioning config of the matching OperatingSystemConfigs.
+	// +optional
+	ProvisioningConfig *OSCOverrideConfig `json:"provisioningConfig,omitempty"`
+}
+
+// OSCOverrideConfig contains the configuration that is added to an OperatingSystemConfig config. Files and units replace
+// the entries of the OperatingSystemConfig with the same path or name, and are added otherwise.
+type OSCOverrideConfig struct {
+	// Units a list of the systemd unit files which will run on the instance
+	Units []Unit `json:"units,omitempty"`
+	// Files is a list of files that should exist in the instance. Files are not rendered as templates and their
+	// content must be inline.
+	// +kubebuilder:validation:items:XValidation:rule="has(self.content.inline)",message="the content of override files must be inline"
+	Files []File `json:"files,omitempty"`
+	// UserSSHKeys is a list of additional attached user ssh keys
+	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
+	// CloudInitModules are merged into the cloud-init modules of the OperatingSystemConfig. Commands are appended,
+	// repositories and subscription settings are added or replaced by name.
+	// +optional
+	CloudInitModules *CloudInitModule `json:"modules,omitempty"`
+	// Packages are merged into the packages of the OperatingSystemConfig. Repositories and packages are added or
+	// replaced by name.
+	// +optional
+	Packages *Packages `json:"packages,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatingSystemConfigOverrideList is a list of OperatingSystemConfigOverrides
+type OperatingSystemConfigOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []OperatingSystemConfigOverride `json:"items"`
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,6 +32,7 @@ const (
 //+genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:shortName=osp
+// +kubebuilder:subresource:status
 
 // OperatingSystemProfile is the object that represents the OperatingSystemProfile
 type OperatingSystemProfile struct {
@@ -39,6 +41,34 @@ type OperatingSystemProfile struct {
 
 	// OperatingSystemProfileSpec represents the operating system configuration spec.
 	Spec OperatingSystemProfileSpec `json:"spec"`
+	// Status represents the observed state of the operating system profile.
+	// +optional
+	Status OperatingSystemProfileStatus `json:"status,omitempty"`
+}
+
+// OperatingSystemProfileStatus represents the observed state of an OperatingSystemProfile
+type OperatingSystemProfileStatus struct {
+	// MachineDeployments are the machine deployments that reference the OperatingSystemProfile.
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	// +listMapKey=name
+	MachineDeployments []OperatingSystemProfileMachineDeployment `json:"machineDeployments,omitempty"`
+}
+
+// OperatingSystemProfileMachineDeployment describes a machine deployment that references an OperatingSystemProfile
+type OperatingSystemProfileMachineDeployment struct {
+	// Namespace is the namespace of the machine deployment.
+	Namespace string `json:"namespace"`
+	// Name is the name of the machine deployment.
+	Name string `json:"name"`
+	// RenderedVersion is the version of the OperatingSystemProfile that the OperatingSystemConfig of the machine deployment
+	// was rendered with. It's empty if no OperatingSystemConfig was rendered yet.
+	// +optional
+	RenderedVersion string `json:"renderedVersion,omitempty"`
+	// RenderError is the error that occurred while rendering the OperatingSystemConfig for the machine deployment.
+	// +optional
+	RenderError string `json:"renderError,omitempty"`
 }
 
 // OperatingSystemProfileSpec represents the data in the newly created OperatingSystemProfile
@@ -59,6 +89,50 @@ type OperatingSystemProfileSpec struct {
 	// ProvisioningUtility used for configuring the worker node. Defaults to cloud-init.
 	// +kubebuilder:default=cloud-init
 	ProvisioningUtility ProvisioningUtility `json:"provisioningUtility,omitempty"`
+
+	// Extends is the name of a base OperatingSystemProfile in the same namespace. The configs of this profile are
+	// overlaid onto the configs of the base profile: files, units, templates and container runtimes replace the
+	// entries of the base profile with the same path or name, and are added otherwise. Parameters are overlaid by
+	// name the same way. Cloud-init modules replace the modules of the base profile. The operating system version and
+	// supported cloud providers are inherited from the base profile if they're left empty. The operating system and
+	// provisioning utility have to match the base profile.
+	// +optional
+	Extends string `json:"extends,omitempty"`
+
+	// Parameters are the values of the profile that machine deployments can set. Machine deployments set them with
+	// the k8c.io/osp-parameters annotation or a config map referenced by the k8c.io/osp-parameters-configmap
+	// annotation. The values are available to the templates of files as .Params.
+	// +optional
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// ParameterType is the type of the value of a parameter.
+// +kubebuilder:validation:Enum=string;integer;number;boolean
+type ParameterType string
+
+const (
+	ParameterTypeString  ParameterType = "string"
+	ParameterTypeInteger ParameterType = "integer"
+	ParameterTypeNumber  ParameterType = "number"
+	ParameterTypeBoolean ParameterType = "boolean"
+)
+
+// Parameter declares a value of an OperatingSystemProfile that is set per machine deployment.
+type Parameter struct {
+	// Name is the name of the parameter. The value is available to templates as .Params.<name>.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+	// Description describes the parameter.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Type is the type of the value.
+	Type ParameterType `json:"type"`
+	// Default is used if the machine deployment doesn't set a value. It must be of the type of the parameter.
+	// +optional
+	Default *apiextensionsv1.JSON `json:"default,omitempty"`
+	// Required parameters must have a value, either from the machine deployment or the default.
+	// +optional
+	Required bool `json:"required,omitempty"`
 }
 
 type OSPConfig struct {
@@ -67,13 +141,59 @@ type OSPConfig struct {
 	SupportedContainerRuntimes []ContainerRuntimeSpec `json:"supportedContainerRuntimes,omitempty"`
 	// Templates to be included in units and files
 	Templates map[string]string `json:"templates,omitempty"`
+	// TemplateLibraries are the names of the TemplateLibraries in the namespace of the profile whose templates are
+	// included in units and files. Templates of the config take precedence over the ones of the libraries, and
+	// libraries take precedence over the ones listed before them.
+	// +optional
+	TemplateLibraries []string `json:"templateLibraries,omitempty"`
 	// Units a list of the systemd unit files which will run on the instance
 	Units []Unit `json:"units,omitempty"`
 	// Files is a list of files that should exist in the instance
 	Files []File `json:"files,omitempty"`
+	// Directories is a list of directories that should exist in the instance
+	// +optional
+	Directories []Directory `json:"directories,omitempty"`
+	// Links is a list of links that should exist in the instance
+	// +optional
+	Links []Link `json:"links,omitempty"`
+	// Storage describes the disks and filesystems of the instance
+	// +optional
+	Storage *Storage `json:"storage,omitempty"`
+	// Packages are the package repositories and packages of the instance
+	// +optional
+	Packages *Packages `json:"packages,omitempty"`
+	// NodeTuning configures kernel parameters, kernel modules and huge pages of the instance
+	// +optional
+	NodeTuning *NodeTuning `json:"nodeTuning,omitempty"`
+	// Users is a list of users that are created on the instance in addition to the default user
+	// +optional
+	Users []User `json:"users,omitempty"`
+	// Groups is a list of groups that are created on the instance
+	// +optional
+	Groups []Group `json:"groups,omitempty"`
 	// CloudInitModules field contains the optional cloud-init modules which are supported by OSM
 	// +optional
 	CloudInitModules *CloudInitModule `json:"modules,omitempty"`
+	// Remove lists the entries of the base OperatingSystemProfile that are removed from this config. It's only
+	// supported if the profile extends another profile.
+	// +optional
+	Remove *OSPConfigRemovals `json:"remove,omitempty"`
+}
+
+// OSPConfigRemovals identifies the entries of a base OperatingSystemProfile config that are removed by an extending profile.
+type OSPConfigRemovals struct {
+	// Files are the paths of the removed files.
+	// +optional
+	Files []string `json:"files,omitempty"`
+	// Units are the names of the removed units.
+	// +optional
+	Units []string `json:"units,omitempty"`
+	// Templates are the names of the removed templates.
+	// +optional
+	Templates []string `json:"templates,omitempty"`
+	// ContainerRuntimes are the names of the removed container runtimes.
+	// +optional
+	ContainerRuntimes []ContainerRuntime `json:"containerRuntimes,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
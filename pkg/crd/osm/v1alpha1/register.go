@@ -50,6 +50,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&OperatingSystemProfileList{},
 		&OperatingSystemConfig{},
 		&OperatingSystemConfigList{},
+		&OperatingSystemProfileFragment{},
+		&OperatingSystemProfileFragmentList{},
+		&OperatingSystemConfigOverride{},
+		&OperatingSystemConfigOverrideList{},
+		&TemplateLibrary{},
+		&TemplateLibraryList{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
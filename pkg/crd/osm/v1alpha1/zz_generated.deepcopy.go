@@ -21,9 +21,120 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudConfigSecretStatus) DeepCopyInto(out *CloudConfigSecretStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudConfigSecretStatus.
+func (in *CloudConfigSecretStatus) DeepCopy() *CloudConfigSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudConfigSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitApt) DeepCopyInto(out *CloudInitApt) {
+	*out = *in
+	if in.PreserveSourcesList != nil {
+		in, out := &in.PreserveSourcesList, &out.PreserveSourcesList
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Primary != nil {
+		in, out := &in.Primary, &out.Primary
+		*out = make([]CloudInitAptMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make(map[string]CloudInitAptSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitApt.
+func (in *CloudInitApt) DeepCopy() *CloudInitApt {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitApt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitAptMirror) DeepCopyInto(out *CloudInitAptMirror) {
+	*out = *in
+	if in.Arches != nil {
+		in, out := &in.Arches, &out.Arches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Search != nil {
+		in, out := &in.Search, &out.Search
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitAptMirror.
+func (in *CloudInitAptMirror) DeepCopy() *CloudInitAptMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitAptMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitAptSource) DeepCopyInto(out *CloudInitAptSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitAptSource.
+func (in *CloudInitAptSource) DeepCopy() *CloudInitAptSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitAptSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitCACerts) DeepCopyInto(out *CloudInitCACerts) {
+	*out = *in
+	if in.Trusted != nil {
+		in, out := &in.Trusted, &out.Trusted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitCACerts.
+func (in *CloudInitCACerts) DeepCopy() *CloudInitCACerts {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitCACerts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudInitModule) DeepCopyInto(out *CloudInitModule) {
 	*out = *in
@@ -62,6 +173,47 @@ func (in *CloudInitModule) DeepCopyInto(out *CloudInitModule) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Apt != nil {
+		in, out := &in.Apt, &out.Apt
+		*out = new(CloudInitApt)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NTP != nil {
+		in, out := &in.NTP, &out.NTP
+		*out = new(CloudInitNTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACerts != nil {
+		in, out := &in.CACerts, &out.CACerts
+		*out = new(CloudInitCACerts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mounts != nil {
+		in, out := &in.Mounts, &out.Mounts
+		*out = make([][]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+	if in.PowerState != nil {
+		in, out := &in.PowerState, &out.PowerState
+		*out = new(CloudInitPowerState)
+		**out = **in
+	}
+	if in.WriteFiles != nil {
+		in, out := &in.WriteFiles, &out.WriteFiles
+		*out = make([]CloudInitWriteFile, len(*in))
+		copy(*out, *in)
+	}
+	if in.PhoneHome != nil {
+		in, out := &in.PhoneHome, &out.PhoneHome
+		*out = new(CloudInitPhoneHome)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitModule.
@@ -75,299 +227,1244 @@ func (in *CloudInitModule) DeepCopy() *CloudInitModule {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CloudProviderSpec) DeepCopyInto(out *CloudProviderSpec) {
+func (in *CloudInitNTP) DeepCopyInto(out *CloudInitNTP) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitNTP.
+func (in *CloudInitNTP) DeepCopy() *CloudInitNTP {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitNTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitPhoneHome) DeepCopyInto(out *CloudInitPhoneHome) {
+	*out = *in
+	if in.Post != nil {
+		in, out := &in.Post, &out.Post
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitPhoneHome.
+func (in *CloudInitPhoneHome) DeepCopy() *CloudInitPhoneHome {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitPhoneHome)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitPowerState) DeepCopyInto(out *CloudInitPowerState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitPowerState.
+func (in *CloudInitPowerState) DeepCopy() *CloudInitPowerState {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitPowerState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudInitWriteFile) DeepCopyInto(out *CloudInitWriteFile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudInitWriteFile.
+func (in *CloudInitWriteFile) DeepCopy() *CloudInitWriteFile {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudInitWriteFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudProviderSpec) DeepCopyInto(out *CloudProviderSpec) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderSpec.
+func (in *CloudProviderSpec) DeepCopy() *CloudProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRuntimeSpec) DeepCopyInto(out *ContainerRuntimeSpec) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRuntimeSpec.
+func (in *ContainerRuntimeSpec) DeepCopy() *ContainerRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Directory) DeepCopyInto(out *Directory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Directory.
+func (in *Directory) DeepCopy() *Directory {
+	if in == nil {
+		return nil
+	}
+	out := new(Directory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Disk) DeepCopyInto(out *Disk) {
+	*out = *in
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]Partition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Disk.
+func (in *Disk) DeepCopy() *Disk {
+	if in == nil {
+		return nil
+	}
+	out := new(Disk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DropIn) DeepCopyInto(out *DropIn) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DropIn.
+func (in *DropIn) DeepCopy() *DropIn {
+	if in == nil {
+		return nil
+	}
+	out := new(DropIn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *File) DeepCopyInto(out *File) {
+	*out = *in
+	in.Content.DeepCopyInto(&out.Content)
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(Selector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new File.
+func (in *File) DeepCopy() *File {
+	if in == nil {
+		return nil
+	}
+	out := new(File)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileContent) DeepCopyInto(out *FileContent) {
+	*out = *in
+	if in.Inline != nil {
+		in, out := &in.Inline, &out.Inline
+		*out = new(FileContentInline)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(FileContentReference)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(FileContentReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileContent.
+func (in *FileContent) DeepCopy() *FileContent {
+	if in == nil {
+		return nil
+	}
+	out := new(FileContent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileContentInline) DeepCopyInto(out *FileContentInline) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileContentInline.
+func (in *FileContentInline) DeepCopy() *FileContentInline {
+	if in == nil {
+		return nil
+	}
+	out := new(FileContentInline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileContentReference) DeepCopyInto(out *FileContentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileContentReference.
+func (in *FileContentReference) DeepCopy() *FileContentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(FileContentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filesystem) DeepCopyInto(out *Filesystem) {
+	*out = *in
+	if in.MountOptions != nil {
+		in, out := &in.MountOptions, &out.MountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filesystem.
+func (in *Filesystem) DeepCopy() *Filesystem {
+	if in == nil {
+		return nil
+	}
+	out := new(Filesystem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Group) DeepCopyInto(out *Group) {
+	*out = *in
+	if in.GID != nil {
+		in, out := &in.GID, &out.GID
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Group.
+func (in *Group) DeepCopy() *Group {
+	if in == nil {
+		return nil
+	}
+	out := new(Group)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugePages) DeepCopyInto(out *HugePages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePages.
+func (in *HugePages) DeepCopy() *HugePages {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Link) DeepCopyInto(out *Link) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Link.
+func (in *Link) DeepCopy() *Link {
+	if in == nil {
+		return nil
+	}
+	out := new(Link)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTuning) DeepCopyInto(out *NodeTuning) {
+	*out = *in
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KernelModules != nil {
+		in, out := &in.KernelModules, &out.KernelModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = make([]HugePages, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTuning.
+func (in *NodeTuning) DeepCopy() *NodeTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSCConfig) DeepCopyInto(out *OSCConfig) {
+	*out = *in
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]Unit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]Directory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]Link, len(*in))
+		copy(*out, *in)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(Storage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(Packages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserSSHKeys != nil {
+		in, out := &in.UserSSHKeys, &out.UserSSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]User, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]Group, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CloudInitModules != nil {
+		in, out := &in.CloudInitModules, &out.CloudInitModules
+		*out = new(CloudInitModule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSCConfig.
+func (in *OSCConfig) DeepCopy() *OSCConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OSCConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSCOverrideConfig) DeepCopyInto(out *OSCOverrideConfig) {
+	*out = *in
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]Unit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UserSSHKeys != nil {
+		in, out := &in.UserSSHKeys, &out.UserSSHKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CloudInitModules != nil {
+		in, out := &in.CloudInitModules, &out.CloudInitModules
+		*out = new(CloudInitModule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(Packages)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSCOverrideConfig.
+func (in *OSCOverrideConfig) DeepCopy() *OSCOverrideConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OSCOverrideConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPConfig) DeepCopyInto(out *OSPConfig) {
+	*out = *in
+	if in.SupportedContainerRuntimes != nil {
+		in, out := &in.SupportedContainerRuntimes, &out.SupportedContainerRuntimes
+		*out = make([]ContainerRuntimeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TemplateLibraries != nil {
+		in, out := &in.TemplateLibraries, &out.TemplateLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]Unit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Directories != nil {
+		in, out := &in.Directories, &out.Directories
+		*out = make([]Directory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]Link, len(*in))
+		copy(*out, *in)
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(Storage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = new(Packages)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeTuning != nil {
+		in, out := &in.NodeTuning, &out.NodeTuning
+		*out = new(NodeTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]User, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]Group, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CloudInitModules != nil {
+		in, out := &in.CloudInitModules, &out.CloudInitModules
+		*out = new(CloudInitModule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = new(OSPConfigRemovals)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPConfig.
+func (in *OSPConfig) DeepCopy() *OSPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPConfigRemovals) DeepCopyInto(out *OSPConfigRemovals) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerRuntimes != nil {
+		in, out := &in.ContainerRuntimes, &out.ContainerRuntimes
+		*out = make([]ContainerRuntime, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPConfigRemovals.
+func (in *OSPConfigRemovals) DeepCopy() *OSPConfigRemovals {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPConfigRemovals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OSPFragmentConfig) DeepCopyInto(out *OSPFragmentConfig) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]Unit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]File, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CloudInitModules != nil {
+		in, out := &in.CloudInitModules, &out.CloudInitModules
+		*out = new(CloudInitModule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPFragmentConfig.
+func (in *OSPFragmentConfig) DeepCopy() *OSPFragmentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OSPFragmentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfig) DeepCopyInto(out *OperatingSystemConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfig.
+func (in *OperatingSystemConfig) DeepCopy() *OperatingSystemConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfigList) DeepCopyInto(out *OperatingSystemConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatingSystemConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigList.
+func (in *OperatingSystemConfigList) DeepCopy() *OperatingSystemConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfigOverride) DeepCopyInto(out *OperatingSystemConfigOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigOverride.
+func (in *OperatingSystemConfigOverride) DeepCopy() *OperatingSystemConfigOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfigOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemConfigOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfigOverrideList) DeepCopyInto(out *OperatingSystemConfigOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatingSystemConfigOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigOverrideList.
+func (in *OperatingSystemConfigOverrideList) DeepCopy() *OperatingSystemConfigOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfigOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemConfigOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfigOverrideSpec) DeepCopyInto(out *OperatingSystemConfigOverrideSpec) {
+	*out = *in
+	if in.MachineDeployments != nil {
+		in, out := &in.MachineDeployments, &out.MachineDeployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MachineDeploymentSelector != nil {
+		in, out := &in.MachineDeploymentSelector, &out.MachineDeploymentSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapConfig != nil {
+		in, out := &in.BootstrapConfig, &out.BootstrapConfig
+		*out = new(OSCOverrideConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningConfig != nil {
+		in, out := &in.ProvisioningConfig, &out.ProvisioningConfig
+		*out = new(OSCOverrideConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigOverrideSpec.
+func (in *OperatingSystemConfigOverrideSpec) DeepCopy() *OperatingSystemConfigOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfigOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfigSpec) DeepCopyInto(out *OperatingSystemConfigSpec) {
+	*out = *in
+	in.CloudProvider.DeepCopyInto(&out.CloudProvider)
+	in.BootstrapConfig.DeepCopyInto(&out.BootstrapConfig)
+	in.ProvisioningConfig.DeepCopyInto(&out.ProvisioningConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigSpec.
+func (in *OperatingSystemConfigSpec) DeepCopy() *OperatingSystemConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemConfigStatus) DeepCopyInto(out *OperatingSystemConfigStatus) {
+	*out = *in
+	if in.CloudConfigSecrets != nil {
+		in, out := &in.CloudConfigSecrets, &out.CloudConfigSecrets
+		*out = make([]CloudConfigSecretStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigStatus.
+func (in *OperatingSystemConfigStatus) DeepCopy() *OperatingSystemConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemProfile) DeepCopyInto(out *OperatingSystemProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfile.
+func (in *OperatingSystemProfile) DeepCopy() *OperatingSystemProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemProfileFragment) DeepCopyInto(out *OperatingSystemProfileFragment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileFragment.
+func (in *OperatingSystemProfileFragment) DeepCopy() *OperatingSystemProfileFragment {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemProfileFragment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemProfileFragment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemProfileFragmentList) DeepCopyInto(out *OperatingSystemProfileFragmentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatingSystemProfileFragment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileFragmentList.
+func (in *OperatingSystemProfileFragmentList) DeepCopy() *OperatingSystemProfileFragmentList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatingSystemProfileFragmentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemProfileFragmentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatingSystemProfileFragmentSpec) DeepCopyInto(out *OperatingSystemProfileFragmentSpec) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.OperatingSystemProfileSelector != nil {
+		in, out := &in.OperatingSystemProfileSelector, &out.OperatingSystemProfileSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineDeploymentSelector != nil {
+		in, out := &in.MachineDeploymentSelector, &out.MachineDeploymentSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BootstrapConfig != nil {
+		in, out := &in.BootstrapConfig, &out.BootstrapConfig
+		*out = new(OSPFragmentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningConfig != nil {
+		in, out := &in.ProvisioningConfig, &out.ProvisioningConfig
+		*out = new(OSPFragmentConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudProviderSpec.
-func (in *CloudProviderSpec) DeepCopy() *CloudProviderSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileFragmentSpec.
+func (in *OperatingSystemProfileFragmentSpec) DeepCopy() *OperatingSystemProfileFragmentSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CloudProviderSpec)
+	out := new(OperatingSystemProfileFragmentSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerRuntimeSpec) DeepCopyInto(out *ContainerRuntimeSpec) {
+func (in *OperatingSystemProfileList) DeepCopyInto(out *OperatingSystemProfileList) {
 	*out = *in
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = make([]File, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatingSystemProfile, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRuntimeSpec.
-func (in *ContainerRuntimeSpec) DeepCopy() *ContainerRuntimeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileList.
+func (in *OperatingSystemProfileList) DeepCopy() *OperatingSystemProfileList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerRuntimeSpec)
+	out := new(OperatingSystemProfileList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatingSystemProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DropIn) DeepCopyInto(out *DropIn) {
+func (in *OperatingSystemProfileMachineDeployment) DeepCopyInto(out *OperatingSystemProfileMachineDeployment) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DropIn.
-func (in *DropIn) DeepCopy() *DropIn {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileMachineDeployment.
+func (in *OperatingSystemProfileMachineDeployment) DeepCopy() *OperatingSystemProfileMachineDeployment {
 	if in == nil {
 		return nil
 	}
-	out := new(DropIn)
+	out := new(OperatingSystemProfileMachineDeployment)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *File) DeepCopyInto(out *File) {
+func (in *OperatingSystemProfileSpec) DeepCopyInto(out *OperatingSystemProfileSpec) {
 	*out = *in
-	in.Content.DeepCopyInto(&out.Content)
+	if in.SupportedCloudProviders != nil {
+		in, out := &in.SupportedCloudProviders, &out.SupportedCloudProviders
+		*out = make([]CloudProviderSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.BootstrapConfig.DeepCopyInto(&out.BootstrapConfig)
+	in.ProvisioningConfig.DeepCopyInto(&out.ProvisioningConfig)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new File.
-func (in *File) DeepCopy() *File {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileSpec.
+func (in *OperatingSystemProfileSpec) DeepCopy() *OperatingSystemProfileSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(File)
+	out := new(OperatingSystemProfileSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FileContent) DeepCopyInto(out *FileContent) {
+func (in *OperatingSystemProfileStatus) DeepCopyInto(out *OperatingSystemProfileStatus) {
 	*out = *in
-	if in.Inline != nil {
-		in, out := &in.Inline, &out.Inline
-		*out = new(FileContentInline)
-		**out = **in
+	if in.MachineDeployments != nil {
+		in, out := &in.MachineDeployments, &out.MachineDeployments
+		*out = make([]OperatingSystemProfileMachineDeployment, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileContent.
-func (in *FileContent) DeepCopy() *FileContent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileStatus.
+func (in *OperatingSystemProfileStatus) DeepCopy() *OperatingSystemProfileStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FileContent)
+	out := new(OperatingSystemProfileStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FileContentInline) DeepCopyInto(out *FileContentInline) {
+func (in *Package) DeepCopyInto(out *Package) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileContentInline.
-func (in *FileContentInline) DeepCopy() *FileContentInline {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Package.
+func (in *Package) DeepCopy() *Package {
 	if in == nil {
 		return nil
 	}
-	out := new(FileContentInline)
+	out := new(Package)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSCConfig) DeepCopyInto(out *OSCConfig) {
+func (in *PackageRepository) DeepCopyInto(out *PackageRepository) {
 	*out = *in
-	if in.Units != nil {
-		in, out := &in.Units, &out.Units
-		*out = make([]Unit, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = make([]File, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.UserSSHKeys != nil {
-		in, out := &in.UserSSHKeys, &out.UserSSHKeys
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.CloudInitModules != nil {
-		in, out := &in.CloudInitModules, &out.CloudInitModules
-		*out = new(CloudInitModule)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSCConfig.
-func (in *OSCConfig) DeepCopy() *OSCConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRepository.
+func (in *PackageRepository) DeepCopy() *PackageRepository {
 	if in == nil {
 		return nil
 	}
-	out := new(OSCConfig)
+	out := new(PackageRepository)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OSPConfig) DeepCopyInto(out *OSPConfig) {
+func (in *Packages) DeepCopyInto(out *Packages) {
 	*out = *in
-	if in.SupportedContainerRuntimes != nil {
-		in, out := &in.SupportedContainerRuntimes, &out.SupportedContainerRuntimes
-		*out = make([]ContainerRuntimeSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Templates != nil {
-		in, out := &in.Templates, &out.Templates
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Units != nil {
-		in, out := &in.Units, &out.Units
-		*out = make([]Unit, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Files != nil {
-		in, out := &in.Files, &out.Files
-		*out = make([]File, len(*in))
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]PackageRepository, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.CloudInitModules != nil {
-		in, out := &in.CloudInitModules, &out.CloudInitModules
-		*out = new(CloudInitModule)
-		(*in).DeepCopyInto(*out)
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]Package, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OSPConfig.
-func (in *OSPConfig) DeepCopy() *OSPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Packages.
+func (in *Packages) DeepCopy() *Packages {
 	if in == nil {
 		return nil
 	}
-	out := new(OSPConfig)
+	out := new(Packages)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatingSystemConfig) DeepCopyInto(out *OperatingSystemConfig) {
+func (in *Parameter) DeepCopyInto(out *Parameter) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfig.
-func (in *OperatingSystemConfig) DeepCopy() *OperatingSystemConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
+func (in *Parameter) DeepCopy() *Parameter {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatingSystemConfig)
+	out := new(Parameter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperatingSystemConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Partition) DeepCopyInto(out *Partition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Partition.
+func (in *Partition) DeepCopy() *Partition {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(Partition)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatingSystemConfigList) DeepCopyInto(out *OperatingSystemConfigList) {
+func (in *Selector) DeepCopyInto(out *Selector) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]OperatingSystemConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CloudProviders != nil {
+		in, out := &in.CloudProviders, &out.CloudProviders
+		*out = make([]CloudProvider, len(*in))
+		copy(*out, *in)
+	}
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MachineDeploymentSelector != nil {
+		in, out := &in.MachineDeploymentSelector, &out.MachineDeploymentSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigList.
-func (in *OperatingSystemConfigList) DeepCopy() *OperatingSystemConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Selector.
+func (in *Selector) DeepCopy() *Selector {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatingSystemConfigList)
+	out := new(Selector)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperatingSystemConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatingSystemConfigSpec) DeepCopyInto(out *OperatingSystemConfigSpec) {
+func (in *Storage) DeepCopyInto(out *Storage) {
 	*out = *in
-	in.CloudProvider.DeepCopyInto(&out.CloudProvider)
-	in.BootstrapConfig.DeepCopyInto(&out.BootstrapConfig)
-	in.ProvisioningConfig.DeepCopyInto(&out.ProvisioningConfig)
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]Disk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Filesystems != nil {
+		in, out := &in.Filesystems, &out.Filesystems
+		*out = make([]Filesystem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemConfigSpec.
-func (in *OperatingSystemConfigSpec) DeepCopy() *OperatingSystemConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Storage.
+func (in *Storage) DeepCopy() *Storage {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatingSystemConfigSpec)
+	out := new(Storage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatingSystemProfile) DeepCopyInto(out *OperatingSystemProfile) {
+func (in *TemplateLibrary) DeepCopyInto(out *TemplateLibrary) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfile.
-func (in *OperatingSystemProfile) DeepCopy() *OperatingSystemProfile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLibrary.
+func (in *TemplateLibrary) DeepCopy() *TemplateLibrary {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatingSystemProfile)
+	out := new(TemplateLibrary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperatingSystemProfile) DeepCopyObject() runtime.Object {
+func (in *TemplateLibrary) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -375,31 +1472,31 @@ func (in *OperatingSystemProfile) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatingSystemProfileList) DeepCopyInto(out *OperatingSystemProfileList) {
+func (in *TemplateLibraryList) DeepCopyInto(out *TemplateLibraryList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]OperatingSystemProfile, len(*in))
+		*out = make([]TemplateLibrary, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileList.
-func (in *OperatingSystemProfileList) DeepCopy() *OperatingSystemProfileList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLibraryList.
+func (in *TemplateLibraryList) DeepCopy() *TemplateLibraryList {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatingSystemProfileList)
+	out := new(TemplateLibraryList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *OperatingSystemProfileList) DeepCopyObject() runtime.Object {
+func (in *TemplateLibraryList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -407,25 +1504,23 @@ func (in *OperatingSystemProfileList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatingSystemProfileSpec) DeepCopyInto(out *OperatingSystemProfileSpec) {
+func (in *TemplateLibrarySpec) DeepCopyInto(out *TemplateLibrarySpec) {
 	*out = *in
-	if in.SupportedCloudProviders != nil {
-		in, out := &in.SupportedCloudProviders, &out.SupportedCloudProviders
-		*out = make([]CloudProviderSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
-	in.BootstrapConfig.DeepCopyInto(&out.BootstrapConfig)
-	in.ProvisioningConfig.DeepCopyInto(&out.ProvisioningConfig)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatingSystemProfileSpec.
-func (in *OperatingSystemProfileSpec) DeepCopy() *OperatingSystemProfileSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLibrarySpec.
+func (in *TemplateLibrarySpec) DeepCopy() *TemplateLibrarySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatingSystemProfileSpec)
+	out := new(TemplateLibrarySpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -453,6 +1548,11 @@ func (in *Unit) DeepCopyInto(out *Unit) {
 		*out = make([]DropIn, len(*in))
 		copy(*out, *in)
 	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Unit.
@@ -464,3 +1564,38 @@ func (in *Unit) DeepCopy() *Unit {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *User) DeepCopyInto(out *User) {
+	*out = *in
+	if in.UID != nil {
+		in, out := &in.UID, &out.UID
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sudo != nil {
+		in, out := &in.Sudo, &out.Sudo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SSHAuthorizedKeys != nil {
+		in, out := &in.SSHAuthorizedKeys, &out.SSHAuthorizedKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new User.
+func (in *User) DeepCopy() *User {
+	if in == nil {
+		return nil
+	}
+	out := new(User)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,450 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// OperatingSystem represents supported operating system.
+// +kubebuilder:validation:Enum=flatcar;rhel;ubuntu;amzn2;rockylinux
+type OperatingSystem string
+
+const (
+	OperatingSystemFlatcar      OperatingSystem = "flatcar"
+	OperatingSystemRHEL         OperatingSystem = "rhel"
+	OperatingSystemUbuntu       OperatingSystem = "ubuntu"
+	OperatingSystemAmazonLinux2 OperatingSystem = "amzn2"
+	OperatingSystemRockyLinux   OperatingSystem = "rockylinux"
+)
+
+// CloudProvider represents supported cloud provider.
+// +kubebuilder:validation:Enum=aws;azure;digitalocean;edge;gce;hetzner;kubevirt;linode;nutanix;openstack;vsphere;fake;alibaba;anexia;scaleway;baremetal;external;vmware-cloud-director;opennebula
+type CloudProvider string
+
+const (
+	CloudProviderAlibaba             CloudProvider = "alibaba"
+	CloudProviderAnexia              CloudProvider = "anexia"
+	CloudProviderAWS                 CloudProvider = "aws"
+	CloudProviderAzure               CloudProvider = "azure"
+	CloudProviderBaremetal           CloudProvider = "baremetal"
+	CloudProviderDigitalocean        CloudProvider = "digitalocean"
+	CloudProviderEdge                CloudProvider = "edge"
+	CloudProviderExternal            CloudProvider = "external"
+	CloudProviderFake                CloudProvider = "fake"
+	CloudProviderGoogle              CloudProvider = "gce"
+	CloudProviderHetzner             CloudProvider = "hetzner"
+	CloudProviderKubeVirt            CloudProvider = "kubevirt"
+	CloudProviderLinode              CloudProvider = "linode"
+	CloudProviderNutanix             CloudProvider = "nutanix"
+	CloudProviderOpenNebula          CloudProvider = "opennebula"
+	CloudProviderOpenstack           CloudProvider = "openstack"
+	CloudProviderScaleway            CloudProvider = "scaleway"
+	CloudProviderVMwareCloudDirector CloudProvider = "vmware-cloud-director"
+	CloudProviderVsphere             CloudProvider = "vsphere"
+)
+
+// ContainerRuntime represents supported container runtime
+// +kubebuilder:validation:Enum=containerd;crio
+type ContainerRuntime string
+
+const (
+	ContainerRuntimeContainerd ContainerRuntime = "containerd"
+	ContainerRuntimeCRIO       ContainerRuntime = "crio"
+)
+
+// ProvisioningUtility used to provision the machines
+// +kubebuilder:validation:Enum=cloud-init;ignition
+type ProvisioningUtility string
+
+const (
+	ProvisioningUtilityCloudInit ProvisioningUtility = "cloud-init"
+	ProvisioningUtilityIgnition  ProvisioningUtility = "ignition"
+)
+
+// CloudProviderSpec contains the os/image reference for a specific supported cloud provider
+type CloudProviderSpec struct {
+	// Name represents the name of the supported cloud provider
+	Name CloudProvider `json:"name"`
+	// Spec represents the os/image reference in the supported cloud provider
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec runtime.RawExtension `json:"spec,omitempty"`
+}
+
+// Unit is a systemd unit used for the operating system config.
+type Unit struct {
+	// Name is the name of a unit.
+	Name string `json:"name"`
+	// Enable describes whether the unit is enabled or not.
+	Enable *bool `json:"enable,omitempty"`
+	// Mask describes whether the unit is masked or not.
+	Mask *bool `json:"mask,omitempty"`
+	// Content is the unit's content.
+	Content *string `json:"content,omitempty"`
+	// DropIns is a list of drop_ins for this unit.
+	DropIns []DropIn `json:"dropIns,omitempty"`
+	// When restricts the unit to the machines that match the selector.
+	// +optional
+	When *Selector `json:"when,omitempty"`
+}
+
+// Selector restricts a file or unit to the machines that match all of its conditions. Conditions that are left empty
+// match all machines.
+type Selector struct {
+	// CloudProviders are the cloud providers of the machines.
+	// +optional
+	CloudProviders []CloudProvider `json:"cloudProviders,omitempty"`
+	// OSVersions is a semver constraint on the operating system version of the profile, e.g. ">= 22.04".
+	// +optional
+	OSVersions string `json:"osVersions,omitempty"`
+	// KubernetesVersions is a semver constraint on the kubelet version of the machines, e.g. ">= 1.30, < 1.33".
+	// +optional
+	KubernetesVersions string `json:"kubernetesVersions,omitempty"`
+	// Architectures are the CPU architectures of the machines, e.g. amd64 or arm64. The architecture is taken from
+	// the kubernetes.io/arch label of the machine template or of its machine spec. The label is required by this
+	// selector, rendering fails for machine deployments without it.
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+	// MachineDeploymentSelector selects the machine deployments by their labels.
+	// +optional
+	MachineDeploymentSelector *metav1.LabelSelector `json:"machineDeploymentSelector,omitempty"`
+}
+
+// DropIn is a drop-in configuration for a systemd unit.
+type DropIn struct {
+	// Name is the name of the drop-in.
+	Name string `json:"name"`
+	// Content is the content of the drop-in.
+	Content string `json:"content"`
+}
+
+// User is an operating system user that is created on the instance.
+type User struct {
+	// Name is the name of the user.
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_-]*$`
+	Name string `json:"name"`
+	// UID is the user ID of the user. The next free ID is used if it's not set.
+	// +optional
+	UID *int64 `json:"uid,omitempty"`
+	// Groups are the supplementary groups of the user.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// Shell is the login shell of the user.
+	// +optional
+	Shell string `json:"shell,omitempty"`
+	// Sudo is a list of sudo rules for the user, e.g. "ALL=(ALL) NOPASSWD:ALL".
+	// +optional
+	Sudo []string `json:"sudo,omitempty"`
+	// SSHAuthorizedKeys is a list of ssh keys that are authorized to log in as the user.
+	// +optional
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+}
+
+// Group is an operating system group that is created on the instance.
+type Group struct {
+	// Name is the name of the group.
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_-]*$`
+	Name string `json:"name"`
+	// GID is the group ID of the group. It's only supported by ignition, cloud-init always uses the next free ID.
+	// +optional
+	GID *int64 `json:"gid,omitempty"`
+}
+
+// File is a file that should get written to the host's file system. The content can either be inlined or
+// referenced from a secret or a config map.
+type File struct {
+	// Path is the path of the file system where the file should get written to.
+	Path string `json:"path"`
+	// Permissions describes with which permissions the file should get written to the file system.
+	// Must be an octal file mode, e.g. 0644.
+	// +kubebuilder:default="0644"
+	// +kubebuilder:validation:Pattern=`^[0-7]{3,4}$`
+	Permissions string `json:"permissions,omitempty"`
+	// Content describe the file's content.
+	Content FileContent `json:"content"`
+	// Owner is the name of the user that owns the file. Defaults to root.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Group is the name of the group that owns the file. Defaults to root.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// WritePolicy defines how the content is written if the file already exists. Defaults to Overwrite.
+	// +optional
+	WritePolicy FileWritePolicy `json:"writePolicy,omitempty"`
+	// When restricts the file to the machines that match the selector. The file is only rendered for those machines.
+	// +optional
+	When *Selector `json:"when,omitempty"`
+}
+
+// FileWritePolicy defines how the content of a file is written if the file already exists.
+// +kubebuilder:validation:Enum=Overwrite;Append
+type FileWritePolicy string
+
+const (
+	// FileWritePolicyOverwrite replaces the content of an existing file.
+	FileWritePolicyOverwrite FileWritePolicy = "Overwrite"
+	// FileWritePolicyAppend appends the content to an existing file.
+	FileWritePolicyAppend FileWritePolicy = "Append"
+)
+
+// Directory is a directory that should get created on the host's file system.
+type Directory struct {
+	// Path is the path of the directory. Missing parent directories are created as well.
+	Path string `json:"path"`
+	// Permissions describes with which permissions the directory should get created.
+	// Must be an octal file mode, e.g. 0755.
+	// +kubebuilder:default="0755"
+	// +kubebuilder:validation:Pattern=`^[0-7]{3,4}$`
+	Permissions string `json:"permissions,omitempty"`
+	// Owner is the name of the user that owns the directory. Defaults to root.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Group is the name of the group that owns the directory. Defaults to root.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// Link is a link that should get created on the host's file system.
+type Link struct {
+	// Path is the path of the link.
+	Path string `json:"path"`
+	// Target is the path that the link points to.
+	Target string `json:"target"`
+	// Hard creates a hard link instead of a symbolic link.
+	// +optional
+	Hard bool `json:"hard,omitempty"`
+	// Owner is the name of the user that owns the link. Defaults to root.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Group is the name of the group that owns the link. Defaults to root.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// Packages describes the package repositories and packages of the instance. They're rendered for the package manager
+// of the operating system: apt on Ubuntu, yum/dnf on RHEL, Rocky Linux and Amazon Linux, and system extensions on Flatcar.
+type Packages struct {
+	// Repositories are added to the package manager before the packages are installed.
+	// +optional
+	Repositories []PackageRepository `json:"repositories,omitempty"`
+	// Packages are installed on the instance.
+	// +optional
+	Packages []Package `json:"packages,omitempty"`
+}
+
+// PackageRepository is a package repository. Apt uses the URL, distribution and components for the source, yum/dnf
+// uses the URL as base URL and Flatcar downloads system extension images from the URL.
+type PackageRepository struct {
+	// Name of the repository.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`
+	Name string `json:"name"`
+	// URL of the repository.
+	URL string `json:"url"`
+	// Distribution of an apt repository, e.g. jammy.
+	// +optional
+	Distribution string `json:"distribution,omitempty"`
+	// Components of an apt repository, e.g. main.
+	// +optional
+	Components []string `json:"components,omitempty"`
+	// GPGKeyURL is the URL of the key that the repository is signed with.
+	// +optional
+	GPGKeyURL string `json:"gpgKeyURL,omitempty"`
+}
+
+// Package is installed on the instance. On Flatcar it's a system extension image that is downloaded from
+// <repository url>/<name>-<version>.raw, which requires the version, the hash and the repository.
+type Package struct {
+	// Name of the package.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9][a-zA-Z0-9.+_-]*$`
+	Name string `json:"name"`
+	// Version pins the version of the package, e.g. 2.2* for apt or 2.2.* for yum/dnf.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Repository is the name of the repository the package is installed from. Apt installs the package with the
+	// distribution of the repository as target release, yum/dnf prefers the repository through its priority while the
+	// dependencies are resolved from all enabled repositories.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+	// Hash of the system extension image in the form sha512-<hex digest>. Flatcar verifies the downloaded image
+	// against it, the other package managers ignore it.
+	// +kubebuilder:validation:Pattern=`^sha512-[a-f0-9]{128}$`
+	// +optional
+	Hash string `json:"hash,omitempty"`
+	// Hold prevents the package from being upgraded or removed by the package manager.
+	// +optional
+	Hold bool `json:"hold,omitempty"`
+}
+
+// NodeTuning configures kernel parameters, kernel modules and huge pages of the instance.
+// The reserved huge pages are added to the system reservation of the kubelet, the other kubelet reservations are
+// configured with the kubelet configuration annotations of the MachineDeployment.
+type NodeTuning struct {
+	// Sysctls are the kernel parameters that are set on the instance, keyed by their name.
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+	// KernelModules are the kernel modules that are loaded on the instance.
+	// +optional
+	KernelModules []string `json:"kernelModules,omitempty"`
+	// HugePages are the huge pages that are allocated before the kubelet starts. The kubelet detects them as capacity
+	// of the node and removes them from its allocatable memory, it doesn't start if fewer pages could be allocated.
+	// +optional
+	HugePages []HugePages `json:"hugePages,omitempty"`
+	// TransparentHugePages is the transparent huge pages mode of the instance.
+	// +optional
+	TransparentHugePages TransparentHugePagesMode `json:"transparentHugePages,omitempty"`
+}
+
+// HugePages is a number of huge pages of a size.
+type HugePages struct {
+	// Size of the huge pages, e.g. 2Mi or 1Gi.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(Ki|Mi|Gi)$`
+	Size string `json:"size"`
+	// Count is the number of huge pages that are allocated.
+	// +kubebuilder:validation:Minimum=0
+	Count int32 `json:"count"`
+	// Reserved is the number of the allocated huge pages that are reserved for system daemons. They are rendered as
+	// hugepages-<size> into the systemReserved of the kubelet, which doesn't allocate them to pods.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Reserved int32 `json:"reserved,omitempty"`
+}
+
+// TransparentHugePagesMode is the mode of transparent huge pages.
+// +kubebuilder:validation:Enum=always;madvise;never
+type TransparentHugePagesMode string
+
+const (
+	TransparentHugePagesAlways  TransparentHugePagesMode = "always"
+	TransparentHugePagesMadvise TransparentHugePagesMode = "madvise"
+	TransparentHugePagesNever   TransparentHugePagesMode = "never"
+)
+
+// Storage describes the disks and filesystems of the instance.
+type Storage struct {
+	// Disks is a list of disks that are partitioned.
+	// +optional
+	Disks []Disk `json:"disks,omitempty"`
+	// Filesystems is a list of filesystems, including swap, that are created and mounted.
+	// +optional
+	Filesystems []Filesystem `json:"filesystems,omitempty"`
+}
+
+// Disk is a disk that is partitioned.
+type Disk struct {
+	// Device is the path of the disk, e.g. /dev/sdb.
+	Device string `json:"device"`
+	// WipeTable wipes the partition table of the disk before it's partitioned.
+	// +optional
+	WipeTable bool `json:"wipeTable,omitempty"`
+	// Partitions is a list of partitions that are created on the disk. cloud-init only supports a single partition
+	// that spans the whole disk.
+	// +optional
+	Partitions []Partition `json:"partitions,omitempty"`
+}
+
+// Partition is a partition of a disk.
+type Partition struct {
+	// Number is the number of the partition. The next free number is used if it's not set. It's only supported by
+	// ignition.
+	// +optional
+	Number int32 `json:"number,omitempty"`
+	// Label is the label of the partition. It's only supported by ignition.
+	// +optional
+	Label string `json:"label,omitempty"`
+	// SizeMiB is the size of the partition in MiB. The partition fills the remaining space of the disk if it's not set.
+	// +optional
+	SizeMiB int64 `json:"sizeMiB,omitempty"`
+}
+
+// FilesystemFormat is the format of a filesystem.
+// +kubebuilder:validation:Enum=ext4;xfs;swap
+type FilesystemFormat string
+
+const (
+	FilesystemFormatExt4 FilesystemFormat = "ext4"
+	FilesystemFormatXFS  FilesystemFormat = "xfs"
+	FilesystemFormatSwap FilesystemFormat = "swap"
+)
+
+// Filesystem is a filesystem that is created on a device and mounted. Swap is enabled instead of mounted.
+// +kubebuilder:validation:XValidation:rule="self.format != 'swap' || !has(self.path)",message="swap must not have a path"
+type Filesystem struct {
+	// Device is the path of the device, e.g. /dev/sdb1.
+	Device string `json:"device"`
+	// Format is the format of the filesystem.
+	Format FilesystemFormat `json:"format"`
+	// Label is the label of the filesystem.
+	// +optional
+	Label string `json:"label,omitempty"`
+	// WipeFilesystem creates the filesystem even if the device already contains a filesystem.
+	// +optional
+	WipeFilesystem bool `json:"wipeFilesystem,omitempty"`
+	// Path is the path that the filesystem is mounted at. The filesystem isn't mounted if it's not set.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// MountOptions are the options that the filesystem is mounted with.
+	// +optional
+	MountOptions []string `json:"mountOptions,omitempty"`
+}
+
+// ContainerRuntimeSpec aggregates information about a specific container runtime
+type ContainerRuntimeSpec struct {
+	// Name of the Container runtime
+	Name ContainerRuntime `json:"name"`
+	// Files to add to the main files list when the containerRuntime is selected
+	Files []File `json:"files"`
+	// Templates to add to the available templates when the containerRuntime is selected
+	Templates map[string]string `json:"templates,omitempty"`
+}
+
+// FileContent can either reference a secret or a config map, or contain inline configuration.
+// Exactly one of inline, secretRef and configMapRef must be set.
+type FileContent struct {
+	// Inline is a struct that contains information about the inlined data.
+	Inline *FileContentInline `json:"inline,omitempty"`
+	// SecretRef references a key of a secret that holds the file's data.
+	SecretRef *FileContentReference `json:"secretRef,omitempty"`
+	// ConfigMapRef references a key of a config map that holds the file's data.
+	ConfigMapRef *FileContentReference `json:"configMapRef,omitempty"`
+}
+
+// FileContentReference points to a key of a secret or config map. Referenced data is resolved when the
+// OperatingSystemConfig is rendered and is written to the file as-is, without being templated.
+type FileContentReference struct {
+	// Name is the name of the referenced object.
+	Name string `json:"name"`
+	// Key is the key of the referenced object that holds the file's data.
+	Key string `json:"key"`
+	// Namespace is the namespace of the referenced object. Defaults to the namespace of the OperatingSystemProfile,
+	// which is the only namespace that is allowed since OSM can only read secrets and config maps in its own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// FileContentInline contains keys for inlining a file content's data and encoding.
+type FileContentInline struct {
+	// Encoding is the file's encoding (e.g. base64).
+	Encoding string `json:"encoding,omitempty"`
+	// Data is the file's data.
+	Data string `json:"data"`
+}
+
+// CloudInitModules maps the names of cloud-init modules to their configuration, e.g. runcmd or yum_repos.
+// The configuration is passed to cloud-init as-is.
+type CloudInitModules map[string]apiextensionsv1.JSON
@@ -0,0 +1,32 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// Hub marks OperatingSystemProfile as a conversion hub, all other versions of the API are converted to and from v1beta1.
+func (*OperatingSystemProfile) Hub() {}
+
+// Hub marks OperatingSystemConfig as a conversion hub.
+func (*OperatingSystemConfig) Hub() {}
+
+// Hub marks OperatingSystemProfileFragment as a conversion hub.
+func (*OperatingSystemProfileFragment) Hub() {}
+
+// Hub marks OperatingSystemConfigOverride as a conversion hub.
+func (*OperatingSystemConfigOverride) Hub() {}
+
+// Hub marks TemplateLibrary as a conversion hub.
+func (*TemplateLibrary) Hub() {}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperatingSystemConfigResourceName represents "Resource" defined in Kubernetes
+	OperatingSystemConfigResourceName = "operatingsystemconfigs"
+
+	// OperatingSystemConfigKindName represents "Kind" defined in Kubernetes
+	OperatingSystemConfigKindName = "OperatingSystemConfig"
+)
+
+const (
+	// OperatingSystemConfigRendered indicates that the OperatingSystemConfig was rendered from its OperatingSystemProfile.
+	OperatingSystemConfigRendered = "Rendered"
+	// OperatingSystemConfigSecretsSynced indicates that the cloud-config secrets were generated from the OperatingSystemConfig.
+	OperatingSystemConfigSecretsSynced = "SecretsSynced"
+	// OperatingSystemConfigRenderFailed indicates that rendering an updated OperatingSystemConfig failed. The existing
+	// OperatingSystemConfig is kept until rendering succeeds.
+	OperatingSystemConfigRenderFailed = "RenderFailed"
+)
+
+//+genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=osc
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// OperatingSystemConfig is the object that represents the OperatingSystemConfig
+type OperatingSystemConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// OperatingSystemConfigSpec represents the operating system configuration spec.
+	Spec OperatingSystemConfigSpec `json:"spec"`
+	// Status represents the observed state of the operating system configuration.
+	// +optional
+	Status OperatingSystemConfigStatus `json:"status,omitempty"`
+}
+
+// OperatingSystemConfigSpec represents the data in the newly created OperatingSystemConfig
+type OperatingSystemConfigSpec struct {
+	// OSType represent the operating system name e.g: ubuntu
+	OSName OperatingSystem `json:"osName"`
+	// OSVersion the version of the operating system
+	OSVersion string `json:"osVersion"`
+	// CloudProvider represent the cloud provider that support the given operating system version
+	CloudProvider CloudProviderSpec `json:"cloudProvider"`
+	// BootstrapConfig is used for initial configuration of machine and to fetch the kubernetes secret that contains the provisioning config.
+	BootstrapConfig OSCConfig `json:"bootstrapConfig"`
+	// ProvisioningConfig is used for provisioning the worker node.
+	ProvisioningConfig OSCConfig `json:"provisioningConfig"`
+	// ProvisioningUtility used for configuring the worker node. Defaults to cloud-init.
+	// +kubebuilder:default=cloud-init
+	ProvisioningUtility ProvisioningUtility `json:"provisioningUtility,omitempty"`
+}
+
+// OperatingSystemConfigStatus represents the observed state of an OperatingSystemConfig
+type OperatingSystemConfigStatus struct {
+	// ObservedGeneration is the most recent generation of the OperatingSystemConfig observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// OperatingSystemProfile is the name of the OperatingSystemProfile the config was rendered from.
+	OperatingSystemProfile string `json:"operatingSystemProfile,omitempty"`
+	// OperatingSystemProfileVersion is the version of the OperatingSystemProfile the config was rendered from.
+	OperatingSystemProfileVersion string `json:"operatingSystemProfileVersion,omitempty"`
+	// MachineDeploymentRevision is the revision of the MachineDeployment the config was rendered for.
+	MachineDeploymentRevision string `json:"machineDeploymentRevision,omitempty"`
+	// MachineDeploymentAnnotationsHash is the hash of the MachineDeployment annotations the config was rendered with.
+	MachineDeploymentAnnotationsHash string `json:"machineDeploymentAnnotationsHash,omitempty"`
+	// CloudConfigSecrets are the cloud-config secrets generated from the config.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	CloudConfigSecrets []CloudConfigSecretStatus `json:"cloudConfigSecrets,omitempty"`
+	// Conditions represent the latest observations of the config's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CloudConfigSecretStatus describes a generated cloud-config secret
+type CloudConfigSecretStatus struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+	// Namespace is the namespace of the secret in the worker cluster.
+	Namespace string `json:"namespace"`
+	// Type is the type of the cloud-config e.g: bootstrap or provisioning
+	Type string `json:"type"`
+	// ContentHash is the hash of the secret's data.
+	ContentHash string `json:"contentHash"`
+}
+
+type OSCConfig struct {
+	// Units a list of the systemd unit files which will run on the instance
+	Units []Unit `json:"units,omitempty"`
+	// Files is a list of files that should exist in the instance
+	Files []File `json:"files,omitempty"`
+	// Directories is a list of directories that should exist in the instance
+	// +optional
+	Directories []Directory `json:"directories,omitempty"`
+	// Links is a list of links that should exist in the instance
+	// +optional
+	Links []Link `json:"links,omitempty"`
+	// Storage describes the disks and filesystems of the instance
+	// +optional
+	Storage *Storage `json:"storage,omitempty"`
+	// Packages are the package repositories and packages of the instance
+	// +optional
+	Packages *Packages `json:"packages,omitempty"`
+	// UserSSHKeys is a list of attached user ssh keys
+	UserSSHKeys []string `json:"userSSHKeys,omitempty"`
+	// Users is a list of users that are created on the instance in addition to the default user
+	// +optional
+	Users []User `json:"users,omitempty"`
+	// Groups is a list of groups that are created on the instance
+	// +optional
+	Groups []Group `json:"groups,omitempty"`
+	// CloudInitModules contains the supported cloud-init modules
+	// +optional
+	CloudInitModules CloudInitModules `json:"modules,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatingSystemConfigList is a list of OperatingSystemConfigs
+type OperatingSystemConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []OperatingSystemConfig `json:"items"`
+}
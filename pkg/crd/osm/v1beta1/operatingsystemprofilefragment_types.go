@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperatingSystemProfileFragmentResourceName represents "Resource" defined in Kubernetes
+	OperatingSystemProfileFragmentResourceName = "operatingsystemprofilefragments"
+
+	// OperatingSystemProfileFragmentKindName represents "Kind" defined in Kubernetes
+	OperatingSystemProfileFragmentKindName = "OperatingSystemProfileFragment"
+)
+
+//+genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=ospf
+// +kubebuilder:storageversion
+
+// OperatingSystemProfileFragment is the object that represents a part of the configuration that is merged into all
+// matching OperatingSystemProfiles in the same namespace
+type OperatingSystemProfileFragment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// OperatingSystemProfileFragmentSpec represents the operating system profile fragment spec.
+	Spec OperatingSystemProfileFragmentSpec `json:"spec"`
+}
+
+// OperatingSystemProfileFragmentSpec represents the data in the newly created OperatingSystemProfileFragment
+// +kubebuilder:validation:XValidation:rule="has(self.operatingSystemProfileSelector) || has(self.machineDeploymentSelector)",message="at least one of operatingSystemProfileSelector and machineDeploymentSelector must be set"
+type OperatingSystemProfileFragmentSpec struct {
+	// OperatingSystemProfileSelector selects the OperatingSystemProfiles that the fragment is merged into by their labels.
+	// An empty selector matches all OperatingSystemProfiles.
+	// +optional
+	OperatingSystemProfileSelector *metav1.LabelSelector `json:"operatingSystemProfileSelector,omitempty"`
+	// MachineDeploymentSelector selects the MachineDeployments whose OperatingSystemConfigs the fragment is merged into
+	// by their labels. An empty selector matches all MachineDeployments.
+	// If both selectors are set, the fragment is only merged if both match.
+	// +optional
+	MachineDeploymentSelector *metav1.LabelSelector `json:"machineDeploymentSelector,omitempty"`
+	// BootstrapConfig is merged into the bootstrap config of the matching OperatingSystemProfiles.
+	// +optional
+	BootstrapConfig *OSPFragmentConfig `json:"bootstrapConfig,omitempty"`
+	// ProvisioningConfig is merged into the provisioning config of the matching OperatingSystemProfiles.
+	// +optional
+	ProvisioningConfig *OSPFragmentConfig `json:"provisioningConfig,omitempty"`
+}
+
+// OSPFragmentConfig contains the configuration that is merged into an OperatingSystemProfile config. Files, units and
+// templates replace the entries of the OperatingSystemProfile with the same path or name, and are added otherwise.
+type OSPFragmentConfig struct {
+	// Templates to be included in units and files
+	Templates map[string]string `json:"templates,omitempty"`
+	// Units a list of the systemd unit files which will run on the instance, only supported for Flatcar
+	Units []Unit `json:"units,omitempty"`
+	// Files is a list of files that should exist in the instance
+	Files []File `json:"files,omitempty"`
+	// CloudInitModules are merged into the cloud-init modules of the OperatingSystemProfile. Commands are appended,
+	// repositories and subscription settings are added or replaced by name.
+	// +optional
+	CloudInitModules CloudInitModules `json:"modules,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperatingSystemProfileFragmentList is a list of OperatingSystemProfileFragments
+type OperatingSystemProfileFragmentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []OperatingSystemProfileFragment `json:"items"`
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TemplateLibraryResourceName represents "Resource" defined in Kubernetes
+	TemplateLibraryResourceName = "templatelibraries"
+
+	// TemplateLibraryKindName represents "Kind" defined in Kubernetes
+	TemplateLibraryKindName = "TemplateLibrary"
+)
+
+//+genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=tl
+// +kubebuilder:storageversion
+
+// TemplateLibrary is the object that represents a set of named templates that OperatingSystemProfiles in the same
+// namespace can import
+type TemplateLibrary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// TemplateLibrarySpec represents the template library spec.
+	Spec TemplateLibrarySpec `json:"spec"`
+}
+
+// TemplateLibrarySpec represents the data in the newly created TemplateLibrary
+type TemplateLibrarySpec struct {
+	// Version is the version of the template library. OperatingSystemConfigs that import the library are rotated if
+	// the version or the templates change.
+	// +kubebuilder:validation:Pattern=`v(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
+	Version string `json:"version"`
+	// Templates are the named templates of the library. They're available to the units and files of the importing
+	// OperatingSystemProfiles like their own templates.
+	Templates map[string]string `json:"templates"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TemplateLibraryList is a list of TemplateLibraries
+type TemplateLibraryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TemplateLibrary `json:"items"`
+}
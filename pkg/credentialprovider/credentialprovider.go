@@ -0,0 +1,290 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigFileName is the path of the CredentialProviderConfig of the kubelet.
+	ConfigFileName = "/etc/kubernetes/credential-provider-config.yaml"
+	// BinDir is the directory of the credential provider binaries, the kubelet runs the binary with the name of the
+	// provider.
+	BinDir = "/opt/bin/credential-providers"
+
+	configAPIVersion   = "kubelet.config.k8s.io/v1"
+	configKind         = "CredentialProviderConfig"
+	providerAPIVersion = "credentialprovider.kubelet.k8s.io/v1"
+
+	defaultCacheDuration = "5m"
+)
+
+// Provider is an image credential provider plugin of the kubelet.
+type Provider struct {
+	Name string
+	// BinaryURL is the download URL of the provider binary, ${arch} is replaced with the architecture of the node.
+	BinaryURL            string
+	MatchImages          []string
+	DefaultCacheDuration string
+	Args                 []string
+	// CloudConfigFile is the path of the cloud-config that the provider reads, it's written to the nodes by OSM.
+	CloudConfigFile string
+	// Script is the content of the provider executable, it's written to the nodes by OSM instead of downloading a
+	// binary.
+	Script string
+}
+
+// cloudProviders are the credential providers of the registries of the cloud providers.
+var cloudProviders = map[osmv1alpha1.CloudProvider]Provider{
+	osmv1alpha1.CloudProviderAWS: {
+		Name:      "ecr-credential-provider",
+		BinaryURL: "https://artifacts.k8s.io/binaries/cloud-provider-aws/v1.33.0/linux/${arch}/ecr-credential-provider-linux-${arch}",
+		MatchImages: []string{
+			"*.dkr.ecr.*.amazonaws.com",
+			"*.dkr.ecr.*.amazonaws.com.cn",
+			"*.dkr.ecr-fips.*.amazonaws.com",
+			"*.dkr.ecr.us-iso-east-1.c2s.ic.gov",
+			"*.dkr.ecr.us-isob-east-1.sc2s.sgov.gov",
+		},
+		// ECR tokens are valid for 12 hours.
+		DefaultCacheDuration: "12h",
+		Args:                 []string{"get-credentials"},
+	},
+	osmv1alpha1.CloudProviderAzure: {
+		Name:      "acr-credential-provider",
+		BinaryURL: "https://github.com/kubernetes-sigs/cloud-provider-azure/releases/download/v1.33.0/azure-acr-credential-provider-linux-${arch}",
+		MatchImages: []string{
+			"*.azurecr.io",
+			"*.azurecr.cn",
+			"*.azurecr.de",
+			"*.azurecr.us",
+		},
+		DefaultCacheDuration: "10m",
+		Args:                 []string{"/etc/kubernetes/acr-credential-provider.json"},
+		CloudConfigFile:      "/etc/kubernetes/acr-credential-provider.json",
+	},
+	// auth-provider-gcp isn't published as a release binary, its URL has to be configured.
+	osmv1alpha1.CloudProviderGoogle: {
+		Name: "auth-provider-gcp",
+		MatchImages: []string{
+			"container.cloud.google.com",
+			"gcr.io",
+			"*.gcr.io",
+			"*.pkg.dev",
+		},
+		DefaultCacheDuration: "1m",
+		Args:                 []string{"get-credentials", "--v=3"},
+	},
+}
+
+type Opts struct {
+	// CloudProvider enables the credential provider of the cloud provider of the nodes.
+	CloudProvider bool
+	// CloudProviderBinaryURL overrides the download URL of the credential provider of the cloud provider.
+	CloudProviderBinaryURL string
+	// Custom is a credential provider in the format of ParseProvider, it's configured for all cloud providers.
+	Custom string
+	// RegistryCredentialsMode is either container-runtime or credential-provider, it defaults to container-runtime.
+	RegistryCredentialsMode string
+}
+
+type Config struct {
+	CloudProvider          bool
+	CloudProviderBinaryURL string
+	Custom                 *Provider
+	// RegistryCredentials delivers the registry credentials through a credential provider instead of the config of
+	// the container runtime.
+	RegistryCredentials bool
+
+	// registries are the registries of the registry credentials of the nodes.
+	registries []string
+}
+
+func BuildConfig(opts Opts) (Config, error) {
+	if opts.CloudProviderBinaryURL != "" && !opts.CloudProvider {
+		return Config{}, errors.New("the binary URL of the cloud provider credential provider requires the cloud provider credential provider to be enabled")
+	}
+
+	cfg := Config{
+		CloudProvider:          opts.CloudProvider,
+		CloudProviderBinaryURL: opts.CloudProviderBinaryURL,
+	}
+
+	switch opts.RegistryCredentialsMode {
+	case "", RegistryCredentialsModeContainerRuntime:
+	case RegistryCredentialsModeCredentialProvider:
+		cfg.RegistryCredentials = true
+	default:
+		return Config{}, fmt.Errorf("unknown registry credentials mode %q, expected %s or %s", opts.RegistryCredentialsMode, RegistryCredentialsModeContainerRuntime, RegistryCredentialsModeCredentialProvider)
+	}
+
+	if opts.Custom != "" {
+		custom, err := ParseProvider(opts.Custom)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid custom credential provider: %w", err)
+		}
+		cfg.Custom = &custom
+	}
+
+	return cfg, nil
+}
+
+// WithRegistries returns a copy of the config with the registries of the registry credentials of the nodes, their
+// credential provider is only configured if registry credentials are delivered through a credential provider.
+func (cfg Config) WithRegistries(registries []string) Config {
+	cfg.registries = slices.Clone(registries)
+	return cfg
+}
+
+// Providers returns the credential providers of nodes of the cloud provider, it's empty if no credential provider is
+// configured for them.
+func (cfg Config) Providers(cloudProvider osmv1alpha1.CloudProvider) ([]Provider, error) {
+	var providers []Provider
+
+	if provider, ok := cloudProviders[cloudProvider]; ok && cfg.CloudProvider {
+		if cfg.CloudProviderBinaryURL != "" {
+			provider.BinaryURL = cfg.CloudProviderBinaryURL
+		}
+		if provider.BinaryURL == "" {
+			return nil, fmt.Errorf("credential provider %s has no binary URL", provider.Name)
+		}
+		providers = append(providers, provider)
+	}
+
+	if cfg.RegistryCredentials && len(cfg.registries) > 0 {
+		providers = append(providers, registryCredentialsProvider(cfg.registries))
+	}
+
+	if cfg.Custom != nil {
+		if slices.ContainsFunc(providers, func(p Provider) bool { return p.Name == cfg.Custom.Name }) {
+			return nil, fmt.Errorf("custom credential provider %s conflicts with the built-in credential provider of the same name", cfg.Custom.Name)
+		}
+		providers = append(providers, *cfg.Custom)
+	}
+
+	return providers, nil
+}
+
+// ParseProvider parses a credential provider from comma separated key=value pairs, e.g.
+// "name=my-provider,url=https://example.com/my-provider-${arch},match-images=registry.example.com;*.example.com". Besides
+// name, url and match-images, which are required, args and cache-duration can be set. Lists are separated by
+// semicolons.
+func ParseProvider(value string) (Provider, error) {
+	provider := Provider{DefaultCacheDuration: defaultCacheDuration}
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Provider{}, fmt.Errorf("%q is not a key=value pair", entry)
+		}
+
+		switch key {
+		case "name":
+			provider.Name = val
+		case "url":
+			provider.BinaryURL = val
+		case "match-images":
+			provider.MatchImages = splitList(val)
+		case "args":
+			provider.Args = splitList(val)
+		case "cache-duration":
+			if _, err := time.ParseDuration(val); err != nil {
+				return Provider{}, fmt.Errorf("invalid cache-duration %q: %w", val, err)
+			}
+			provider.DefaultCacheDuration = val
+		default:
+			return Provider{}, fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	switch {
+	case provider.Name == "":
+		return Provider{}, errors.New("name is required")
+	// The kubelet runs the binary in the bin dir with the name of the provider.
+	case strings.ContainsAny(provider.Name, "/ ") || provider.Name == "." || provider.Name == "..":
+		return Provider{}, fmt.Errorf("name %q is not a valid file name", provider.Name)
+	case provider.BinaryURL == "":
+		return Provider{}, errors.New("url is required")
+	case len(provider.MatchImages) == 0:
+		return Provider{}, errors.New("match-images is required")
+	}
+
+	return provider, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+type credentialProviderConfig struct {
+	APIVersion string                   `json:"apiVersion"`
+	Kind       string                   `json:"kind"`
+	Providers  []credentialProviderSpec `json:"providers"`
+}
+
+type credentialProviderSpec struct {
+	Name                 string   `json:"name"`
+	MatchImages          []string `json:"matchImages"`
+	DefaultCacheDuration string   `json:"defaultCacheDuration"`
+	APIVersion           string   `json:"apiVersion"`
+	Args                 []string `json:"args,omitempty"`
+}
+
+// KubeletConfig returns the CredentialProviderConfig of the kubelet for the credential providers.
+func KubeletConfig(providers []Provider) (string, error) {
+	cfg := credentialProviderConfig{
+		APIVersion: configAPIVersion,
+		Kind:       configKind,
+	}
+
+	for _, provider := range providers {
+		cfg.Providers = append(cfg.Providers, credentialProviderSpec{
+			Name:                 provider.Name,
+			MatchImages:          provider.MatchImages,
+			DefaultCacheDuration: provider.DefaultCacheDuration,
+			APIVersion:           providerAPIVersion,
+			Args:                 provider.Args,
+		})
+	}
+
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credential provider config: %w", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	testUtil "k8c.io/operating-system-manager/pkg/test/util"
+)
+
+var update = flag.Bool("update", false, "update testdata files")
+
+func TestParseProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected Provider
+		wantErr  bool
+	}{
+		{
+			name:  "all keys",
+			value: "name=my-provider,url=https://example.com/my-provider-${arch},match-images=registry.example.com;*.registry.example.com,args=get-credentials;--v=2,cache-duration=10m",
+			expected: Provider{
+				Name:                 "my-provider",
+				BinaryURL:            "https://example.com/my-provider-${arch}",
+				MatchImages:          []string{"registry.example.com", "*.registry.example.com"},
+				DefaultCacheDuration: "10m",
+				Args:                 []string{"get-credentials", "--v=2"},
+			},
+		},
+		{
+			name:  "default cache duration",
+			value: "name=my-provider, url=https://example.com/my-provider, match-images=registry.example.com",
+			expected: Provider{
+				Name:                 "my-provider",
+				BinaryURL:            "https://example.com/my-provider",
+				MatchImages:          []string{"registry.example.com"},
+				DefaultCacheDuration: "5m",
+			},
+		},
+		{
+			name:    "missing name",
+			value:   "url=https://example.com/my-provider,match-images=registry.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "name with path",
+			value:   "name=../my-provider,url=https://example.com/my-provider,match-images=registry.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "missing url",
+			value:   "name=my-provider,match-images=registry.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "missing match images",
+			value:   "name=my-provider,url=https://example.com/my-provider",
+			wantErr: true,
+		},
+		{
+			name:    "invalid cache duration",
+			value:   "name=my-provider,url=https://example.com/my-provider,match-images=registry.example.com,cache-duration=forever",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			value:   "name=my-provider,url=https://example.com/my-provider,match-images=registry.example.com,env=FOO",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := ParseProvider(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := deep.Equal(provider, tt.expected); diff != nil {
+				t.Errorf("unexpected provider: %v", diff)
+			}
+		})
+	}
+}
+
+func TestConfigProviders(t *testing.T) {
+	custom := &Provider{
+		Name:                 "my-provider",
+		BinaryURL:            "https://example.com/my-provider-${arch}",
+		MatchImages:          []string{"registry.example.com"},
+		DefaultCacheDuration: "5m",
+	}
+
+	tests := []struct {
+		name          string
+		config        Config
+		cloudProvider osmv1alpha1.CloudProvider
+		expected      []string
+		wantErr       bool
+	}{
+		{
+			name:          "disabled",
+			config:        Config{},
+			cloudProvider: osmv1alpha1.CloudProviderAWS,
+		},
+		{
+			name:          "aws",
+			config:        Config{CloudProvider: true},
+			cloudProvider: osmv1alpha1.CloudProviderAWS,
+			expected:      []string{"ecr-credential-provider"},
+		},
+		{
+			name:          "cloud provider without credential provider",
+			config:        Config{CloudProvider: true},
+			cloudProvider: osmv1alpha1.CloudProviderHetzner,
+		},
+		{
+			name:          "gce without binary url",
+			config:        Config{CloudProvider: true},
+			cloudProvider: osmv1alpha1.CloudProviderGoogle,
+			wantErr:       true,
+		},
+		{
+			name:          "gce with binary url",
+			config:        Config{CloudProvider: true, CloudProviderBinaryURL: "https://example.com/auth-provider-gcp"},
+			cloudProvider: osmv1alpha1.CloudProviderGoogle,
+			expected:      []string{"auth-provider-gcp"},
+		},
+		{
+			name:          "azure and custom",
+			config:        Config{CloudProvider: true, Custom: custom},
+			cloudProvider: osmv1alpha1.CloudProviderAzure,
+			expected:      []string{"acr-credential-provider", "my-provider"},
+		},
+		{
+			name:          "custom only",
+			config:        Config{Custom: custom},
+			cloudProvider: osmv1alpha1.CloudProviderAWS,
+			expected:      []string{"my-provider"},
+		},
+		{
+			name:          "custom conflicts with cloud provider",
+			config:        Config{CloudProvider: true, Custom: &Provider{Name: "ecr-credential-provider"}},
+			cloudProvider: osmv1alpha1.CloudProviderAWS,
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers, err := tt.config.Providers(tt.cloudProvider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Providers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var names []string
+			for _, provider := range providers {
+				names = append(names, provider.Name)
+			}
+			if diff := deep.Equal(names, tt.expected); diff != nil {
+				t.Errorf("unexpected providers: %v", diff)
+			}
+		})
+	}
+}
+
+func TestKubeletConfig(t *testing.T) {
+	providers, err := Config{
+		CloudProvider: true,
+		Custom: &Provider{
+			Name:                 "my-provider",
+			BinaryURL:            "https://example.com/my-provider-${arch}",
+			MatchImages:          []string{"registry.example.com", "*.registry.example.com"},
+			DefaultCacheDuration: "5m",
+		},
+	}.Providers(osmv1alpha1.CloudProviderAWS)
+	if err != nil {
+		t.Fatalf("Providers() error = %v", err)
+	}
+
+	config, err := KubeletConfig(providers)
+	if err != nil {
+		t.Fatalf("KubeletConfig() error = %v", err)
+	}
+
+	testUtil.CompareOutput(t, testUtil.FSGoldenName(t), config, *update)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"k8c.io/operating-system-manager/pkg/containerruntime"
+)
+
+const (
+	// RegistryCredentialsModeContainerRuntime writes the registry credentials into the config of the container runtime.
+	RegistryCredentialsModeContainerRuntime = "container-runtime"
+	// RegistryCredentialsModeCredentialProvider keeps the registry credentials out of the OSCs, the nodes fetch them
+	// into a root-only file while provisioning and the kubelet reads them through an image credential provider. The
+	// sandbox image is pulled by the container runtime rather than the kubelet, so the credentials of its registry are
+	// still written into the config of the container runtime.
+	RegistryCredentialsModeCredentialProvider = "credential-provider"
+
+	// RegistryCredentialsFileName is the path of the registry credentials on the nodes, in the format of a
+	// CredentialProviderResponse.
+	RegistryCredentialsFileName = "/etc/kubernetes/registry-credentials.json"
+	// RegistryCredentialsSecretKey is the key of the registry credentials in the secret that the nodes fetch.
+	RegistryCredentialsSecretKey = "credentials"
+
+	registryCredentialsProviderName = "osm-registry-credentials"
+	responseKind                    = "CredentialProviderResponse"
+
+	// dockerHubRegistry is the registry of images without a registry, docker config json files key its credentials by
+	// "https://index.docker.io/v1/".
+	dockerHubRegistry      = "docker.io"
+	dockerHubIndexRegistry = "index.docker.io"
+)
+
+// registryCredentialsProvider returns the credential provider that responds with the registry credentials of the
+// nodes. The kubelet sends a request on stdin, which is not required since the response is the same for all images.
+func registryCredentialsProvider(registries []string) Provider {
+	return Provider{
+		Name:                 registryCredentialsProviderName,
+		MatchImages:          registries,
+		DefaultCacheDuration: defaultCacheDuration,
+		Script:               fmt.Sprintf("#!/bin/sh\nexec cat %s\n", RegistryCredentialsFileName),
+	}
+}
+
+type credentialProviderResponse struct {
+	APIVersion   string                    `json:"apiVersion"`
+	Kind         string                    `json:"kind"`
+	CacheKeyType string                    `json:"cacheKeyType"`
+	Auth         map[string]authCredential `json:"auth"`
+}
+
+type authCredential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RegistryCredentialsResponse returns the CredentialProviderResponse with the registry credentials and the registries
+// they belong to, which are the images that the credential provider has to match.
+func RegistryCredentialsResponse(credentials map[string]containerruntime.AuthConfig) (string, []string, error) {
+	response := credentialProviderResponse{
+		APIVersion:   providerAPIVersion,
+		Kind:         responseKind,
+		CacheKeyType: "Registry",
+		Auth:         map[string]authCredential{},
+	}
+
+	for registry, auth := range credentials {
+		credential, err := authCredentialFromAuthConfig(auth)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid credentials of registry %s: %w", registry, err)
+		}
+		response.Auth[registryHost(registry)] = credential
+	}
+
+	registries := make([]string, 0, len(response.Auth))
+	for registry := range response.Auth {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	b, err := json.Marshal(response)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode credential provider response: %w", err)
+	}
+
+	return string(b), registries, nil
+}
+
+// SandboxImageCredentials returns the credentials of the registry of the sandbox image, which is pulled by the
+// container runtime itself and never sees the credentials of the image credential providers.
+func SandboxImageCredentials(credentials map[string]containerruntime.AuthConfig, sandboxImage string) map[string]containerruntime.AuthConfig {
+	if sandboxImage == "" {
+		return nil
+	}
+
+	registry := imageRegistry(sandboxImage)
+	sandboxCredentials := map[string]containerruntime.AuthConfig{}
+	for key, auth := range credentials {
+		if registryHost(key) == registry {
+			sandboxCredentials[key] = auth
+		}
+	}
+	if len(sandboxCredentials) == 0 {
+		return nil
+	}
+
+	return sandboxCredentials
+}
+
+// authCredentialFromAuthConfig converts docker config json credentials, identity tokens are not supported by the
+// kubelet.
+func authCredentialFromAuthConfig(auth containerruntime.AuthConfig) (authCredential, error) {
+	if auth.IdentityToken != "" {
+		return authCredential{}, fmt.Errorf("identity tokens are not supported")
+	}
+
+	if auth.Username != "" || auth.Password != "" {
+		return authCredential{Username: auth.Username, Password: auth.Password}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+	if err != nil {
+		return authCredential{}, fmt.Errorf("failed to decode auth: %w", err)
+	}
+
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return authCredential{}, fmt.Errorf("auth is not in the format username:password")
+	}
+
+	return authCredential{Username: username, Password: password}, nil
+}
+
+// registryHost returns the host of the registry, docker config json keys can be full URLs, e.g. "https://gcr.io".
+// The index of Docker Hub is mapped to docker.io, which the kubelet matches images against.
+func registryHost(registry string) string {
+	if u, err := url.Parse(registry); err == nil && u.Host != "" {
+		registry = u.Host
+	}
+	if registry == dockerHubIndexRegistry {
+		return dockerHubRegistry
+	}
+	return registry
+}
+
+// imageRegistry returns the registry of the image, images without a registry are pulled from docker.io.
+func imageRegistry(image string) string {
+	registry, _, ok := strings.Cut(image, "/")
+	if !ok || (!strings.ContainsAny(registry, ".:") && registry != "localhost") {
+		return dockerHubRegistry
+	}
+	return registryHost(registry)
+}
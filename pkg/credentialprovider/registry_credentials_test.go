@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprovider
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"k8c.io/operating-system-manager/pkg/containerruntime"
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+)
+
+func TestRegistryCredentialsResponse(t *testing.T) {
+	tests := []struct {
+		name               string
+		credentials        map[string]containerruntime.AuthConfig
+		expectedResponse   string
+		expectedRegistries []string
+		wantErr            bool
+	}{
+		{
+			name: "username and password",
+			credentials: map[string]containerruntime.AuthConfig{
+				"https://registry.example.com": {Username: "user", Password: "pass"},
+				"gcr.io":                       {Auth: "dXNlcjpwYXNz"},
+			},
+			expectedResponse:   `{"apiVersion":"credentialprovider.kubelet.k8s.io/v1","kind":"CredentialProviderResponse","cacheKeyType":"Registry","auth":{"gcr.io":{"username":"user","password":"pass"},"registry.example.com":{"username":"user","password":"pass"}}}`,
+			expectedRegistries: []string{"gcr.io", "registry.example.com"},
+		},
+		{
+			name: "docker hub index",
+			credentials: map[string]containerruntime.AuthConfig{
+				"https://index.docker.io/v1/": {Username: "user", Password: "pass"},
+			},
+			expectedResponse:   `{"apiVersion":"credentialprovider.kubelet.k8s.io/v1","kind":"CredentialProviderResponse","cacheKeyType":"Registry","auth":{"docker.io":{"username":"user","password":"pass"}}}`,
+			expectedRegistries: []string{"docker.io"},
+		},
+		{
+			name: "invalid auth",
+			credentials: map[string]containerruntime.AuthConfig{
+				"registry.example.com": {Auth: "dXNlcg=="},
+			},
+			wantErr: true,
+		},
+		{
+			name: "identity token",
+			credentials: map[string]containerruntime.AuthConfig{
+				"registry.example.com": {IdentityToken: "token"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, registries, err := RegistryCredentialsResponse(tt.credentials)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RegistryCredentialsResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if response != tt.expectedResponse {
+				t.Errorf("expected response %s, got %s", tt.expectedResponse, response)
+			}
+			if diff := deep.Equal(registries, tt.expectedRegistries); diff != nil {
+				t.Errorf("unexpected registries: %v", diff)
+			}
+		})
+	}
+}
+
+func TestSandboxImageCredentials(t *testing.T) {
+	credentials := map[string]containerruntime.AuthConfig{
+		"https://index.docker.io/v1/":  {Username: "hub", Password: "pass"},
+		"https://registry.example.com": {Username: "user", Password: "pass"},
+		"registry.example.com:5000":    {Username: "port", Password: "pass"},
+	}
+
+	tests := []struct {
+		name                string
+		sandboxImage        string
+		expectedCredentials map[string]containerruntime.AuthConfig
+	}{
+		{
+			name:         "registry with credentials",
+			sandboxImage: "registry.example.com/pause:3.10",
+			expectedCredentials: map[string]containerruntime.AuthConfig{
+				"https://registry.example.com": {Username: "user", Password: "pass"},
+			},
+		},
+		{
+			name:         "registry with port",
+			sandboxImage: "registry.example.com:5000/pause:3.10",
+			expectedCredentials: map[string]containerruntime.AuthConfig{
+				"registry.example.com:5000": {Username: "port", Password: "pass"},
+			},
+		},
+		{
+			name:         "image without registry",
+			sandboxImage: "kubernetes/pause:3.10",
+			expectedCredentials: map[string]containerruntime.AuthConfig{
+				"https://index.docker.io/v1/": {Username: "hub", Password: "pass"},
+			},
+		},
+		{
+			name:         "registry without credentials",
+			sandboxImage: "registry.k8s.io/pause:3.10",
+		},
+		{
+			name: "default sandbox image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(SandboxImageCredentials(credentials, tt.sandboxImage), tt.expectedCredentials); diff != nil {
+				t.Errorf("unexpected credentials: %v", diff)
+			}
+		})
+	}
+}
+
+func TestRegistryCredentialsProvider(t *testing.T) {
+	if _, err := BuildConfig(Opts{RegistryCredentialsMode: "plaintext"}); err == nil {
+		t.Fatal("expected an error for an unknown registry credentials mode")
+	}
+
+	cfg, err := BuildConfig(Opts{RegistryCredentialsMode: RegistryCredentialsModeCredentialProvider})
+	if err != nil {
+		t.Fatalf("BuildConfig() error = %v", err)
+	}
+
+	// Without registries there are no credentials to deliver.
+	providers, err := cfg.Providers(osmv1alpha1.CloudProviderHetzner)
+	if err != nil {
+		t.Fatalf("Providers() error = %v", err)
+	}
+	if len(providers) != 0 {
+		t.Fatalf("expected no providers, got %v", providers)
+	}
+
+	providers, err = cfg.WithRegistries([]string{"registry.example.com"}).Providers(osmv1alpha1.CloudProviderHetzner)
+	if err != nil {
+		t.Fatalf("Providers() error = %v", err)
+	}
+	if len(providers) != 1 || providers[0].Name != registryCredentialsProviderName || providers[0].Script == "" {
+		t.Fatalf("expected the %s script provider, got %v", registryCredentialsProviderName, providers)
+	}
+	if diff := deep.Equal(providers[0].MatchImages, []string{"registry.example.com"}); diff != nil {
+		t.Errorf("unexpected match images: %v", diff)
+	}
+
+	// The container runtime mode ignores the registries.
+	providers, err = Config{}.WithRegistries([]string{"registry.example.com"}).Providers(osmv1alpha1.CloudProviderHetzner)
+	if err != nil {
+		t.Fatalf("Providers() error = %v", err)
+	}
+	if len(providers) != 0 {
+		t.Errorf("expected no providers, got %v", providers)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"path"
 	"strings"
 	"text/template"
 
@@ -79,16 +80,41 @@ func (d *DefaultCloudConfigGenerator) Generate(config *osmv1alpha1.OSCConfig, pr
 			Path:     file.Path,
 			Content:  content,
 			Encoding: file.Content.Inline.Encoding,
+			User:     file.Owner,
+			Group:    file.Group,
+			Append:   file.WritePolicy == osmv1alpha1.FileWritePolicyAppend,
+			// cloud-init creates users after writing files, files owned by them have to be written at the end.
+			Defer: !isRoot(file.Owner) || !isRoot(file.Group),
 		}
-		permissions := fmt.Sprintf("%v", file.Permissions)
-		// Convert to an octal value for file permissions.
-		if len(permissions) == 3 {
-			permissions = "0" + permissions
-		}
-		fSpec.Permissions = &permissions
+		fSpec.Permissions = octalPermissions(file.Permissions)
 		files = append(files, fSpec)
 	}
 
+	var directories []*directorySpec
+	for _, directory := range config.Directories {
+		dSpec := &directorySpec{
+			Path:  directory.Path,
+			User:  directory.Owner,
+			Group: directory.Group,
+		}
+		// Directories without permissions are created with the default mode.
+		if directory.Permissions != 0 {
+			dSpec.Permissions = octalPermissions(directory.Permissions)
+		}
+		directories = append(directories, dSpec)
+	}
+
+	var links []*linkSpec
+	for _, link := range config.Links {
+		links = append(links, &linkSpec{
+			Path:   link.Path,
+			Target: link.Target,
+			Hard:   link.Hard,
+			User:   link.Owner,
+			Group:  link.Group,
+		})
+	}
+
 	var units []*unitSpec
 	for _, unit := range config.Units {
 		uSpec := &unitSpec{
@@ -117,6 +143,39 @@ func (d *DefaultCloudConfigGenerator) Generate(config *osmv1alpha1.OSCConfig, pr
 		units = append(units, uSpec)
 	}
 
+	packageFiles, packageLinks, packageCommands, err := packageManagement(config.Packages, operatingSystem, provisioner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render packages: %w", err)
+	}
+	files = append(files, packageFiles...)
+
+	users, groups := usersAndGroups(config)
+	commands := append(directoryAndLinkCommands(directories, links), packageCommands...)
+	links = append(links, packageLinks...)
+	cloudInitModules := config.CloudInitModules
+	// The commands are run as part of the runcmd module, which is only rendered with the cloud-init modules.
+	if cloudInitModules == nil && len(commands) > 0 {
+		cloudInitModules = &osmv1alpha1.CloudInitModule{}
+	}
+	// Ignition has no support for sudo rules, they are written to sudoers files instead.
+	if provisioner == osmv1alpha1.ProvisioningUtilityIgnition {
+		files = append(files, sudoersFiles(config.Users)...)
+		// Ignition only creates filesystems, they are mounted by systemd units.
+		units = append(units, storageUnits(config.Storage)...)
+	} else {
+		if err := validateCloudInitStorage(config.Storage); err != nil {
+			return nil, err
+		}
+		if cloudInitModules != nil {
+			files = append(files, cloudInitWriteFiles(cloudInitModules.WriteFiles)...)
+		}
+	}
+
+	mounts := cloudInitMounts(config.Storage)
+	if cloudInitModules != nil {
+		mounts = append(mounts, cloudInitModules.Mounts...)
+	}
+
 	// Retrieve Operating System Config.
 	providerConfig := mcproviderconfig.Config{}
 	if err := jsonutil.StrictUnmarshal(md.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerConfig); err != nil {
@@ -138,8 +197,15 @@ func (d *DefaultCloudConfigGenerator) Generate(config *osmv1alpha1.OSCConfig, pr
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, &struct {
 		Files             []*fileSpec
+		Directories       []*directorySpec
+		Links             []*linkSpec
+		Commands          []string
+		Storage           *osmv1alpha1.Storage
+		Mounts            [][]string
 		Units             []*unitSpec
 		UserSSHKeys       []string
+		Users             []*userSpec
+		Groups            []*groupSpec
 		CloudInitModules  *osmv1alpha1.CloudInitModule
 		CloudProviderName string
 		OperatingSystem   string
@@ -147,9 +213,16 @@ func (d *DefaultCloudConfigGenerator) Generate(config *osmv1alpha1.OSCConfig, pr
 		OSConfig          providerconfig.Config
 	}{
 		Files:             files,
+		Directories:       directories,
+		Links:             links,
+		Commands:          commands,
+		Storage:           config.Storage,
+		Mounts:            mounts,
 		Units:             units,
 		UserSSHKeys:       deduplicateSSHKeys(config.UserSSHKeys),
-		CloudInitModules:  config.CloudInitModules,
+		Users:             users,
+		Groups:            groups,
+		CloudInitModules:  cloudInitModules,
 		CloudProviderName: string(cloudProvider),
 		OperatingSystem:   string(operatingSystem),
 		ConfigurationType: string(secretType),
@@ -185,6 +258,236 @@ func deduplicateSSHKeys(keys []string) []string {
 	return result
 }
 
+// octalPermissions converts permissions that are written as decimal numbers, e.g. 644, to an octal file mode.
+func octalPermissions(permissions int32) *string {
+	mode := fmt.Sprintf("%v", permissions)
+	if len(mode) == 3 {
+		mode = "0" + mode
+	}
+	return &mode
+}
+
+func isRoot(name string) bool {
+	return name == "" || name == "root"
+}
+
+// directoryAndLinkCommands returns the commands that create the directories and links, cloud-init has no module for
+// them.
+func directoryAndLinkCommands(directories []*directorySpec, links []*linkSpec) []string {
+	var commands []string
+	for _, directory := range directories {
+		command := []string{"install", "-d"}
+		if directory.Permissions != nil {
+			command = append(command, "-m", *directory.Permissions)
+		}
+		if directory.User != "" {
+			command = append(command, "-o", directory.User)
+		}
+		if directory.Group != "" {
+			command = append(command, "-g", directory.Group)
+		}
+		commands = append(commands, shellCommand(append(command, directory.Path)...))
+	}
+
+	for _, link := range links {
+		commands = append(commands, shellCommand("mkdir", "-p", path.Dir(link.Path)))
+		if link.Hard {
+			commands = append(commands, shellCommand("ln", "-f", link.Target, link.Path))
+		} else {
+			commands = append(commands, shellCommand("ln", "-sfn", link.Target, link.Path))
+		}
+		if !isRoot(link.User) || !isRoot(link.Group) {
+			owner := link.User
+			if owner == "" {
+				owner = "root"
+			}
+			if link.Group != "" {
+				owner += ":" + link.Group
+			}
+			commands = append(commands, shellCommand("chown", "-h", owner, link.Path))
+		}
+	}
+
+	return commands
+}
+
+// shellCommand joins the arguments to a command line, quoting the arguments that contain special characters.
+func shellCommand(args ...string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_./:=@%+,-") == "" {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// validateCloudInitStorage checks that the storage can be configured by cloud-init, which can only create a single
+// partition that spans the whole disk.
+func validateCloudInitStorage(storage *osmv1alpha1.Storage) error {
+	if storage == nil {
+		return nil
+	}
+
+	for _, disk := range storage.Disks {
+		if len(disk.Partitions) > 1 {
+			return fmt.Errorf("disk %s has %d partitions, cloud-init only supports a single partition", disk.Device, len(disk.Partitions))
+		}
+		if len(disk.Partitions) == 1 && disk.Partitions[0].SizeMiB != 0 {
+			return fmt.Errorf("partition of disk %s has a size, cloud-init only supports partitions that span the whole disk", disk.Device)
+		}
+	}
+
+	return nil
+}
+
+// cloudInitMounts returns the entries of the cloud-init mounts module for the filesystems.
+func cloudInitMounts(storage *osmv1alpha1.Storage) [][]string {
+	if storage == nil {
+		return nil
+	}
+
+	var mounts [][]string
+	for _, filesystem := range storage.Filesystems {
+		switch {
+		case filesystem.Format == osmv1alpha1.FilesystemFormatSwap:
+			mounts = append(mounts, []string{filesystem.Device, "none", "swap", "sw", "0", "0"})
+		case filesystem.Path != "":
+			mounts = append(mounts, []string{filesystem.Device, filesystem.Path, string(filesystem.Format), mountOptions(filesystem), "0", "2"})
+		}
+	}
+	return mounts
+}
+
+// cloudInitWriteFiles returns the files of the cloud-init write_files module, which are rendered together with the
+// files of the config.
+func cloudInitWriteFiles(writeFiles []osmv1alpha1.CloudInitWriteFile) []*fileSpec {
+	var files []*fileSpec
+	for _, file := range writeFiles {
+		fSpec := &fileSpec{
+			Path:     file.Path,
+			Content:  file.Content,
+			Encoding: file.Encoding,
+			Append:   file.Append,
+			Defer:    file.Defer,
+		}
+		if file.Permissions != "" {
+			fSpec.Permissions = &file.Permissions
+		}
+		if file.Owner != "" {
+			fSpec.User, fSpec.Group, _ = strings.Cut(file.Owner, ":")
+		}
+		files = append(files, fSpec)
+	}
+	return files
+}
+
+// storageUnits returns the systemd units that mount the filesystems and enable swap.
+func storageUnits(storage *osmv1alpha1.Storage) []*unitSpec {
+	if storage == nil {
+		return nil
+	}
+
+	var units []*unitSpec
+	for _, filesystem := range storage.Filesystems {
+		switch {
+		case filesystem.Format == osmv1alpha1.FilesystemFormatSwap:
+			units = append(units, &unitSpec{
+				Name:    systemdEscapePath(filesystem.Device) + ".swap",
+				Enable:  true,
+				Content: fmt.Sprintf("[Swap]\nWhat=%s\n\n[Install]\nWantedBy=swap.target\n", filesystem.Device),
+			})
+		case filesystem.Path != "":
+			units = append(units, &unitSpec{
+				Name:   systemdEscapePath(filesystem.Path) + ".mount",
+				Enable: true,
+				Content: fmt.Sprintf("[Unit]\nBefore=local-fs.target\n\n[Mount]\nWhat=%s\nWhere=%s\nType=%s\nOptions=%s\n\n[Install]\nRequiredBy=local-fs.target\n",
+					filesystem.Device, filesystem.Path, filesystem.Format, mountOptions(filesystem)),
+			})
+		}
+	}
+	return units
+}
+
+func mountOptions(filesystem osmv1alpha1.Filesystem) string {
+	if len(filesystem.MountOptions) == 0 {
+		return "defaults"
+	}
+	return strings.Join(filesystem.MountOptions, ",")
+}
+
+// systemdEscapePath escapes a path like systemd-escape --path does, e.g. /var/lib/containerd becomes
+// var-lib-containerd.
+func systemdEscapePath(p string) string {
+	p = strings.Trim(path.Clean(p), "/")
+	if p == "" {
+		return "-"
+	}
+
+	var escaped strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c == '/':
+			escaped.WriteByte('-')
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == ':', c == '.' && i > 0:
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "\\x%02x", c)
+		}
+	}
+	return escaped.String()
+}
+
+func usersAndGroups(config *osmv1alpha1.OSCConfig) ([]*userSpec, []*groupSpec) {
+	var users []*userSpec
+	for _, user := range config.Users {
+		users = append(users, &userSpec{
+			Name:              user.Name,
+			UID:               user.UID,
+			Groups:            user.Groups,
+			Shell:             user.Shell,
+			Sudo:              user.Sudo,
+			SSHAuthorizedKeys: deduplicateSSHKeys(user.SSHAuthorizedKeys),
+		})
+	}
+
+	var groups []*groupSpec
+	for _, group := range config.Groups {
+		groups = append(groups, &groupSpec{
+			Name: group.Name,
+			GID:  group.GID,
+		})
+	}
+
+	return users, groups
+}
+
+// sudoersFiles returns a sudoers file for each user with sudo rules.
+func sudoersFiles(users []osmv1alpha1.User) []*fileSpec {
+	var files []*fileSpec
+	for _, user := range users {
+		if len(user.Sudo) == 0 {
+			continue
+		}
+
+		var content strings.Builder
+		for _, rule := range user.Sudo {
+			fmt.Fprintf(&content, "%s %s\n", user.Name, rule)
+		}
+
+		permissions := "0440"
+		files = append(files, &fileSpec{
+			Path:        "/etc/sudoers.d/" + user.Name,
+			Content:     content.String(),
+			Permissions: &permissions,
+		})
+	}
+	return files
+}
+
 func getUserDataTemplate(p osmv1alpha1.ProvisioningUtility, mdName, cloudProvider string) string {
 	if p == osmv1alpha1.ProvisioningUtilityIgnition {
 		return ignitionTemplate
@@ -200,9 +503,36 @@ func getUserDataTemplate(p osmv1alpha1.ProvisioningUtility, mdName, cloudProvide
 type fileSpec struct {
 	Path        string
 	Content     string
+	Source      string
+	SourceHash  *hashSpec
 	Encoding    string
 	Permissions *string
 	Name        string
+	User        string
+	Group       string
+	Append      bool
+	Defer       bool
+}
+
+type directorySpec struct {
+	Path        string
+	Permissions *string
+	User        string
+	Group       string
+}
+
+// hashSpec is the hash that a remote file is verified against.
+type hashSpec struct {
+	Function string
+	Sum      string
+}
+
+type linkSpec struct {
+	Path   string
+	Target string
+	Hard   bool
+	User   string
+	Group  string
 }
 
 type unitSpec struct {
@@ -218,6 +548,20 @@ type dropInSpec struct {
 	Content string
 }
 
+type userSpec struct {
+	Name              string
+	UID               *int64
+	Groups            []string
+	Shell             string
+	Sudo              []string
+	SSHAuthorizedKeys []string
+}
+
+type groupSpec struct {
+	Name string
+	GID  *int64
+}
+
 var cloudInitTemplate = `#cloud-config
 {{- /* Hostname is configured only for the bootstrap configuration */}}
 {{- if eq .ConfigurationType "bootstrap" -}}
@@ -240,10 +584,60 @@ ssh_authorized_keys:
 - '{{ $key }}'
 {{ end -}}
 
+{{- if .Groups }}
+groups:
+{{- range $_, $group := .Groups }}
+- '{{ replace "'" "''" $group.Name }}'
+{{- end }}
+{{ end -}}
+
+{{- if .Users }}
+{{- /* The default user has to be listed explicitly, otherwise cloud-init doesn't create it */}}
+users:
+- default
+{{- range $_, $user := .Users }}
+- name: '{{ replace "'" "''" $user.Name }}'
+{{- with $user.UID }}
+  uid: {{ . }}
+{{- end }}
+{{- with $user.Groups }}
+  groups:
+{{- range $_, $group := . }}
+  - '{{ replace "'" "''" $group }}'
+{{- end }}
+{{- end }}
+{{- with $user.Shell }}
+  shell: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- with $user.Sudo }}
+  sudo:
+{{- range $_, $rule := . }}
+  - '{{ replace "'" "''" $rule }}'
+{{- end }}
+{{- end }}
+  lock_passwd: true
+{{- with $user.SSHAuthorizedKeys }}
+  ssh_authorized_keys:
+{{- range $_, $key := . }}
+  - '{{ replace "'" "''" $key }}'
+{{- end }}
+{{- end }}
+{{- end }}
+{{ end -}}
+
 write_files:
 {{- range $_, $file := .Files }}
-- path: '{{ $file.Path }}'
+- path: '{{ replace "'" "''" $file.Path }}'
   permissions: '{{or $file.Permissions 0644}}'
+{{- if or $file.User $file.Group }}
+  owner: '{{ replace "'" "''" (or $file.User "root") }}:{{ replace "'" "''" (or $file.Group "root") }}'
+{{- end }}
+{{- if $file.Append }}
+  append: true
+{{- end }}
+{{- if $file.Defer }}
+  defer: true
+{{- end }}
 {{- if $file.Encoding }}
   encoding: '{{ $file.Encoding }}'
 {{- end }}
@@ -296,8 +690,11 @@ bootcmd:
 {{- end }}
 {{ end }}
 
-{{- if .CloudInitModules.RunCMD }}
+{{- if or .Commands .CloudInitModules.RunCMD }}
 runcmd:
+{{- range $_, $cmd := .Commands }}
+- '{{ replace "'" "''" $cmd }}'
+{{- end }}
 {{- range $_, $val := .CloudInitModules.RunCMD }}
 - {{ $val -}}
 {{ end }}
@@ -323,16 +720,107 @@ yum_repos:
 {{- if .CloudInitModules.YumRepoDir }}
 yum_repo_dir: {{ .CloudInitModules.YumRepoDir }}
 {{- end }}
+
+{{- with .CloudInitModules.Apt }}
+apt:
+{{ toYaml . | indent 2 }}
+{{- end }}
+
+{{- with .CloudInitModules.NTP }}
+ntp:
+{{ toYaml . | indent 2 }}
+{{- end }}
+
+{{- with .CloudInitModules.Timezone }}
+timezone: {{ toYaml . }}
+{{- end }}
+
+{{- with .CloudInitModules.CACerts }}
+ca_certs:
+{{ toYaml . | indent 2 }}
+{{- end }}
+
+{{- with .CloudInitModules.PowerState }}
+power_state:
+{{ toYaml . | indent 2 }}
+{{- end }}
+
+{{- with .CloudInitModules.PhoneHome }}
+phone_home:
+{{ toYaml . | indent 2 }}
+{{- end }}
+{{- end }}
+
+{{- with .Storage }}
+{{- with .Disks }}
+disk_setup:
+{{- range $_, $disk := . }}
+  '{{ replace "'" "''" $disk.Device }}':
+    table_type: gpt
+    layout: {{ if $disk.Partitions }}true{{ else }}false{{ end }}
+    overwrite: {{ $disk.WipeTable }}
+{{- end }}
+{{ end }}
+{{- with .Filesystems }}
+fs_setup:
+{{- range $_, $filesystem := . }}
+- device: '{{ replace "'" "''" $filesystem.Device }}'
+  filesystem: '{{ $filesystem.Format }}'
+{{- with $filesystem.Label }}
+  label: '{{ replace "'" "''" . }}'
+{{- end }}
+  overwrite: {{ $filesystem.WipeFilesystem }}
+{{- end }}
+{{ end }}
+{{- end }}
+
+{{- with .Mounts }}
+mounts:
+{{- range $_, $mount := . }}
+- [{{ range $i, $field := $mount }}{{ if $i }}, {{ end }}'{{ replace "'" "''" $field }}'{{ end }}]
+{{- end }}
 {{- end }}`
 
 var ignitionTemplate = `passwd:
-{{- if ne (len .UserSSHKeys) 0 }}
+{{- if or (ne (len .UserSSHKeys) 0) .Users }}
   users:
+{{- if ne (len .UserSSHKeys) 0 }}
     - name: core
       ssh_authorized_keys:
         {{range .UserSSHKeys}}- {{.}}
         {{end}}
 {{- end }}
+{{- range $_, $user := .Users }}
+    - name: '{{ replace "'" "''" $user.Name }}'
+{{- with $user.UID }}
+      uid: {{ . }}
+{{- end }}
+{{- with $user.Groups }}
+      groups:
+{{- range $_, $group := . }}
+        - '{{ replace "'" "''" $group }}'
+{{- end }}
+{{- end }}
+{{- with $user.Shell }}
+      shell: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- with $user.SSHAuthorizedKeys }}
+      ssh_authorized_keys:
+{{- range $_, $key := . }}
+        - '{{ replace "'" "''" $key }}'
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Groups }}
+  groups:
+{{- range $_, $group := . }}
+    - name: '{{ replace "'" "''" $group.Name }}'
+{{- with $group.GID }}
+      gid: {{ . }}
+{{- end }}
+{{- end }}
+{{- end }}
 storage:
   files:
 {{- /* Hostname is configured only for the bootstrap configuration */}}
@@ -348,13 +836,102 @@ storage:
 {{ end }}
 {{ end }}
 {{- range $_, $file := .Files }}
-  - path: '{{ $file.Path }}'
+  - path: '{{ replace "'" "''" $file.Path }}'
     mode: {{or $file.Permissions 0644}}
     filesystem: root
+{{- with $file.User }}
+    user:
+      name: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- with $file.Group }}
+    group:
+      name: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- if $file.Append }}
+    append: true
+{{- end }}
     contents:
+{{- if $file.Source }}
+        remote:
+          url: '{{ replace "'" "''" $file.Source }}'
+{{- with $file.SourceHash }}
+          verification:
+            hash:
+              function: {{ .Function }}
+              sum: {{ .Sum }}
+{{- end }}
+{{- else }}
         inline: |
 {{ $file.Content | indent 10 }}
 {{- end }}
+{{- end }}
+{{- with .Directories }}
+  directories:
+{{- range $_, $directory := . }}
+  - path: '{{ replace "'" "''" $directory.Path }}'
+    mode: {{or $directory.Permissions 0755}}
+    filesystem: root
+{{- with $directory.User }}
+    user:
+      name: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- with $directory.Group }}
+    group:
+      name: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Links }}
+  links:
+{{- range $_, $link := . }}
+  - path: '{{ replace "'" "''" $link.Path }}'
+    target: '{{ replace "'" "''" $link.Target }}'
+    hard: {{ $link.Hard }}
+    filesystem: root
+{{- with $link.User }}
+    user:
+      name: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- with $link.Group }}
+    group:
+      name: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Storage }}
+{{- with .Disks }}
+  disks:
+{{- range $_, $disk := . }}
+  - device: '{{ replace "'" "''" $disk.Device }}'
+    wipe_table: {{ $disk.WipeTable }}
+{{- with $disk.Partitions }}
+    partitions:
+{{- range $_, $partition := . }}
+    - number: {{ $partition.Number }}
+{{- with $partition.Label }}
+      label: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- if $partition.SizeMiB }}
+      size: {{ $partition.SizeMiB }}MiB
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- with .Filesystems }}
+  filesystems:
+{{- range $i, $filesystem := . }}
+  - name: 'storage-{{ $i }}'
+    mount:
+      device: '{{ replace "'" "''" $filesystem.Device }}'
+      format: {{ $filesystem.Format }}
+      wipe_filesystem: {{ $filesystem.WipeFilesystem }}
+{{- with $filesystem.Label }}
+      label: '{{ replace "'" "''" . }}'
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
 systemd:
   units:
 {{- range $_, $unit := .Units }}
@@ -30,6 +30,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 )
 
 func TestDefaultCloudConfigGenerator_Generate(t *testing.T) {
@@ -770,6 +771,596 @@ yum_repos:
 
 yum_repo_dir: /store/custom/yum.repos.d`),
 		},
+		{
+			name: "generated cloud-init users and groups for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						UserSSHKeys: []string{"ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQDR3"},
+						Users: []osmv1alpha1.User{
+							{
+								Name:              "operator",
+								UID:               ptr.To[int64](1500),
+								Groups:            []string{"operators", "adm"},
+								Shell:             "/bin/bash",
+								Sudo:              []string{"ALL=(ALL) NOPASSWD:ALL"},
+								SSHAuthorizedKeys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOperator operator's key"},
+							},
+						},
+						Groups: []osmv1alpha1.Group{
+							{Name: "operators", GID: ptr.To[int64](1500)},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+- 'ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQDR3'
+
+groups:
+- 'operators'
+
+users:
+- default
+- name: 'operator'
+  uid: 1500
+  groups:
+  - 'operators'
+  - 'adm'
+  shell: '/bin/bash'
+  sudo:
+  - 'ALL=(ALL) NOPASSWD:ALL'
+  lock_passwd: true
+  ssh_authorized_keys:
+  - 'ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOperator operator''s key'
+write_files:`),
+		},
+		{
+			name: "generated ignition users and groups for flatcar",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName: "flatcar",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					OSVersion: "2605.22.1",
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Users: []osmv1alpha1.User{
+							{
+								Name:              "operator",
+								UID:               ptr.To[int64](1500),
+								Groups:            []string{"operators"},
+								Shell:             "/bin/bash",
+								Sudo:              []string{"ALL=(ALL) NOPASSWD:ALL"},
+								SSHAuthorizedKeys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOperator operator's key"},
+							},
+						},
+						Groups: []osmv1alpha1.Group{
+							{Name: "operators", GID: ptr.To[int64](1500)},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+				},
+			},
+			expectedCloudConfig: []byte(`{"ignition":{"config":{},"security":{"tls":{}},"timeouts":{},"version":"2.3.0"},"networkd":{},"passwd":{"groups":[{"gid":1500,"name":"operators"}],"users":[{"groups":["operators"],"name":"operator","sshAuthorizedKeys":["ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOperator operator's key"],"shell":"/bin/bash","uid":1500}]},"storage":{"files":[{"filesystem":"root","path":"/etc/sudoers.d/operator","contents":{"source":"data:,operator%20ALL%3D(ALL)%20NOPASSWD%3AALL%0A","verification":{}},"mode":288}]},"systemd":{}}`),
+		},
+		{
+			name: "generated cloud-init file ownership, directories and links for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Files: []osmv1alpha1.File{
+							{
+								Path:        "/etc/environment",
+								Permissions: 644,
+								WritePolicy: osmv1alpha1.FileWritePolicyAppend,
+								Content: osmv1alpha1.FileContent{
+									Inline: &osmv1alpha1.FileContentInline{Data: "FOO=bar"},
+								},
+							},
+							{
+								Path:        "/opt/operator/motd",
+								Permissions: 640,
+								Owner:       "operator",
+								Group:       "operators",
+								Content: osmv1alpha1.FileContent{
+									Inline: &osmv1alpha1.FileContentInline{Data: "welcome"},
+								},
+							},
+						},
+						Directories: []osmv1alpha1.Directory{
+							{Path: "/opt/operator", Permissions: 750, Owner: "operator", Group: "operators"},
+						},
+						Links: []osmv1alpha1.Link{
+							{Path: "/usr/local/bin/crictl", Target: "/opt/bin/crictl"},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+- path: '/etc/environment'
+  permissions: '0644'
+  append: true
+  content: |-
+    FOO=bar
+
+- path: '/opt/operator/motd'
+  permissions: '0640'
+  owner: 'operator:operators'
+  defer: true
+  content: |-
+    welcome
+
+runcmd:
+- 'install -d -m 0750 -o operator -g operators /opt/operator'
+- 'mkdir -p /usr/local/bin'
+- 'ln -sfn /opt/bin/crictl /usr/local/bin/crictl'
+`),
+		},
+		{
+			name: "generated ignition file ownership, directories and links for flatcar",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName: "flatcar",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					OSVersion: "2605.22.1",
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Files: []osmv1alpha1.File{
+							{
+								Path:        "/etc/environment",
+								Permissions: 644,
+								WritePolicy: osmv1alpha1.FileWritePolicyAppend,
+								Content: osmv1alpha1.FileContent{
+									Inline: &osmv1alpha1.FileContentInline{Data: "FOO=bar"},
+								},
+							},
+							{
+								Path:        "/opt/operator/motd",
+								Permissions: 640,
+								Owner:       "operator",
+								Group:       "operators",
+								Content: osmv1alpha1.FileContent{
+									Inline: &osmv1alpha1.FileContentInline{Data: "welcome"},
+								},
+							},
+						},
+						Directories: []osmv1alpha1.Directory{
+							{Path: "/opt/operator", Permissions: 750, Owner: "operator", Group: "operators"},
+						},
+						Links: []osmv1alpha1.Link{
+							{Path: "/usr/local/bin/crictl", Target: "/opt/bin/crictl"},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+				},
+			},
+			expectedCloudConfig: []byte(`{"ignition":{"config":{},"security":{"tls":{}},"timeouts":{},"version":"2.3.0"},"networkd":{},"passwd":{},"storage":{"directories":[{"filesystem":"root","group":{"name":"operators"},"path":"/opt/operator","user":{"name":"operator"},"mode":488}],"files":[{"filesystem":"root","path":"/etc/environment","append":true,"contents":{"source":"data:,FOO%3Dbar%0A","verification":{}},"mode":420},{"filesystem":"root","group":{"name":"operators"},"path":"/opt/operator/motd","user":{"name":"operator"},"contents":{"source":"data:,welcome%0A","verification":{}},"mode":416}],"links":[{"filesystem":"root","path":"/usr/local/bin/crictl","target":"/opt/bin/crictl"}]},"systemd":{}}`),
+		},
+		{
+			name: "generated ignition quotes paths, owners and link targets for flatcar",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName: "flatcar",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					OSVersion: "2605.22.1",
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Files: []osmv1alpha1.File{
+							{
+								Path:        "/opt/it's: here/motd",
+								Permissions: 640,
+								Owner:       "o'neil",
+								Group:       "#admins",
+								Content: osmv1alpha1.FileContent{
+									Inline: &osmv1alpha1.FileContentInline{Data: "welcome"},
+								},
+							},
+						},
+						Directories: []osmv1alpha1.Directory{
+							{Path: "/opt/it's: here", Owner: "o'neil", Group: "#admins"},
+						},
+						Links: []osmv1alpha1.Link{
+							{Path: "/opt/bin/it's", Target: "/opt/it's: here/bin", Owner: "o'neil"},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+				},
+			},
+			expectedCloudConfig: []byte(`{"ignition":{"config":{},"security":{"tls":{}},"timeouts":{},"version":"2.3.0"},"networkd":{},"passwd":{},"storage":{"directories":[{"filesystem":"root","group":{"name":"#admins"},"path":"/opt/it's: here","user":{"name":"o'neil"},"mode":493}],"files":[{"filesystem":"root","group":{"name":"#admins"},"path":"/opt/it's: here/motd","user":{"name":"o'neil"},"contents":{"source":"data:,welcome%0A","verification":{}},"mode":416}],"links":[{"filesystem":"root","path":"/opt/bin/it's","user":{"name":"o'neil"},"target":"/opt/it's: here/bin"}]},"systemd":{}}`),
+		},
+		{
+			name: "generated cloud-init quotes paths and owners for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Files: []osmv1alpha1.File{
+							{
+								Path:  "/opt/it's: here/motd",
+								Owner: "o'neil",
+								Group: "#admins",
+								Content: osmv1alpha1.FileContent{
+									Inline: &osmv1alpha1.FileContentInline{Data: "welcome"},
+								},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+- path: '/opt/it''s: here/motd'
+  permissions: '0'
+  owner: 'o''neil:#admins'
+  defer: true
+  content: |-
+    welcome
+`),
+		},
+		{
+			name: "generated cloud-init storage for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Storage: &osmv1alpha1.Storage{
+							Disks: []osmv1alpha1.Disk{
+								{Device: "/dev/sdb", WipeTable: true, Partitions: []osmv1alpha1.Partition{{Number: 1, Label: "data"}}},
+							},
+							Filesystems: []osmv1alpha1.Filesystem{
+								{Device: "/dev/sdb1", Format: osmv1alpha1.FilesystemFormatXFS, Label: "data", Path: "/var/lib/containerd", MountOptions: []string{"noatime"}},
+								{Device: "/dev/sdc", Format: osmv1alpha1.FilesystemFormatSwap},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+disk_setup:
+  '/dev/sdb':
+    table_type: gpt
+    layout: true
+    overwrite: true
+
+fs_setup:
+- device: '/dev/sdb1'
+  filesystem: 'xfs'
+  label: 'data'
+  overwrite: false
+- device: '/dev/sdc'
+  filesystem: 'swap'
+  overwrite: false
+
+mounts:
+- ['/dev/sdb1', '/var/lib/containerd', 'xfs', 'noatime', '0', '2']
+- ['/dev/sdc', 'none', 'swap', 'sw', '0', '0']`),
+		},
+		{
+			name: "generated ignition storage for flatcar",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName: "flatcar",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					OSVersion: "2605.22.1",
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Storage: &osmv1alpha1.Storage{
+							Disks: []osmv1alpha1.Disk{
+								{Device: "/dev/sdb", WipeTable: true, Partitions: []osmv1alpha1.Partition{{Number: 1, Label: "data", SizeMiB: 10240}}},
+							},
+							Filesystems: []osmv1alpha1.Filesystem{
+								{Device: "/dev/sdb1", Format: osmv1alpha1.FilesystemFormatXFS, Label: "data", Path: "/var/lib/containerd", MountOptions: []string{"noatime"}},
+								{Device: "/dev/sdc", Format: osmv1alpha1.FilesystemFormatSwap},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+				},
+			},
+			expectedCloudConfig: []byte(`{"ignition":{"config":{},"security":{"tls":{}},"timeouts":{},"version":"2.3.0"},"networkd":{},"passwd":{},"storage":{"disks":[{"device":"/dev/sdb","partitions":[{"label":"data","number":1,"sizeMiB":10240}],"wipeTable":true}],"filesystems":[{"mount":{"device":"/dev/sdb1","format":"xfs","label":"data"},"name":"storage-0"},{"mount":{"device":"/dev/sdc","format":"swap"},"name":"storage-1"}]},"systemd":{"units":[{"contents":"[Unit]\nBefore=local-fs.target\n\n[Mount]\nWhat=/dev/sdb1\nWhere=/var/lib/containerd\nType=xfs\nOptions=noatime\n\n[Install]\nRequiredBy=local-fs.target\n","enabled":true,"name":"var-lib-containerd.mount"},{"contents":"[Swap]\nWhat=/dev/sdc\n\n[Install]\nWantedBy=swap.target\n","enabled":true,"name":"dev-sdc.swap"}]}}`),
+		},
+		{
+			name: "generated cloud-init quotes storage devices and labels for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Storage: &osmv1alpha1.Storage{
+							Disks: []osmv1alpha1.Disk{
+								{Device: "/dev/disk/by-id/it's: here"},
+							},
+							Filesystems: []osmv1alpha1.Filesystem{
+								{Device: "/dev/disk/by-id/it's: here", Format: osmv1alpha1.FilesystemFormatExt4, Label: "o'data"},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+disk_setup:
+  '/dev/disk/by-id/it''s: here':
+    table_type: gpt
+    layout: false
+    overwrite: false
+
+fs_setup:
+- device: '/dev/disk/by-id/it''s: here'
+  filesystem: 'ext4'
+  label: 'o''data'
+  overwrite: false
+`),
+		},
+		{
+			name: "generated ignition quotes storage devices and labels for flatcar",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName: "flatcar",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					OSVersion: "2605.22.1",
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Storage: &osmv1alpha1.Storage{
+							Disks: []osmv1alpha1.Disk{
+								{Device: "/dev/disk/by-id/it's: here", Partitions: []osmv1alpha1.Partition{{Number: 1, Label: "o'data"}}},
+							},
+							Filesystems: []osmv1alpha1.Filesystem{
+								{Device: "/dev/disk/by-id/it's: here-part1", Format: osmv1alpha1.FilesystemFormatExt4, Label: "o'data"},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+				},
+			},
+			expectedCloudConfig: []byte(`{"ignition":{"config":{},"security":{"tls":{}},"timeouts":{},"version":"2.3.0"},"networkd":{},"passwd":{},"storage":{"disks":[{"device":"/dev/disk/by-id/it's: here","partitions":[{"label":"o'data","number":1}]}],"filesystems":[{"mount":{"device":"/dev/disk/by-id/it's: here-part1","format":"ext4","label":"o'data"},"name":"storage-0"}]},"systemd":{}}`),
+		},
+		{
+			name: "generated cloud-init apt packages for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Packages: &osmv1alpha1.Packages{
+							Repositories: []osmv1alpha1.PackageRepository{
+								{Name: "docker", URL: "https://download.docker.com/linux/ubuntu", Distribution: "jammy", Components: []string{"stable"}, GPGKeyURL: "https://download.docker.com/linux/ubuntu/gpg"},
+							},
+							Packages: []osmv1alpha1.Package{
+								{Name: "containerd.io", Version: "2.2*", Repository: "docker", Hold: true},
+								{Name: "jq"},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+- path: '/etc/apt/sources.list.d/docker.list'
+  permissions: '0644'
+  content: |-
+    deb [signed-by=/etc/apt/keyrings/docker.gpg] https://download.docker.com/linux/ubuntu jammy stable
+
+runcmd:
+- 'mkdir -p /etc/apt/keyrings'
+- 'curl -fsSL https://download.docker.com/linux/ubuntu/gpg | gpg --dearmor --yes -o /etc/apt/keyrings/docker.gpg'
+- 'apt-get update'
+- 'DEBIAN_FRONTEND=noninteractive apt-get install -y --allow-downgrades --allow-change-held-packages -t jammy ''containerd.io=2.2*'''
+- 'DEBIAN_FRONTEND=noninteractive apt-get install -y --allow-downgrades --allow-change-held-packages jq'
+- 'apt-mark hold containerd.io'
+`),
+		},
+		{
+			name: "generated cloud-init dnf packages for rockylinux",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "rockylinux",
+					OSVersion: "8.5",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Packages: &osmv1alpha1.Packages{
+							Repositories: []osmv1alpha1.PackageRepository{
+								{Name: "docker-ce-stable", URL: "https://download.docker.com/linux/centos/$releasever/$basearch/stable", GPGKeyURL: "https://download.docker.com/linux/centos/gpg"},
+							},
+							Packages: []osmv1alpha1.Package{
+								{Name: "containerd.io", Version: "2.2.*", Repository: "docker-ce-stable", Hold: true},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+- path: '/etc/yum.repos.d/docker-ce-stable.repo'
+  permissions: '0644'
+  content: |-
+    [docker-ce-stable]
+    name=docker-ce-stable
+    baseurl=https://download.docker.com/linux/centos/$releasever/$basearch/stable
+    enabled=1
+    priority=1
+    gpgcheck=1
+    gpgkey=https://download.docker.com/linux/centos/gpg
+
+runcmd:
+- 'dnf install -y ''containerd.io-2.2.*'''
+- 'dnf install -y ''dnf-command(versionlock)'''
+- 'dnf versionlock add containerd.io'
+`),
+		},
+		{
+			name: "generated ignition system extensions for flatcar",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "flatcar",
+					OSVersion: "2605.22.1",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						Packages: &osmv1alpha1.Packages{
+							Repositories: []osmv1alpha1.PackageRepository{
+								{Name: "bakery", URL: "https://extensions.example.com/it's/"},
+							},
+							Packages: []osmv1alpha1.Package{
+								{Name: "kubernetes", Version: "v1.31.0-x86-64", Repository: "bakery", Hash: "sha512-0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a"},
+							},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityIgnition,
+				},
+			},
+			expectedCloudConfig: []byte(`{"ignition":{"config":{},"security":{"tls":{}},"timeouts":{},"version":"2.3.0"},"networkd":{},"passwd":{},"storage":{"files":[{"filesystem":"root","path":"/opt/extensions/kubernetes/kubernetes-v1.31.0-x86-64.raw","contents":{"source":"https://extensions.example.com/it's/kubernetes-v1.31.0-x86-64.raw","verification":{"hash":"sha512-0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a"}},"mode":420}],"links":[{"filesystem":"root","path":"/etc/extensions/kubernetes.raw","target":"/opt/extensions/kubernetes/kubernetes-v1.31.0-x86-64.raw"}]},"systemd":{}}`),
+		},
+		{
+			name: "generated cloud-init modules for ubuntu",
+			osc: &osmv1alpha1.OperatingSystemConfig{
+				Spec: osmv1alpha1.OperatingSystemConfigSpec{
+					OSName:    "ubuntu",
+					OSVersion: "22.04",
+					CloudProvider: osmv1alpha1.CloudProviderSpec{
+						Name: "aws",
+					},
+					ProvisioningConfig: osmv1alpha1.OSCConfig{
+						CloudInitModules: &osmv1alpha1.CloudInitModule{
+							Apt: &osmv1alpha1.CloudInitApt{
+								Primary: []osmv1alpha1.CloudInitAptMirror{{Arches: []string{"default"}, URI: "http://mirror.example.com/ubuntu"}},
+								Sources: map[string]osmv1alpha1.CloudInitAptSource{
+									"internal.list": {Source: "deb http://apt.example.com $RELEASE main # it's internal", KeyID: "F430BBA5"},
+								},
+								HTTPProxy: "http://proxy.example.com:3128",
+							},
+							NTP:      &osmv1alpha1.CloudInitNTP{Enabled: ptr.To(true), NTPClient: "chrony", Servers: []string{"ntp.example.com"}},
+							Timezone: "Europe/Berlin",
+							CACerts: &osmv1alpha1.CloudInitCACerts{
+								Trusted: []string{"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"},
+							},
+							Mounts:     [][]string{{"nfs.example.com:/export", "/mnt/nfs", "nfs", "defaults,_netdev", "0", "0"}},
+							PowerState: &osmv1alpha1.CloudInitPowerState{Mode: "reboot", Delay: "now", Message: "rebooting: kernel update"},
+							WriteFiles: []osmv1alpha1.CloudInitWriteFile{
+								{Path: "/home/operator/.profile", Content: "export EDITOR=vim", Owner: "operator:operator", Permissions: "0600", Defer: true},
+							},
+							PhoneHome: &osmv1alpha1.CloudInitPhoneHome{URL: "https://inventory.example.com/$INSTANCE_ID/", Post: []string{"instance_id", "fqdn"}, Tries: 3},
+						},
+					},
+					ProvisioningUtility: osmv1alpha1.ProvisioningUtilityCloudInit,
+				},
+			},
+			expectedCloudConfig: []byte(`#cloud-config
+
+ssh_pwauth: false
+
+ssh_authorized_keys:
+write_files:
+- path: '/home/operator/.profile'
+  permissions: '0600'
+  owner: 'operator:operator'
+  defer: true
+  content: |-
+    export EDITOR=vim
+
+apt:
+  http_proxy: http://proxy.example.com:3128
+  primary:
+  - arches:
+    - default
+    uri: http://mirror.example.com/ubuntu
+  sources:
+    internal.list:
+      keyid: F430BBA5
+      source: 'deb http://apt.example.com $RELEASE main # it''s internal'
+ntp:
+  enabled: true
+  ntp_client: chrony
+  servers:
+  - ntp.example.com
+timezone: Europe/Berlin
+ca_certs:
+  trusted:
+  - |-
+    -----BEGIN CERTIFICATE-----
+    MIIB
+    -----END CERTIFICATE-----
+power_state:
+  delay: now
+  message: 'rebooting: kernel update'
+  mode: reboot
+phone_home:
+  post:
+  - instance_id
+  - fqdn
+  tries: 3
+  url: https://inventory.example.com/$INSTANCE_ID/
+mounts:
+- ['nfs.example.com:/export', '/mnt/nfs', 'nfs', 'defaults,_netdev', '0', '0']`),
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -939,3 +1530,54 @@ func TestDefaultCloudConfigGenerator_Generate_IgnoresEmptySSHKeys(t *testing.T)
 		t.Fatalf("expected ssh-rsa AAAA2 to appear once in generated cloud config:\n%s", generated)
 	}
 }
+
+func TestDefaultCloudConfigGenerator_Generate_RejectsSizedCloudInitPartitions(t *testing.T) {
+	generator := NewDefaultCloudConfigGenerator("")
+	osSpec := runtime.RawExtension{Raw: []byte(`{"distUpgradeOnBoot":false}`)}
+	md := generateMachineDeployment(t, providerconfig.OperatingSystemUbuntu, "aws", &osSpec)
+
+	_, err := generator.Generate(
+		&osmv1alpha1.OSCConfig{
+			Storage: &osmv1alpha1.Storage{
+				Disks: []osmv1alpha1.Disk{
+					{Device: "/dev/sdb", Partitions: []osmv1alpha1.Partition{{Number: 1, SizeMiB: 1024}}},
+				},
+			},
+		},
+		osmv1alpha1.ProvisioningUtilityCloudInit,
+		osmv1alpha1.OperatingSystemUbuntu,
+		osmv1alpha1.CloudProviderAWS,
+		md,
+		resources.ProvisioningCloudConfig,
+	)
+	if err == nil {
+		t.Fatal("expected an error for a sized partition with cloud-init")
+	}
+}
+
+func TestDefaultCloudConfigGenerator_Generate_RejectsSystemExtensionsWithoutHash(t *testing.T) {
+	generator := NewDefaultCloudConfigGenerator("")
+	osSpec := runtime.RawExtension{Raw: []byte(`{"distUpgradeOnBoot":false}`)}
+	md := generateMachineDeployment(t, providerconfig.OperatingSystemFlatcar, "aws", &osSpec)
+
+	_, err := generator.Generate(
+		&osmv1alpha1.OSCConfig{
+			Packages: &osmv1alpha1.Packages{
+				Repositories: []osmv1alpha1.PackageRepository{
+					{Name: "bakery", URL: "https://extensions.example.com/"},
+				},
+				Packages: []osmv1alpha1.Package{
+					{Name: "kubernetes", Version: "v1.31.0-x86-64", Repository: "bakery"},
+				},
+			},
+		},
+		osmv1alpha1.ProvisioningUtilityIgnition,
+		osmv1alpha1.OperatingSystemFlatcar,
+		osmv1alpha1.CloudProviderAWS,
+		md,
+		resources.ProvisioningCloudConfig,
+	)
+	if err == nil {
+		t.Fatal("expected an error for a system extension without a hash")
+	}
+}
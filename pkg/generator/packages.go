@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The Operating System Manager contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+)
+
+const (
+	aptKeyringsDir     = "/etc/apt/keyrings"
+	flatcarSysextDir   = "/opt/extensions"
+	flatcarSysextLinks = "/etc/extensions"
+)
+
+// systemExtensionHashRegexp matches the hashes that ignition can verify system extension images against.
+var systemExtensionHashRegexp = regexp.MustCompile(`^(sha512)-([a-f0-9]{128})$`)
+
+// packageManagement renders the packages for the package manager of the operating system. It returns the files of
+// the repositories, the links that enable Flatcar system extensions and the commands that install the packages.
+func packageManagement(packages *osmv1alpha1.Packages, operatingSystem osmv1alpha1.OperatingSystem, provisioner osmv1alpha1.ProvisioningUtility) ([]*fileSpec, []*linkSpec, []string, error) {
+	if packages == nil || (len(packages.Repositories) == 0 && len(packages.Packages) == 0) {
+		return nil, nil, nil, nil
+	}
+
+	switch operatingSystem {
+	case osmv1alpha1.OperatingSystemUbuntu:
+		files, commands, err := aptPackages(packages)
+		return files, nil, commands, err
+	case osmv1alpha1.OperatingSystemRHEL, osmv1alpha1.OperatingSystemRockyLinux:
+		files, commands, err := yumPackages(packages, "dnf", "dnf-command(versionlock)")
+		return files, nil, commands, err
+	case osmv1alpha1.OperatingSystemAmazonLinux2:
+		files, commands, err := yumPackages(packages, "yum", "yum-plugin-versionlock")
+		return files, nil, commands, err
+	case osmv1alpha1.OperatingSystemFlatcar:
+		if provisioner != osmv1alpha1.ProvisioningUtilityIgnition {
+			return nil, nil, nil, fmt.Errorf("packages on flatcar require the %s provisioning utility", osmv1alpha1.ProvisioningUtilityIgnition)
+		}
+		files, links, err := flatcarSystemExtensions(packages)
+		return files, links, nil, err
+	default:
+		return nil, nil, nil, fmt.Errorf("packages are not supported with: %s", operatingSystem)
+	}
+}
+
+func aptPackages(packages *osmv1alpha1.Packages) ([]*fileSpec, []string, error) {
+	var (
+		files    []*fileSpec
+		commands []string
+	)
+
+	for _, repository := range packages.Repositories {
+		if repository.Distribution == "" {
+			return nil, nil, fmt.Errorf("apt repository %q requires a distribution", repository.Name)
+		}
+
+		options := ""
+		if repository.GPGKeyURL != "" {
+			keyring := path.Join(aptKeyringsDir, repository.Name+".gpg")
+			options = fmt.Sprintf("[signed-by=%s] ", keyring)
+			commands = append(commands,
+				shellCommand("mkdir", "-p", aptKeyringsDir),
+				shellCommand("curl", "-fsSL", repository.GPGKeyURL)+" | "+shellCommand("gpg", "--dearmor", "--yes", "-o", keyring),
+			)
+		}
+
+		source := strings.Join(append([]string{"deb " + options + repository.URL, repository.Distribution}, repository.Components...), " ")
+		files = append(files, &fileSpec{
+			Path:        path.Join("/etc/apt/sources.list.d", repository.Name+".list"),
+			Content:     source,
+			Permissions: octalPermissions(644),
+		})
+	}
+
+	groups, err := groupPackagesByRepository(packages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var holds []string
+	commands = append(commands, shellCommand("apt-get", "update"))
+	for _, group := range groups {
+		args := []string{"apt-get", "install", "-y", "--allow-downgrades", "--allow-change-held-packages"}
+		// The target release prefers the versions of the distribution of the repository over all other sources.
+		if group.repository != nil {
+			args = append(args, "-t", group.repository.Distribution)
+		}
+		for _, pkg := range group.packages {
+			if pkg.Version != "" {
+				args = append(args, pkg.Name+"="+pkg.Version)
+			} else {
+				args = append(args, pkg.Name)
+			}
+			if pkg.Hold {
+				holds = append(holds, pkg.Name)
+			}
+		}
+		commands = append(commands, "DEBIAN_FRONTEND=noninteractive "+shellCommand(args...))
+	}
+	if len(holds) > 0 {
+		commands = append(commands, shellCommand(append([]string{"apt-mark", "hold"}, holds...)...))
+	}
+
+	return files, commands, nil
+}
+
+func yumPackages(packages *osmv1alpha1.Packages, packageManager, versionlockPackage string) ([]*fileSpec, []string, error) {
+	var (
+		files    []*fileSpec
+		commands []string
+	)
+
+	for _, repository := range packages.Repositories {
+		var repo strings.Builder
+		fmt.Fprintf(&repo, "[%s]\n", repository.Name)
+		fmt.Fprintf(&repo, "name=%s\n", repository.Name)
+		fmt.Fprintf(&repo, "baseurl=%s\n", repository.URL)
+		repo.WriteString("enabled=1\n")
+		// Packages that are pinned to the repository are preferred from it, while the other repositories stay enabled
+		// for their dependencies. Lower priorities take precedence, the default is 99.
+		if slices.ContainsFunc(packages.Packages, func(pkg osmv1alpha1.Package) bool { return pkg.Repository == repository.Name }) {
+			repo.WriteString("priority=1\n")
+		}
+		if repository.GPGKeyURL != "" {
+			repo.WriteString("gpgcheck=1\n")
+			fmt.Fprintf(&repo, "gpgkey=%s\n", repository.GPGKeyURL)
+		} else {
+			repo.WriteString("gpgcheck=0\n")
+		}
+
+		files = append(files, &fileSpec{
+			Path:        path.Join("/etc/yum.repos.d", repository.Name+".repo"),
+			Content:     strings.TrimSuffix(repo.String(), "\n"),
+			Permissions: octalPermissions(644),
+		})
+	}
+
+	// The repositories are pinned through their priority, grouping only validates that they exist.
+	if _, err := groupPackagesByRepository(packages); err != nil {
+		return nil, nil, err
+	}
+
+	var holds []string
+	args := []string{packageManager, "install", "-y"}
+	for _, pkg := range packages.Packages {
+		if pkg.Version != "" {
+			args = append(args, pkg.Name+"-"+pkg.Version)
+		} else {
+			args = append(args, pkg.Name)
+		}
+		if pkg.Hold {
+			holds = append(holds, pkg.Name)
+		}
+	}
+	if len(packages.Packages) > 0 {
+		commands = append(commands, shellCommand(args...))
+	}
+	if len(holds) > 0 {
+		commands = append(commands,
+			shellCommand(packageManager, "install", "-y", versionlockPackage),
+			shellCommand(append([]string{packageManager, "versionlock", "add"}, holds...)...),
+		)
+	}
+
+	return files, commands, nil
+}
+
+// packageGroup are the packages that are installed from the same repository, the repository is nil for packages that
+// are installed from any of the configured repositories.
+type packageGroup struct {
+	repository *osmv1alpha1.PackageRepository
+	packages   []osmv1alpha1.Package
+}
+
+// groupPackagesByRepository groups the packages by their repository in the order in which they appear first.
+func groupPackagesByRepository(packages *osmv1alpha1.Packages) ([]*packageGroup, error) {
+	var groups []*packageGroup
+	byRepository := map[string]*packageGroup{}
+
+	for _, pkg := range packages.Packages {
+		group, ok := byRepository[pkg.Repository]
+		if !ok {
+			group = &packageGroup{}
+			if pkg.Repository != "" {
+				for i := range packages.Repositories {
+					if packages.Repositories[i].Name == pkg.Repository {
+						group.repository = &packages.Repositories[i]
+					}
+				}
+				if group.repository == nil {
+					return nil, fmt.Errorf("repository %q of package %q doesn't exist", pkg.Repository, pkg.Name)
+				}
+			}
+			byRepository[pkg.Repository] = group
+			groups = append(groups, group)
+		}
+		group.packages = append(group.packages, pkg)
+	}
+
+	return groups, nil
+}
+
+// flatcarSystemExtensions downloads the system extension images of the packages and links them into the extensions
+// directory, from where systemd-sysext merges them on boot. The images are pinned by their version and verified against
+// their hash, holds don't apply.
+func flatcarSystemExtensions(packages *osmv1alpha1.Packages) ([]*fileSpec, []*linkSpec, error) {
+	var (
+		files []*fileSpec
+		links []*linkSpec
+	)
+
+	for _, pkg := range packages.Packages {
+		if pkg.Version == "" || pkg.Hash == "" || pkg.Repository == "" {
+			return nil, nil, fmt.Errorf("system extension %q requires a version, a hash and a repository", pkg.Name)
+		}
+		hash := systemExtensionHashRegexp.FindStringSubmatch(pkg.Hash)
+		if hash == nil {
+			return nil, nil, fmt.Errorf("invalid hash %q of system extension %q: must match %s", pkg.Hash, pkg.Name, systemExtensionHashRegexp)
+		}
+
+		var url string
+		for _, repository := range packages.Repositories {
+			if repository.Name == pkg.Repository {
+				url = repository.URL
+			}
+		}
+		if url == "" {
+			return nil, nil, fmt.Errorf("repository %q of system extension %q doesn't exist", pkg.Repository, pkg.Name)
+		}
+
+		image := fmt.Sprintf("%s-%s.raw", pkg.Name, pkg.Version)
+		imagePath := path.Join(flatcarSysextDir, pkg.Name, image)
+		files = append(files, &fileSpec{
+			Path:        imagePath,
+			Source:      strings.TrimSuffix(url, "/") + "/" + image,
+			SourceHash:  &hashSpec{Function: hash[1], Sum: hash[2]},
+			Permissions: octalPermissions(644),
+		})
+		links = append(links, &linkSpec{
+			Path:   path.Join(flatcarSysextLinks, pkg.Name+".raw"),
+			Target: imagePath,
+		})
+	}
+
+	return files, links, nil
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package generator
 
 import (
+	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+
+	"sigs.k8s.io/yaml"
 )
 
 // TxtFuncMap returns an aggregated template function map. Currently (custom functions + sprig)
@@ -27,6 +30,7 @@ func TxtFuncMap() template.FuncMap {
 	funcMap := sprig.TxtFuncMap()
 
 	funcMap["runCMDs"] = runCMDs
+	funcMap["toYaml"] = toYaml
 
 	return funcMap
 }
@@ -41,3 +45,14 @@ func runCMDs(fSpecs []*fileSpec) []string {
 
 	return services
 }
+
+// toYaml encodes the value as YAML, which takes care of escaping. Values that can't be encoded result in an empty
+// string.
+func toYaml(value any) string {
+	encoded, err := yaml.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(string(encoded), "\n")
+}
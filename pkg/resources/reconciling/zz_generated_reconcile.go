@@ -25,6 +25,7 @@ import (
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	osmv1alpha1 "k8c.io/operating-system-manager/pkg/crd/osm/v1alpha1"
+	nodev1 "k8s.io/api/node/v1"
 )
 
 // OperatingSystemProfileReconciler defines an interface to create/update OperatingSystemProfiles.
@@ -100,3 +101,40 @@ func ReconcileOperatingSystemConfigs(ctx context.Context, namedFactories []Named
 
 	return nil
 }
+
+// RuntimeClassReconciler defines an interface to create/update RuntimeClasses.
+type RuntimeClassReconciler = func(existing *nodev1.RuntimeClass) (*nodev1.RuntimeClass, error)
+
+// NamedRuntimeClassReconcilerFactory returns the name of the resource and the corresponding Reconciler function.
+type NamedRuntimeClassReconcilerFactory = func() (name string, reconciler RuntimeClassReconciler)
+
+// RuntimeClassObjectWrapper adds a wrapper so the RuntimeClassReconciler matches ObjectReconciler.
+// This is needed as Go does not support function interface matching.
+func RuntimeClassObjectWrapper(reconciler RuntimeClassReconciler) reconciling.ObjectReconciler {
+	return func(existing ctrlruntimeclient.Object) (ctrlruntimeclient.Object, error) {
+		if existing != nil {
+			return reconciler(existing.(*nodev1.RuntimeClass))
+		}
+		return reconciler(&nodev1.RuntimeClass{})
+	}
+}
+
+// ReconcileRuntimeClasses will create and update the RuntimeClasses coming from the passed RuntimeClassReconciler slice.
+func ReconcileRuntimeClasses(ctx context.Context, namedFactories []NamedRuntimeClassReconcilerFactory, namespace string, client ctrlruntimeclient.Client, objectModifiers ...reconciling.ObjectModifier) error {
+	for _, factory := range namedFactories {
+		name, reconciler := factory()
+		reconcileObject := RuntimeClassObjectWrapper(reconciler)
+		reconcileObject = reconciling.CreateWithNamespace(reconcileObject, namespace)
+		reconcileObject = reconciling.CreateWithName(reconcileObject, name)
+
+		for _, objectModifier := range objectModifiers {
+			reconcileObject = objectModifier(reconcileObject)
+		}
+
+		if err := reconciling.EnsureNamedObject(ctx, types.NamespacedName{Namespace: namespace, Name: name}, reconcileObject, client, &nodev1.RuntimeClass{}, false); err != nil {
+			return fmt.Errorf("failed to ensure RuntimeClass %s/%s: %w", namespace, name, err)
+		}
+	}
+
+	return nil
+}